@@ -1,20 +1,47 @@
 package awsx
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/aws/aws-sdk-go/service/elasticache"
-
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/dax/daxiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/pi/piiface"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/rdsdataservice/rdsdataserviceiface"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/servicediscovery/servicediscoveryiface"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/aws/aws-sdk-go/service/timestreamquery/timestreamqueryiface"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite/timestreamwriteiface"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config is the configuration definition for our AWS services.
@@ -32,18 +59,80 @@ type Config struct {
 	Service      *Services
 	ServiceSts   *Services
 	panicOnErr   bool // Should we panic the app or proceed if we can't publish to CWL
+
+	ServiceEndpoints map[string]string // optional: per-service endpoint overrides keyed by endpoint ID (e.g. "elasticache")
+	S3ForcePathStyle bool              // optional: use path-style addressing for S3 (required by LocalStack and similar)
+	RetryPolicy      *RetryPolicy      // optional: retry and backoff settings for all service calls
+	Clock            Clock             // optional: time source for pollers, waiters and backoff
+	CacheTTL         time.Duration     // optional: how long discovery results are cached, zero disables caching
+	StalePolicy      StalePolicy       // optional: what discovery returns when AWS calls fail but an earlier result exists
+	Regions          []string          // optional: regions queried by the MultiRegion discovery functions
+	FallbackRegion   *string           // optional: region used when none is configured or detected, see SetFallbackRegion
+	MetadataOptions  *MetadataOptions  // optional: timeout, retries and IMDSv2 settings for the EC2 metadata client
+	HTTPClient       *http.Client      // optional: HTTP client for service calls, e.g. to use a proxy
+	Identity         *CallerIdentity   // set by SetSession when ValidateCredentials is enabled
+	Metrics          Metrics           // optional: receives measurements of AWS calls and cache lookups
+	Logger           *log.Logger       // optional: receives warnings, defaults to standard error
+
+	ExternalID        string            // optional: external ID passed when assuming Role with WithRole
+	SourceIdentity    string            // optional: source identity set when assuming Role with WithRole
+	SessionTags       map[string]string // optional: session tags set when assuming Role with WithRole
+	TransitiveTagKeys []string          // optional: session tag keys passed on to roles chained after Role
+	SAMLProvider      string            // optional: ARN of the SAML identity provider used by WithSAML
+
+	cache         *resultCache
+	endpointStore *endpointStore
+	history       *TopologyHistory
+	fuzzyNames    bool
+	regions       *regionConfigs
+	redisEngines  map[string]redisEngine // engine metadata per replication group, see setGroupEngine
+
+	detectedRegion    string
+	noRegionDetection bool
+	fallbackWarned    bool // the fallback region warning was logged, see ResolveRegion
+	metadataClient    *ec2metadata.EC2Metadata
+	validateCreds     bool
+
+	ctx                context.Context // set by WithContext
+	credentialCacheDir *string
+	credHooks          *credentialHooks
+	statusTracker      *statusTracker
+	tracer             trace.Tracer
+	requestHooks       *requestHooks
 }
 
 // Services stores the used client types so I don't have to remember to do that.
+// Clients are held as their SDK interfaces so they can be replaced with mocks, see
+// the awsxmock package.
 type Services struct {
-	Rds *rds.RDS
-	Ec  *elasticache.ElastiCache
+	Rds      rdsiface.RDSAPI
+	Ec       elasticacheiface.ElastiCacheAPI
+	S3       s3iface.S3API
+	Sts      stsiface.STSAPI
+	Ec2      ec2iface.EC2API
+	Cw       cloudwatchiface.CloudWatchAPI
+	Data     rdsdataserviceiface.RDSDataServiceAPI
+	Kinesis  kinesisiface.KinesisAPI
+	Sqs      sqsiface.SQSAPI
+	Sns      snsiface.SNSAPI
+	Events   eventbridgeiface.EventBridgeAPI
+	Ddb      dynamodbiface.DynamoDBAPI
+	Dax      daxiface.DAXAPI
+	Kms      kmsiface.KMSAPI
+	CloudMap servicediscoveryiface.ServiceDiscoveryAPI
+	R53      route53iface.Route53API
+	Elb      elbv2iface.ELBV2API
+	TsWrite  timestreamwriteiface.TimestreamWriteAPI
+	TsQuery  timestreamqueryiface.TimestreamQueryAPI
+	Ecs      ecsiface.ECSAPI
+	Eks      eksiface.EKSAPI
+	Pi       piiface.PIAPI
 }
 
 // NewAWS creates a new Config struct and populates it with an empty provider chain
 func NewAWS() *Config {
 	p := make([]credentials.Provider, 0)
-	return &Config{Providers: p, Service: &Services{}, ServiceSts: &Services{}}
+	return &Config{Providers: p, Service: &Services{}, ServiceSts: &Services{}, ServiceEndpoints: map[string]string{}, cache: newResultCache()}
 }
 
 // WithStatic adds a static credential provider to the provider chain
@@ -63,7 +152,7 @@ func (a *Config) WithStatic() *Config {
 		a.Providers = append(a.Providers, &credentials.StaticProvider{Value: v})
 
 	} else {
-		fmt.Println("No static AWS credebtials found")
+		a.warn("No static AWS credebtials found")
 	}
 
 	return a
@@ -88,7 +177,7 @@ func (a *Config) SetRegion(region string) *Config {
 	if len(region) > 0 {
 		a.Region = region
 	} else {
-		fmt.Println("No region specified in call to SetRegion(region string)")
+		a.warn("No region specified in call to SetRegion(region string)")
 	}
 	return a
 }
@@ -98,7 +187,7 @@ func (a *Config) SetProfile(profile string) *Config {
 	if len(profile) > 0 {
 		a.Profile = profile
 	} else {
-		fmt.Println("No profile specified in call to SetProfile(profile string)")
+		a.warn("No profile specified in call to SetProfile(profile string)")
 	}
 	return a
 }
@@ -108,8 +197,45 @@ func (a *Config) SetEndpoint(endpoint string) *Config {
 	if len(endpoint) > 0 {
 		a.Endpoint = endpoint
 	} else {
-		fmt.Println("No Endpoint specified in all to SetEndpoint(endpoint string)")
+		a.warn("No Endpoint specified in all to SetEndpoint(endpoint string)")
+	}
+	return a
+}
+
+// SetServiceEndpoint sets a custom endpoint for a single service, keyed by the SDK
+// endpoint ID (e.g. elasticache.EndpointsID). It takes precedence over SetEndpoint.
+func (a *Config) SetServiceEndpoint(service, endpoint string) *Config {
+	if len(service) == 0 || len(endpoint) == 0 {
+		a.warn("No service or endpoint specified in call to SetServiceEndpoint(service, endpoint string)")
+		return a
+	}
+	if a.ServiceEndpoints == nil {
+		a.ServiceEndpoints = map[string]string{}
+	}
+	a.ServiceEndpoints[service] = endpoint
+	return a
+}
+
+// WithLocalStack routes all service calls to a LocalStack (or similar emulator) URL such as
+// http://localhost:4566 using static dummy credentials and path-style S3 addressing so that
+// discovery code can be exercised in integration tests without AWS
+func (a *Config) WithLocalStack(baseURL string) *Config {
+	if len(baseURL) == 0 {
+		baseURL = "http://localhost:4566"
+	}
+
+	a.Endpoint = baseURL
+	a.S3ForcePathStyle = true
+	if a.Region == "" {
+		a.Region = "us-east-1"
 	}
+
+	a.Providers = append(a.Providers, &credentials.StaticProvider{Value: credentials.Value{
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		ProviderName:    "LocalStack",
+	}})
+
 	return a
 }
 
@@ -136,43 +262,66 @@ func (a *Config) WithFile() *Config {
 		}
 		a.Providers = append(a.Providers, cfile)
 	} else {
-		a.Providers = append(a.Providers, &credentials.SharedCredentialsProvider{})
+		a.Providers = append(a.Providers, &credentials.SharedCredentialsProvider{Profile: a.Profile})
+	}
+
+	return a
+}
+
+// WithWebIdentity adds the web identity provider to the credential chain, which assumes
+// the role in AWS_ROLE_ARN with the token file in AWS_WEB_IDENTITY_TOKEN_FILE, as set up
+// by IAM roles for service accounts (IRSA) on EKS. It does nothing if they are not set.
+// The STS session is only created when credentials are first needed.
+func (a *Config) WithWebIdentity() *Config {
+	roleARN := os.Getenv("AWS_ROLE_ARN")
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		a.warn("No web identity role or token file found")
+		return a
 	}
 
+	a.Providers = append(a.Providers, newLazyProvider(func() (credentials.Provider, error) {
+		sess, err := a.stsSession(nil)
+		if err != nil {
+			return nil, err
+		}
+		return stscreds.NewWebIdentityRoleProvider(
+			sts.New(sess), roleARN, os.Getenv("AWS_ROLE_SESSION_NAME"), tokenFile,
+		), nil
+	}))
+
 	return a
 }
 
 // WithInstanceRole adds the credentials from the EC2 instance obtained from the
-// metadata service to the provider list.
+// metadata service to the provider list. The metadata client uses IMDSv2 session tokens,
+// falling back to IMDSv1 unless disabled with SetMetadataOptions. The metadata service
+// is not contacted until credentials are retrieved.
 func (a *Config) WithInstanceRole() *Config {
-	lowTimeoutClient := &http.Client{Timeout: 3 * time.Second} // low timeout to ec2 metadata service
+	lowTimeoutClient := &http.Client{Timeout: a.metadataOptions().Timeout} // low timeout to ec2 metadata service
 
 	// RemoteCredProvider for default remote endpoints such as EC2 or ECS IAM Roles
 	def := defaults.Get()
 	def.Config.HTTPClient = lowTimeoutClient
 	a.Providers = append(a.Providers, defaults.RemoteCredProvider(*def.Config, def.Handlers))
 
-	// EC2RoleProvider retrieves credentials from the EC2 service, and keeps track if those credentials are expired
-	sess, err := session.NewSession()
-	if err != nil {
-		fmt.Println("Error on connecting to AWS: ", err)
-		if a.panicOnErr {
-			fmt.Println("panicOnError is enabled so exiting...")
-			os.Exit(1)
+	// EC2RoleProvider retrieves credentials from the EC2 service, and keeps track if those credentials are expired.
+	// The metadata client is only created when credentials are first needed.
+	a.Providers = append(a.Providers, newLazyProvider(func() (credentials.Provider, error) {
+		client, err := a.MetadataClient()
+		if err != nil {
+			return nil, err
 		}
-		return nil
-	}
-	a.Providers = append(a.Providers, &ec2rolecreds.EC2RoleProvider{
-		Client: ec2metadata.New(sess, &aws.Config{
-			HTTPClient: lowTimeoutClient,
-		}),
-		ExpiryWindow: 3,
-	})
+		return &ec2rolecreds.EC2RoleProvider{
+			Client:       client,
+			ExpiryWindow: 3,
+		}, nil
+	}))
 
 	return a
 }
 
-//WithAllProviders provides a chain of credentials for connectivity
+// WithAllProviders provides a chain of credentials for connectivity
 func (a *Config) WithAllProviders() *Config {
 
 	// If the static credentials are provided and who knows why but maybe
@@ -203,39 +352,27 @@ func (a *Config) WithAllProviders() *Config {
 		}
 		a.Providers = append(a.Providers, cfile)
 	} else {
-		a.Providers = append(a.Providers, &credentials.SharedCredentialsProvider{})
+		a.Providers = append(a.Providers, &credentials.SharedCredentialsProvider{Profile: a.Profile})
 	}
 
-	httpTimeout := &http.Client{Timeout: 3 * time.Second} // low timeout to ec2 metadata service
-
-	// RemoteCredProvider for default remote endpoints such as EC2 or ECS IAM Roles
-	def := defaults.Get()
-	def.Config.HTTPClient = httpTimeout
-	a.Providers = append(a.Providers, defaults.RemoteCredProvider(*def.Config, def.Handlers))
-
-	// EC2RoleProvider retrieves credentials from the EC2 service, and keeps track if those credentials are expired
-	sess, err := session.NewSession()
-	if err != nil {
-		fmt.Println("Error on connecting to AWS: ", err)
-		if a.panicOnErr {
-			fmt.Println("panicOnError is enabled so exiting...")
-			os.Exit(1)
-		}
-		return nil
-	}
-	a.Providers = append(a.Providers, &ec2rolecreds.EC2RoleProvider{
-		Client: ec2metadata.New(sess, &aws.Config{
-			HTTPClient: httpTimeout,
-		}),
-		ExpiryWindow: 3,
-	})
-
-	return a
+	// RemoteCredProvider and EC2RoleProvider for EC2 or ECS IAM Roles
+	return a.WithInstanceRole()
 }
 
-// SetSession calls GetSession and sets the session return as a struct param
+// SetSession calls GetSession and sets the session return as a struct param. With
+// ValidateCredentials enabled, the session is only set if the credentials are valid;
+// otherwise the error is logged and the previous session is kept.
 func (a *Config) SetSession() *Config {
+	prev := a.Session
+	noSts := a.Service != nil && a.Service.Sts == nil
 	a.Session = a.GetSession()
+	if err := a.validateSession(); err != nil {
+		a.Session = prev
+		if noSts {
+			// created by WhoAmI with the rejected session
+			a.Service.Sts = nil
+		}
+	}
 	return a
 }
 
@@ -243,30 +380,21 @@ func (a *Config) SetSession() *Config {
 // above functions
 func (a *Config) GetSession() *session.Session {
 	if len(a.Providers) == 0 {
-		fmt.Println("Calling GetSession() without initializing a credential provider using With*() methods.")
+		a.warn("Calling GetSession() without initializing a credential provider using With*() methods.")
 		if a.panicOnErr {
 			panic("No credential providers specified")
 		}
 	}
 
-	Config := defaults.Config()
-
-	if a.Region != "" {
-		Config.WithRegion(a.Region)
-	} else if val, ok := os.LookupEnv("AWS_DEFAULT_REGION"); ok {
-		Config.WithRegion(val)
-	} else {
-		Config.WithRegion("us-east-1")
-	}
-
-	if a.Endpoint != "" {
-		Config.WithEndpoint(a.Endpoint)
+	Config := a.Build()
+	if aws.StringValue(Config.Region) == "" {
+		a.warn("Calling GetSession() without a region configured or detected.")
+		if a.panicOnErr {
+			panic("No region configured or detected")
+		}
+		return nil
 	}
 
-	Config.WithCredentials(
-		credentials.NewChainCredentials(a.Providers),
-	)
-
 	// create new session with config
 	sess, err := session.NewSessionWithOptions(
 		session.Options{
@@ -276,6 +404,98 @@ func (a *Config) GetSession() *session.Session {
 	if err != nil {
 		return nil
 	}
+	a.installHandlers(sess)
 
 	return sess
 }
+
+// GetRegion returns the region that service calls will be made against
+func (a *Config) GetRegion() string {
+	if a.Session != nil && a.Session.Config.Region != nil {
+		return *a.Session.Config.Region
+	}
+	return aws.StringValue(a.Build().Region)
+}
+
+// Build returns the aws.Config described by the Config struct: the region, the
+// global endpoint and the credential chain built with the With*() methods
+func (a *Config) Build() *aws.Config {
+	Config := defaults.Config()
+
+	if region, err := a.ResolveRegion(); err == nil {
+		Config.WithRegion(region)
+	}
+
+	if a.Endpoint != "" {
+		Config.WithEndpoint(a.Endpoint)
+	}
+
+	// the regional STS endpoint exists in every partition, unlike the global endpoint
+	Config.WithSTSRegionalEndpoint(endpoints.RegionalSTSEndpoint)
+
+	if a.S3ForcePathStyle {
+		Config.WithS3ForcePathStyle(true)
+	}
+
+	if a.HTTPClient != nil {
+		Config.WithHTTPClient(a.HTTPClient)
+	}
+
+	if a.RetryPolicy != nil {
+		request.WithRetryer(Config, a.RetryPolicy.NewRetryer())
+	}
+
+	if a.Clock != nil {
+		Config.WithSleepDelay(a.Clock.Sleep)
+	}
+
+	Config.WithCredentials(
+		credentials.NewCredentials(&chainProvider{providers: a.Providers}),
+	)
+
+	return Config
+}
+
+// ClientConfig returns the session along with the per-service aws.Config overrides
+// for the named service so that clients for services awsx does not wrap can reuse
+// the credential chain and endpoint settings:
+//
+//	svc := s3.New(a.ClientConfig(s3.EndpointsID))
+//
+// The provider is never nil: when no session can be created, e.g. without a region,
+// clients created with it fail every call with an error instead of panicking.
+func (a *Config) ClientConfig(service string) (client.ConfigProvider, *aws.Config) {
+	if a.Session == nil {
+		a.SetSession()
+	}
+
+	cfg := aws.NewConfig()
+	if endpoint, ok := a.ServiceEndpoints[service]; ok && endpoint != "" {
+		cfg.WithEndpoint(endpoint)
+	}
+
+	if a.Session == nil {
+		return noSession{}, cfg
+	}
+	return a.Session, cfg
+}
+
+// errNoSession is the error of calls made by clients created without a session
+var errNoSession = errors.New("no aws session, configure a region and credential providers before creating clients")
+
+// noSession is the client.ConfigProvider of ClientConfig when no session could be
+// created. Its clients fail every call with errNoSession before it is signed or sent.
+type noSession struct{}
+
+// ClientConfig returns a client config whose requests fail validation
+func (noSession) ClientConfig(service string, cfgs ...*aws.Config) client.Config {
+	cfg := aws.NewConfig()
+	cfg.MergeIn(cfgs...)
+
+	c := client.Config{Config: cfg}
+	c.Handlers.Validate.PushBackNamed(request.NamedHandler{
+		Name: "awsx.nosession",
+		Fn:   func(r *request.Request) { r.Error = errNoSession },
+	})
+	return c
+}
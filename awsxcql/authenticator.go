@@ -0,0 +1,59 @@
+package awsxcql
+
+import (
+	"github.com/gocql/gocql"
+	"github.com/routebyintuition/awsx"
+)
+
+// Authenticator is a gocql.Authenticator answering the Keyspaces SigV4 challenge with
+// credentials from an awsx Config. Credentials are resolved for every new connection, so
+// rotated credentials are picked up.
+type Authenticator struct {
+	Config *awsx.Config
+}
+
+var (
+	_ gocql.Authenticator = Authenticator{}
+	_ gocql.Authenticator = (*challengeAuthenticator)(nil)
+)
+
+// Challenge starts the SigV4 exchange
+func (a Authenticator) Challenge(req []byte) ([]byte, gocql.Authenticator, error) {
+	return awsx.KeyspacesInitialResponse, &challengeAuthenticator{config: a.Config}, nil
+}
+
+// Success is called when authentication succeeded
+func (a Authenticator) Success(data []byte) error {
+	return nil
+}
+
+// challengeAuthenticator answers the nonce challenge sent after the initial response
+type challengeAuthenticator struct {
+	config *awsx.Config
+}
+
+func (c *challengeAuthenticator) Challenge(req []byte) ([]byte, gocql.Authenticator, error) {
+	resp, err := c.config.KeyspacesChallengeResponse(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, nil, nil
+}
+
+func (c *challengeAuthenticator) Success(data []byte) error {
+	return nil
+}
+
+// NewCluster returns a gocql cluster config for the regional Keyspaces endpoint of the
+// Config with TLS and SigV4 authentication set up. Keyspaces requires LOCAL_QUORUM for
+// writes, which is set as the default consistency.
+func NewCluster(a *awsx.Config) *gocql.ClusterConfig {
+	cluster := gocql.NewCluster(a.KeyspacesEndpoint())
+	cluster.Authenticator = Authenticator{Config: a}
+	cluster.SslOpts = &gocql.SslOptions{
+		Config:                 a.KeyspacesTLSConfig(),
+		EnableHostVerification: true,
+	}
+	cluster.Consistency = gocql.LocalQuorum
+	return cluster
+}
@@ -0,0 +1,10 @@
+// Package awsxcql connects gocql to Amazon Keyspaces with SigV4 authentication using the
+// credential chain of an awsx Config, so no service-specific credentials are needed:
+//
+//	a := awsx.NewAWS().WithAllProviders()
+//	a.SetRegion("us-east-1")
+//
+//	cluster := awsxcql.NewCluster(a)
+//	cluster.Keyspace = "orders"
+//	session, err := cluster.CreateSession()
+package awsxcql
@@ -0,0 +1,184 @@
+package awsxenvoy
+
+import (
+	"github.com/routebyintuition/awsx"
+)
+
+// Type URLs of the exported resources
+const (
+	ClusterType               = "type.googleapis.com/envoy.config.cluster.v3.Cluster"
+	ClusterLoadAssignmentType = "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"
+	upstreamTLSContextType    = "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.UpstreamTlsContext"
+)
+
+// DefaultConnectTimeout is the connect timeout of the exported clusters
+var DefaultConnectTimeout = "5s"
+
+// DefaultCAFile is the CA bundle Envoy verifies the certificates of TLS endpoints with.
+// ElastiCache certificates are issued by Amazon Trust Services, which system bundles trust.
+var DefaultCAFile = "/etc/ssl/certs/ca-certificates.crt"
+
+// sniMatchKey is the endpoint metadata key the transport socket matches select the SNI by
+const sniMatchKey = "sni"
+
+// Cluster is the JSON form of the fields of an envoy.config.cluster.v3.Cluster that
+// awsx sets
+type Cluster struct {
+	Type            string                 `json:"@type,omitempty"`
+	Name            string                 `json:"name"`
+	ClusterType     string                 `json:"type"`
+	ConnectTimeout  string                 `json:"connect_timeout"`
+	DNSLookupFamily string                 `json:"dns_lookup_family,omitempty"`
+	LoadAssignment  *ClusterLoadAssignment `json:"load_assignment,omitempty"`
+	TransportSocket *TransportSocket       `json:"transport_socket,omitempty"`
+
+	TransportSocketMatches []TransportSocketMatch `json:"transport_socket_matches,omitempty"`
+}
+
+// ClusterLoadAssignment is the JSON form of an envoy.config.endpoint.v3.ClusterLoadAssignment
+type ClusterLoadAssignment struct {
+	Type        string                `json:"@type,omitempty"`
+	ClusterName string                `json:"cluster_name"`
+	Endpoints   []LocalityLbEndpoints `json:"endpoints"`
+}
+
+// LocalityLbEndpoints is a group of endpoints of a ClusterLoadAssignment
+type LocalityLbEndpoints struct {
+	LbEndpoints []LbEndpoint `json:"lb_endpoints"`
+}
+
+// LbEndpoint is an upstream host of a ClusterLoadAssignment
+type LbEndpoint struct {
+	Endpoint struct {
+		Address Address `json:"address"`
+	} `json:"endpoint"`
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+// Metadata is the JSON form of an envoy.config.core.v3.Metadata
+type Metadata struct {
+	FilterMetadata map[string]map[string]string `json:"filter_metadata"`
+}
+
+// Address is the JSON form of an envoy.config.core.v3.Address
+type Address struct {
+	SocketAddress SocketAddress `json:"socket_address"`
+}
+
+// SocketAddress is the JSON form of an envoy.config.core.v3.SocketAddress
+type SocketAddress struct {
+	Address   string `json:"address"`
+	PortValue int    `json:"port_value"`
+}
+
+// TransportSocket is the JSON form of an envoy.config.core.v3.TransportSocket
+type TransportSocket struct {
+	Name        string                 `json:"name"`
+	TypedConfig map[string]interface{} `json:"typed_config"`
+}
+
+// TransportSocketMatch is the JSON form of an envoy.config.cluster.v3.Cluster.TransportSocketMatch
+type TransportSocketMatch struct {
+	Name            string            `json:"name"`
+	Match           map[string]string `json:"match"`
+	TransportSocket *TransportSocket  `json:"transport_socket"`
+}
+
+// NewTLSTransportSocket returns a TLS transport socket verifying the upstream certificate
+// against DefaultCAFile and its name against sni
+func NewTLSTransportSocket(sni string) *TransportSocket {
+	return &TransportSocket{
+		Name: "envoy.transport_sockets.tls",
+		TypedConfig: map[string]interface{}{
+			"@type": upstreamTLSContextType,
+			"sni":   sni,
+			"common_tls_context": map[string]interface{}{
+				"validation_context": map[string]interface{}{
+					"trusted_ca": map[string]string{"filename": DefaultCAFile},
+					"match_typed_subject_alt_names": []interface{}{
+						map[string]interface{}{"san_type": "DNS", "matcher": map[string]string{"exact": sni}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// NewCluster returns a STRICT_DNS cluster named name with the endpoints as hosts.
+// Envoy connects with TLS to the endpoints that require it, with the SNI of each
+// endpoint: a cluster of a single host sets it on the transport socket, otherwise every
+// endpoint selects its own transport socket match by metadata.
+func NewCluster(name string, set awsx.EndpointSet) *Cluster {
+	c := &Cluster{
+		Type:            ClusterType,
+		Name:            name,
+		ClusterType:     "STRICT_DNS",
+		ConnectTimeout:  DefaultConnectTimeout,
+		DNSLookupFamily: "V4_PREFERRED",
+		LoadAssignment:  NewClusterLoadAssignment(name, set),
+	}
+	c.LoadAssignment.Type = ""
+
+	hosts := make([]string, 0, len(set))
+	seen := map[string]bool{}
+	for _, e := range set {
+		if e.TLS() && !seen[e.Host()] {
+			seen[e.Host()] = true
+			hosts = append(hosts, e.Host())
+		}
+	}
+	switch {
+	case len(hosts) == 0:
+		return c
+	case len(hosts) == 1 && len(hosts) == len(set):
+		c.TransportSocket = NewTLSTransportSocket(hosts[0])
+		return c
+	}
+
+	for _, h := range hosts {
+		c.TransportSocketMatches = append(c.TransportSocketMatches, TransportSocketMatch{
+			Name:            h,
+			Match:           map[string]string{sniMatchKey: h},
+			TransportSocket: NewTLSTransportSocket(h),
+		})
+	}
+	lbs := c.LoadAssignment.Endpoints[0].LbEndpoints
+	for i, e := range set {
+		if e.TLS() {
+			lbs[i].Metadata = &Metadata{FilterMetadata: map[string]map[string]string{
+				"envoy.transport_socket_match": {sniMatchKey: e.Host()},
+			}}
+		}
+	}
+
+	return c
+}
+
+// NewClusterLoadAssignment returns the endpoints as the load assignment of the cluster
+func NewClusterLoadAssignment(name string, set awsx.EndpointSet) *ClusterLoadAssignment {
+	lbs := make([]LbEndpoint, 0, len(set))
+	for _, e := range set {
+		var lb LbEndpoint
+		lb.Endpoint.Address.SocketAddress = SocketAddress{Address: e.Host(), PortValue: e.Port()}
+		lbs = append(lbs, lb)
+	}
+
+	return &ClusterLoadAssignment{
+		Type:        ClusterLoadAssignmentType,
+		ClusterName: name,
+		Endpoints:   []LocalityLbEndpoints{{LbEndpoints: lbs}},
+	}
+}
+
+// Clusters returns the cluster name for the primary or configuration endpoint of the
+// discovery result and, when it has replicas, the cluster name-replicas for them
+func Clusters(name string, set awsx.EndpointSet) []*Cluster {
+	clusters := make([]*Cluster, 0, 2)
+	if p := set.Primary(); p != nil {
+		clusters = append(clusters, NewCluster(name, awsx.EndpointSet{p}))
+	}
+	if replicas := set.Replicas(); len(replicas) > 0 {
+		clusters = append(clusters, NewCluster(name+"-replicas", replicas))
+	}
+	return clusters
+}
@@ -0,0 +1,12 @@
+// Package awsxenvoy exports awsx discovery results as Envoy v3 Cluster and
+// ClusterLoadAssignment resources in their JSON form, and serves them to Envoy over the
+// REST-JSON xDS protocol, so sidecar proxies can consume awsx discovery directly:
+//
+//	srv := awsxenvoy.NewServer()
+//	srv.Watch("orders-db", a.WatchAurora("orders-cluster", 30*time.Second).Start())
+//	http.Handle("/v3/", srv)
+//
+// Every discovery result becomes a STRICT_DNS cluster with the name of its primary, or
+// configuration endpoint, and a <name>-replicas cluster for its replicas, so Envoy
+// re-resolves the AWS hostnames itself.
+package awsxenvoy
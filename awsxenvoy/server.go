@@ -0,0 +1,170 @@
+package awsxenvoy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/routebyintuition/awsx"
+)
+
+// DiscoveryRequest is the JSON form of the fields of an envoy.service.discovery.v3
+// DiscoveryRequest the Server reads
+type DiscoveryRequest struct {
+	VersionInfo   string   `json:"version_info,omitempty"`
+	ResourceNames []string `json:"resource_names,omitempty"`
+	TypeURL       string   `json:"type_url,omitempty"`
+}
+
+// DiscoveryResponse is the JSON form of an envoy.service.discovery.v3 DiscoveryResponse
+type DiscoveryResponse struct {
+	VersionInfo string        `json:"version_info"`
+	Resources   []interface{} `json:"resources"`
+	TypeURL     string        `json:"type_url"`
+	Nonce       string        `json:"nonce"`
+}
+
+// Server serves the clusters of awsx discovery results to Envoy over REST-JSON xDS, on
+// /v3/discovery:clusters (CDS) and /v3/discovery:endpoints (EDS). Configure Envoy with
+// an api_config_source of api_type REST pointing at it. The version of the resources is
+// a hash of their content, so it stays the same across restarts of the Server as long
+// as the clusters do.
+type Server struct {
+	mu       sync.RWMutex
+	clusters map[string][]*Cluster
+	version  string
+}
+
+// NewServer returns a Server without clusters
+func NewServer() *Server {
+	s := &Server{clusters: map[string][]*Cluster{}}
+	s.version = s.hash()
+	return s
+}
+
+// Set replaces the clusters of the discovery result name, see Clusters
+func (s *Server) Set(name string, set awsx.EndpointSet) {
+	s.mu.Lock()
+	s.clusters[name] = Clusters(name, set)
+	s.version = s.hash()
+	s.mu.Unlock()
+}
+
+// Remove removes the clusters of the discovery result name
+func (s *Server) Remove(name string) {
+	s.mu.Lock()
+	delete(s.clusters, name)
+	s.version = s.hash()
+	s.mu.Unlock()
+}
+
+// Watch updates the clusters of name on every event of the watcher until it is
+// stopped. Events with an error keep the clusters of the last successful discovery.
+func (s *Server) Watch(name string, w *awsx.Watcher) {
+	sub := w.Subscribe(awsx.SubscribeOptions{Buffer: 1, Policy: awsx.Coalesce})
+	go func() {
+		for ev := range sub.C {
+			switch {
+			case ev.Err != nil:
+				// keep serving the last known endpoints
+			case ev.Redis != nil:
+				s.Set(name, ev.Redis.EndpointSet())
+			case ev.Aurora != nil:
+				s.Set(name, ev.Aurora.EndpointSet())
+			}
+		}
+	}()
+}
+
+// Clusters returns every cluster served, sorted by name
+func (s *Server) Clusters() []*Cluster {
+	_, clusters := s.snapshot()
+	return clusters
+}
+
+// snapshot returns the version and the clusters served, sorted by name
+func (s *Server) snapshot() (string, []*Cluster) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version, s.sorted()
+}
+
+// sorted returns the clusters served, sorted by name. The caller must hold the lock.
+func (s *Server) sorted() []*Cluster {
+	clusters := make([]*Cluster, 0, len(s.clusters))
+	for _, cs := range s.clusters {
+		clusters = append(clusters, cs...)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters
+}
+
+// hash returns the SHA-256 of the JSON of the clusters served as the version. The caller
+// must hold the lock.
+func (s *Server) hash() string {
+	data, _ := json.Marshal(s.sorted())
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ServeHTTP answers CDS and EDS discovery requests. A request for the current version
+// is answered with 304 Not Modified.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var typeURL string
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/discovery:clusters"):
+		typeURL = ClusterType
+	case strings.HasSuffix(r.URL.Path, "/discovery:endpoints"):
+		typeURL = ClusterLoadAssignmentType
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "discovery requests must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DiscoveryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, clusters := s.snapshot()
+	if req.VersionInfo == version {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	wanted := map[string]bool{}
+	for _, n := range req.ResourceNames {
+		wanted[n] = true
+	}
+
+	resources := make([]interface{}, 0)
+	for _, c := range clusters {
+		if len(wanted) > 0 && !wanted[c.Name] {
+			continue
+		}
+		if typeURL == ClusterType {
+			resources = append(resources, c)
+		} else {
+			cla := *c.LoadAssignment
+			cla.Type = ClusterLoadAssignmentType
+			resources = append(resources, &cla)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&DiscoveryResponse{
+		VersionInfo: version,
+		Resources:   resources,
+		TypeURL:     typeURL,
+		Nonce:       version,
+	})
+}
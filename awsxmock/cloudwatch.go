@@ -0,0 +1,21 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+// CloudWatch is a mock of cloudwatchiface.CloudWatchAPI
+type CloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+
+	GetMetricDataFunc func(*cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// GetMetricData calls GetMetricDataFunc
+func (m *CloudWatch) GetMetricData(in *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	if m.GetMetricDataFunc == nil {
+		return m.CloudWatchAPI.GetMetricData(in)
+	}
+	return m.GetMetricDataFunc(in)
+}
@@ -0,0 +1,20 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/dax"
+	"github.com/aws/aws-sdk-go/service/dax/daxiface"
+)
+
+// DAX is a mock of daxiface.DAXAPI
+type DAX struct {
+	daxiface.DAXAPI
+	DescribeClustersFunc func(*dax.DescribeClustersInput) (*dax.DescribeClustersOutput, error)
+}
+
+// DescribeClusters calls DescribeClustersFunc
+func (m *DAX) DescribeClusters(in *dax.DescribeClustersInput) (*dax.DescribeClustersOutput, error) {
+	if m.DescribeClustersFunc == nil {
+		return m.DAXAPI.DescribeClusters(in)
+	}
+	return m.DescribeClustersFunc(in)
+}
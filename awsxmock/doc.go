@@ -0,0 +1,15 @@
+// Package awsxmock provides mocks of the AWS service clients used by awsx so that code
+// calling awsx discovery functions can be unit tested without AWS.
+//
+// Each mock embeds the SDK interface and exposes a func field per operation awsx wraps.
+// The *WithContext variants awsx calls during discovery use the func of the operation, so
+// the same mock serves both. Operations without a func set panic when called, which
+// surfaces unexpected calls:
+//
+//	ec := &awsxmock.ElastiCache{
+//		DescribeReplicationGroupsFunc: func(in *elasticache.DescribeReplicationGroupsInput) (*elasticache.DescribeReplicationGroupsOutput, error) {
+//			return &elasticache.DescribeReplicationGroupsOutput{}, nil
+//		},
+//	}
+//	a := awsx.NewAWS().WithECClient(ec)
+package awsxmock
@@ -0,0 +1,20 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// DynamoDB is a mock of dynamodbiface.DynamoDBAPI
+type DynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+	DescribeTableFunc func(*dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error)
+}
+
+// DescribeTable calls DescribeTableFunc
+func (m *DynamoDB) DescribeTable(in *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	if m.DescribeTableFunc == nil {
+		return m.DynamoDBAPI.DescribeTable(in)
+	}
+	return m.DescribeTableFunc(in)
+}
@@ -0,0 +1,30 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// EC2 is a mock of ec2iface.EC2API
+type EC2 struct {
+	ec2iface.EC2API
+
+	DescribeVpcEndpointsPagesFunc func(*ec2.DescribeVpcEndpointsInput, func(*ec2.DescribeVpcEndpointsOutput, bool) bool) error
+	DescribeInstancesPagesFunc    func(*ec2.DescribeInstancesInput, func(*ec2.DescribeInstancesOutput, bool) bool) error
+}
+
+// DescribeVpcEndpointsPages calls DescribeVpcEndpointsPagesFunc
+func (m *EC2) DescribeVpcEndpointsPages(in *ec2.DescribeVpcEndpointsInput, fn func(*ec2.DescribeVpcEndpointsOutput, bool) bool) error {
+	if m.DescribeVpcEndpointsPagesFunc == nil {
+		return m.EC2API.DescribeVpcEndpointsPages(in, fn)
+	}
+	return m.DescribeVpcEndpointsPagesFunc(in, fn)
+}
+
+// DescribeInstancesPages calls DescribeInstancesPagesFunc
+func (m *EC2) DescribeInstancesPages(in *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	if m.DescribeInstancesPagesFunc == nil {
+		return m.EC2API.DescribeInstancesPages(in, fn)
+	}
+	return m.DescribeInstancesPagesFunc(in, fn)
+}
@@ -0,0 +1,30 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+)
+
+// ECS is a mock of ecsiface.ECSAPI
+type ECS struct {
+	ecsiface.ECSAPI
+	DescribeServicesFunc func(*ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error)
+}
+
+// DescribeServices calls DescribeServicesFunc
+func (m *ECS) DescribeServices(in *ecs.DescribeServicesInput) (*ecs.DescribeServicesOutput, error) {
+	if m.DescribeServicesFunc == nil {
+		return m.ECSAPI.DescribeServices(in)
+	}
+	return m.DescribeServicesFunc(in)
+}
+
+// DescribeServicesWithContext calls DescribeServicesFunc, ignoring the context
+func (m *ECS) DescribeServicesWithContext(ctx aws.Context, in *ecs.DescribeServicesInput, opts ...request.Option) (*ecs.DescribeServicesOutput, error) {
+	if m.DescribeServicesFunc == nil {
+		return m.ECSAPI.DescribeServicesWithContext(ctx, in, opts...)
+	}
+	return m.DescribeServicesFunc(in)
+}
@@ -0,0 +1,20 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+)
+
+// EKS is a mock of eksiface.EKSAPI
+type EKS struct {
+	eksiface.EKSAPI
+	DescribeClusterFunc func(*eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error)
+}
+
+// DescribeCluster calls DescribeClusterFunc
+func (m *EKS) DescribeCluster(in *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+	if m.DescribeClusterFunc == nil {
+		return m.EKSAPI.DescribeCluster(in)
+	}
+	return m.DescribeClusterFunc(in)
+}
@@ -0,0 +1,245 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+)
+
+// ElastiCache is a mock of elasticacheiface.ElastiCacheAPI
+type ElastiCache struct {
+	elasticacheiface.ElastiCacheAPI
+
+	DescribeReplicationGroupsFunc                     func(*elasticache.DescribeReplicationGroupsInput) (*elasticache.DescribeReplicationGroupsOutput, error)
+	DescribeCacheClustersFunc                         func(*elasticache.DescribeCacheClustersInput) (*elasticache.DescribeCacheClustersOutput, error)
+	DescribeCacheParametersPagesFunc                  func(*elasticache.DescribeCacheParametersInput, func(*elasticache.DescribeCacheParametersOutput, bool) bool) error
+	DescribeSnapshotsFunc                             func(*elasticache.DescribeSnapshotsInput) (*elasticache.DescribeSnapshotsOutput, error)
+	CopySnapshotFunc                                  func(*elasticache.CopySnapshotInput) (*elasticache.CopySnapshotOutput, error)
+	CreateReplicationGroupFunc                        func(*elasticache.CreateReplicationGroupInput) (*elasticache.CreateReplicationGroupOutput, error)
+	DeleteReplicationGroupFunc                        func(*elasticache.DeleteReplicationGroupInput) (*elasticache.DeleteReplicationGroupOutput, error)
+	WaitUntilReplicationGroupAvailableWithContextFunc func(aws.Context, *elasticache.DescribeReplicationGroupsInput, ...request.WaiterOption) error
+	WaitUntilReplicationGroupDeletedWithContextFunc   func(aws.Context, *elasticache.DescribeReplicationGroupsInput, ...request.WaiterOption) error
+	DescribeReplicationGroupsPagesFunc                func(*elasticache.DescribeReplicationGroupsInput, func(*elasticache.DescribeReplicationGroupsOutput, bool) bool) error
+	DescribeCacheClustersPagesFunc                    func(*elasticache.DescribeCacheClustersInput, func(*elasticache.DescribeCacheClustersOutput, bool) bool) error
+	ListTagsForResourceFunc                           func(*elasticache.ListTagsForResourceInput) (*elasticache.TagListMessage, error)
+	DescribeUsersPagesFunc                            func(*elasticache.DescribeUsersInput, func(*elasticache.DescribeUsersOutput, bool) bool) error
+	DescribeUserGroupsPagesFunc                       func(*elasticache.DescribeUserGroupsInput, func(*elasticache.DescribeUserGroupsOutput, bool) bool) error
+	CreateSnapshotFunc                                func(*elasticache.CreateSnapshotInput) (*elasticache.CreateSnapshotOutput, error)
+	DescribeSnapshotsPagesFunc                        func(*elasticache.DescribeSnapshotsInput, func(*elasticache.DescribeSnapshotsOutput, bool) bool) error
+	CreateReplicationGroupWithContextFunc             func(aws.Context, *elasticache.CreateReplicationGroupInput, ...request.Option) (*elasticache.CreateReplicationGroupOutput, error)
+	DeleteReplicationGroupWithContextFunc             func(aws.Context, *elasticache.DeleteReplicationGroupInput, ...request.Option) (*elasticache.DeleteReplicationGroupOutput, error)
+	IncreaseReplicaCountWithContextFunc               func(aws.Context, *elasticache.IncreaseReplicaCountInput, ...request.Option) (*elasticache.IncreaseReplicaCountOutput, error)
+	DecreaseReplicaCountWithContextFunc               func(aws.Context, *elasticache.DecreaseReplicaCountInput, ...request.Option) (*elasticache.DecreaseReplicaCountOutput, error)
+	TestFailoverWithContextFunc                       func(aws.Context, *elasticache.TestFailoverInput, ...request.Option) (*elasticache.TestFailoverOutput, error)
+	DescribeEventsPagesWithContextFunc                func(aws.Context, *elasticache.DescribeEventsInput, func(*elasticache.DescribeEventsOutput, bool) bool, ...request.Option) error
+	DescribeReservedCacheNodesPagesFunc               func(*elasticache.DescribeReservedCacheNodesInput, func(*elasticache.DescribeReservedCacheNodesOutput, bool) bool) error
+}
+
+// DescribeReplicationGroups calls DescribeReplicationGroupsFunc
+func (m *ElastiCache) DescribeReplicationGroups(in *elasticache.DescribeReplicationGroupsInput) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	if m.DescribeReplicationGroupsFunc == nil {
+		return m.ElastiCacheAPI.DescribeReplicationGroups(in)
+	}
+	return m.DescribeReplicationGroupsFunc(in)
+}
+
+// DescribeReplicationGroupsWithContext calls DescribeReplicationGroupsFunc, ignoring the context
+func (m *ElastiCache) DescribeReplicationGroupsWithContext(ctx aws.Context, in *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	if m.DescribeReplicationGroupsFunc == nil {
+		return m.ElastiCacheAPI.DescribeReplicationGroupsWithContext(ctx, in, opts...)
+	}
+	return m.DescribeReplicationGroupsFunc(in)
+}
+
+// DescribeCacheClusters calls DescribeCacheClustersFunc
+func (m *ElastiCache) DescribeCacheClusters(in *elasticache.DescribeCacheClustersInput) (*elasticache.DescribeCacheClustersOutput, error) {
+	if m.DescribeCacheClustersFunc == nil {
+		return m.ElastiCacheAPI.DescribeCacheClusters(in)
+	}
+	return m.DescribeCacheClustersFunc(in)
+}
+
+// DescribeCacheClustersWithContext calls DescribeCacheClustersFunc, ignoring the context
+func (m *ElastiCache) DescribeCacheClustersWithContext(ctx aws.Context, in *elasticache.DescribeCacheClustersInput, opts ...request.Option) (*elasticache.DescribeCacheClustersOutput, error) {
+	if m.DescribeCacheClustersFunc == nil {
+		return m.ElastiCacheAPI.DescribeCacheClustersWithContext(ctx, in, opts...)
+	}
+	return m.DescribeCacheClustersFunc(in)
+}
+
+// DescribeCacheParametersPages calls DescribeCacheParametersPagesFunc
+func (m *ElastiCache) DescribeCacheParametersPages(in *elasticache.DescribeCacheParametersInput, fn func(*elasticache.DescribeCacheParametersOutput, bool) bool) error {
+	if m.DescribeCacheParametersPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeCacheParametersPages(in, fn)
+	}
+	return m.DescribeCacheParametersPagesFunc(in, fn)
+}
+
+// DescribeSnapshots calls DescribeSnapshotsFunc
+func (m *ElastiCache) DescribeSnapshots(in *elasticache.DescribeSnapshotsInput) (*elasticache.DescribeSnapshotsOutput, error) {
+	if m.DescribeSnapshotsFunc == nil {
+		return m.ElastiCacheAPI.DescribeSnapshots(in)
+	}
+	return m.DescribeSnapshotsFunc(in)
+}
+
+// CopySnapshot calls CopySnapshotFunc
+func (m *ElastiCache) CopySnapshot(in *elasticache.CopySnapshotInput) (*elasticache.CopySnapshotOutput, error) {
+	if m.CopySnapshotFunc == nil {
+		return m.ElastiCacheAPI.CopySnapshot(in)
+	}
+	return m.CopySnapshotFunc(in)
+}
+
+// CreateReplicationGroup calls CreateReplicationGroupFunc
+func (m *ElastiCache) CreateReplicationGroup(in *elasticache.CreateReplicationGroupInput) (*elasticache.CreateReplicationGroupOutput, error) {
+	if m.CreateReplicationGroupFunc == nil {
+		return m.ElastiCacheAPI.CreateReplicationGroup(in)
+	}
+	return m.CreateReplicationGroupFunc(in)
+}
+
+// DeleteReplicationGroup calls DeleteReplicationGroupFunc
+func (m *ElastiCache) DeleteReplicationGroup(in *elasticache.DeleteReplicationGroupInput) (*elasticache.DeleteReplicationGroupOutput, error) {
+	if m.DeleteReplicationGroupFunc == nil {
+		return m.ElastiCacheAPI.DeleteReplicationGroup(in)
+	}
+	return m.DeleteReplicationGroupFunc(in)
+}
+
+// WaitUntilReplicationGroupAvailableWithContext calls WaitUntilReplicationGroupAvailableWithContextFunc
+func (m *ElastiCache) WaitUntilReplicationGroupAvailableWithContext(ctx aws.Context, in *elasticache.DescribeReplicationGroupsInput, opts ...request.WaiterOption) error {
+	if m.WaitUntilReplicationGroupAvailableWithContextFunc == nil {
+		return m.ElastiCacheAPI.WaitUntilReplicationGroupAvailableWithContext(ctx, in, opts...)
+	}
+	return m.WaitUntilReplicationGroupAvailableWithContextFunc(ctx, in, opts...)
+}
+
+// WaitUntilReplicationGroupDeletedWithContext calls WaitUntilReplicationGroupDeletedWithContextFunc
+func (m *ElastiCache) WaitUntilReplicationGroupDeletedWithContext(ctx aws.Context, in *elasticache.DescribeReplicationGroupsInput, opts ...request.WaiterOption) error {
+	if m.WaitUntilReplicationGroupDeletedWithContextFunc == nil {
+		return m.ElastiCacheAPI.WaitUntilReplicationGroupDeletedWithContext(ctx, in, opts...)
+	}
+	return m.WaitUntilReplicationGroupDeletedWithContextFunc(ctx, in, opts...)
+}
+
+// DescribeReplicationGroupsPages calls DescribeReplicationGroupsPagesFunc
+func (m *ElastiCache) DescribeReplicationGroupsPages(in *elasticache.DescribeReplicationGroupsInput, fn func(*elasticache.DescribeReplicationGroupsOutput, bool) bool) error {
+	if m.DescribeReplicationGroupsPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeReplicationGroupsPages(in, fn)
+	}
+	return m.DescribeReplicationGroupsPagesFunc(in, fn)
+}
+
+// DescribeReplicationGroupsPagesWithContext calls DescribeReplicationGroupsPagesFunc, ignoring the context
+func (m *ElastiCache) DescribeReplicationGroupsPagesWithContext(ctx aws.Context, in *elasticache.DescribeReplicationGroupsInput, fn func(*elasticache.DescribeReplicationGroupsOutput, bool) bool, opts ...request.Option) error {
+	if m.DescribeReplicationGroupsPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeReplicationGroupsPagesWithContext(ctx, in, fn, opts...)
+	}
+	return m.DescribeReplicationGroupsPagesFunc(in, fn)
+}
+
+// DescribeCacheClustersPages calls DescribeCacheClustersPagesFunc
+func (m *ElastiCache) DescribeCacheClustersPages(in *elasticache.DescribeCacheClustersInput, fn func(*elasticache.DescribeCacheClustersOutput, bool) bool) error {
+	if m.DescribeCacheClustersPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeCacheClustersPages(in, fn)
+	}
+	return m.DescribeCacheClustersPagesFunc(in, fn)
+}
+
+// ListTagsForResource calls ListTagsForResourceFunc
+func (m *ElastiCache) ListTagsForResource(in *elasticache.ListTagsForResourceInput) (*elasticache.TagListMessage, error) {
+	if m.ListTagsForResourceFunc == nil {
+		return m.ElastiCacheAPI.ListTagsForResource(in)
+	}
+	return m.ListTagsForResourceFunc(in)
+}
+
+// DescribeUsersPages calls DescribeUsersPagesFunc
+func (m *ElastiCache) DescribeUsersPages(in *elasticache.DescribeUsersInput, fn func(*elasticache.DescribeUsersOutput, bool) bool) error {
+	if m.DescribeUsersPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeUsersPages(in, fn)
+	}
+	return m.DescribeUsersPagesFunc(in, fn)
+}
+
+// DescribeUserGroupsPages calls DescribeUserGroupsPagesFunc
+func (m *ElastiCache) DescribeUserGroupsPages(in *elasticache.DescribeUserGroupsInput, fn func(*elasticache.DescribeUserGroupsOutput, bool) bool) error {
+	if m.DescribeUserGroupsPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeUserGroupsPages(in, fn)
+	}
+	return m.DescribeUserGroupsPagesFunc(in, fn)
+}
+
+// CreateSnapshot calls CreateSnapshotFunc
+func (m *ElastiCache) CreateSnapshot(in *elasticache.CreateSnapshotInput) (*elasticache.CreateSnapshotOutput, error) {
+	if m.CreateSnapshotFunc == nil {
+		return m.ElastiCacheAPI.CreateSnapshot(in)
+	}
+	return m.CreateSnapshotFunc(in)
+}
+
+// DescribeSnapshotsPages calls DescribeSnapshotsPagesFunc
+func (m *ElastiCache) DescribeSnapshotsPages(in *elasticache.DescribeSnapshotsInput, fn func(*elasticache.DescribeSnapshotsOutput, bool) bool) error {
+	if m.DescribeSnapshotsPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeSnapshotsPages(in, fn)
+	}
+	return m.DescribeSnapshotsPagesFunc(in, fn)
+}
+
+// CreateReplicationGroupWithContext calls CreateReplicationGroupWithContextFunc
+func (m *ElastiCache) CreateReplicationGroupWithContext(ctx aws.Context, in *elasticache.CreateReplicationGroupInput, opts ...request.Option) (*elasticache.CreateReplicationGroupOutput, error) {
+	if m.CreateReplicationGroupWithContextFunc == nil {
+		return m.ElastiCacheAPI.CreateReplicationGroupWithContext(ctx, in, opts...)
+	}
+	return m.CreateReplicationGroupWithContextFunc(ctx, in, opts...)
+}
+
+// DeleteReplicationGroupWithContext calls DeleteReplicationGroupWithContextFunc
+func (m *ElastiCache) DeleteReplicationGroupWithContext(ctx aws.Context, in *elasticache.DeleteReplicationGroupInput, opts ...request.Option) (*elasticache.DeleteReplicationGroupOutput, error) {
+	if m.DeleteReplicationGroupWithContextFunc == nil {
+		return m.ElastiCacheAPI.DeleteReplicationGroupWithContext(ctx, in, opts...)
+	}
+	return m.DeleteReplicationGroupWithContextFunc(ctx, in, opts...)
+}
+
+// IncreaseReplicaCountWithContext calls IncreaseReplicaCountWithContextFunc
+func (m *ElastiCache) IncreaseReplicaCountWithContext(ctx aws.Context, in *elasticache.IncreaseReplicaCountInput, opts ...request.Option) (*elasticache.IncreaseReplicaCountOutput, error) {
+	if m.IncreaseReplicaCountWithContextFunc == nil {
+		return m.ElastiCacheAPI.IncreaseReplicaCountWithContext(ctx, in, opts...)
+	}
+	return m.IncreaseReplicaCountWithContextFunc(ctx, in, opts...)
+}
+
+// DecreaseReplicaCountWithContext calls DecreaseReplicaCountWithContextFunc
+func (m *ElastiCache) DecreaseReplicaCountWithContext(ctx aws.Context, in *elasticache.DecreaseReplicaCountInput, opts ...request.Option) (*elasticache.DecreaseReplicaCountOutput, error) {
+	if m.DecreaseReplicaCountWithContextFunc == nil {
+		return m.ElastiCacheAPI.DecreaseReplicaCountWithContext(ctx, in, opts...)
+	}
+	return m.DecreaseReplicaCountWithContextFunc(ctx, in, opts...)
+}
+
+// TestFailoverWithContext calls TestFailoverWithContextFunc
+func (m *ElastiCache) TestFailoverWithContext(ctx aws.Context, in *elasticache.TestFailoverInput, opts ...request.Option) (*elasticache.TestFailoverOutput, error) {
+	if m.TestFailoverWithContextFunc == nil {
+		return m.ElastiCacheAPI.TestFailoverWithContext(ctx, in, opts...)
+	}
+	return m.TestFailoverWithContextFunc(ctx, in, opts...)
+}
+
+// DescribeEventsPagesWithContext calls DescribeEventsPagesWithContextFunc
+func (m *ElastiCache) DescribeEventsPagesWithContext(ctx aws.Context, in *elasticache.DescribeEventsInput, fn func(*elasticache.DescribeEventsOutput, bool) bool, opts ...request.Option) error {
+	if m.DescribeEventsPagesWithContextFunc == nil {
+		return m.ElastiCacheAPI.DescribeEventsPagesWithContext(ctx, in, fn, opts...)
+	}
+	return m.DescribeEventsPagesWithContextFunc(ctx, in, fn, opts...)
+}
+
+// DescribeReservedCacheNodesPages calls DescribeReservedCacheNodesPagesFunc
+func (m *ElastiCache) DescribeReservedCacheNodesPages(in *elasticache.DescribeReservedCacheNodesInput, fn func(*elasticache.DescribeReservedCacheNodesOutput, bool) bool) error {
+	if m.DescribeReservedCacheNodesPagesFunc == nil {
+		return m.ElastiCacheAPI.DescribeReservedCacheNodesPages(in, fn)
+	}
+	return m.DescribeReservedCacheNodesPagesFunc(in, fn)
+}
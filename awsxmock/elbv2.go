@@ -0,0 +1,38 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+// ELBV2 is a mock of elbv2iface.ELBV2API
+type ELBV2 struct {
+	elbv2iface.ELBV2API
+	DescribeLoadBalancersFunc  func(*elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error)
+	DescribeListenersPagesFunc func(*elbv2.DescribeListenersInput, func(*elbv2.DescribeListenersOutput, bool) bool) error
+	DescribeTargetHealthFunc   func(*elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error)
+}
+
+// DescribeLoadBalancers calls DescribeLoadBalancersFunc
+func (m *ELBV2) DescribeLoadBalancers(in *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	if m.DescribeLoadBalancersFunc == nil {
+		return m.ELBV2API.DescribeLoadBalancers(in)
+	}
+	return m.DescribeLoadBalancersFunc(in)
+}
+
+// DescribeListenersPages calls DescribeListenersPagesFunc
+func (m *ELBV2) DescribeListenersPages(in *elbv2.DescribeListenersInput, fn func(*elbv2.DescribeListenersOutput, bool) bool) error {
+	if m.DescribeListenersPagesFunc == nil {
+		return m.ELBV2API.DescribeListenersPages(in, fn)
+	}
+	return m.DescribeListenersPagesFunc(in, fn)
+}
+
+// DescribeTargetHealth calls DescribeTargetHealthFunc
+func (m *ELBV2) DescribeTargetHealth(in *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	if m.DescribeTargetHealthFunc == nil {
+		return m.ELBV2API.DescribeTargetHealth(in)
+	}
+	return m.DescribeTargetHealthFunc(in)
+}
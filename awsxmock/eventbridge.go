@@ -0,0 +1,22 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+)
+
+// EventBridge is a mock of eventbridgeiface.EventBridgeAPI
+type EventBridge struct {
+	eventbridgeiface.EventBridgeAPI
+	PutEventsWithContextFunc func(aws.Context, *eventbridge.PutEventsInput, ...request.Option) (*eventbridge.PutEventsOutput, error)
+}
+
+// PutEventsWithContext calls PutEventsWithContextFunc
+func (m *EventBridge) PutEventsWithContext(ctx aws.Context, in *eventbridge.PutEventsInput, opts ...request.Option) (*eventbridge.PutEventsOutput, error) {
+	if m.PutEventsWithContextFunc == nil {
+		return m.EventBridgeAPI.PutEventsWithContext(ctx, in, opts...)
+	}
+	return m.PutEventsWithContextFunc(ctx, in, opts...)
+}
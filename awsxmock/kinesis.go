@@ -0,0 +1,31 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+)
+
+// Kinesis is a mock of kinesisiface.KinesisAPI
+type Kinesis struct {
+	kinesisiface.KinesisAPI
+	DescribeStreamSummaryFunc func(*kinesis.DescribeStreamSummaryInput) (*kinesis.DescribeStreamSummaryOutput, error)
+	PutRecordsWithContextFunc func(aws.Context, *kinesis.PutRecordsInput, ...request.Option) (*kinesis.PutRecordsOutput, error)
+}
+
+// DescribeStreamSummary calls DescribeStreamSummaryFunc
+func (m *Kinesis) DescribeStreamSummary(in *kinesis.DescribeStreamSummaryInput) (*kinesis.DescribeStreamSummaryOutput, error) {
+	if m.DescribeStreamSummaryFunc == nil {
+		return m.KinesisAPI.DescribeStreamSummary(in)
+	}
+	return m.DescribeStreamSummaryFunc(in)
+}
+
+// PutRecordsWithContext calls PutRecordsWithContextFunc
+func (m *Kinesis) PutRecordsWithContext(ctx aws.Context, in *kinesis.PutRecordsInput, opts ...request.Option) (*kinesis.PutRecordsOutput, error) {
+	if m.PutRecordsWithContextFunc == nil {
+		return m.KinesisAPI.PutRecordsWithContext(ctx, in, opts...)
+	}
+	return m.PutRecordsWithContextFunc(ctx, in, opts...)
+}
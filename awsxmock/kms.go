@@ -0,0 +1,40 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// KMS is a mock of kmsiface.KMSAPI
+type KMS struct {
+	kmsiface.KMSAPI
+	EncryptWithContextFunc         func(aws.Context, *kms.EncryptInput, ...request.Option) (*kms.EncryptOutput, error)
+	DecryptWithContextFunc         func(aws.Context, *kms.DecryptInput, ...request.Option) (*kms.DecryptOutput, error)
+	GenerateDataKeyWithContextFunc func(aws.Context, *kms.GenerateDataKeyInput, ...request.Option) (*kms.GenerateDataKeyOutput, error)
+}
+
+// EncryptWithContext calls EncryptWithContextFunc
+func (m *KMS) EncryptWithContext(ctx aws.Context, in *kms.EncryptInput, opts ...request.Option) (*kms.EncryptOutput, error) {
+	if m.EncryptWithContextFunc == nil {
+		return m.KMSAPI.EncryptWithContext(ctx, in, opts...)
+	}
+	return m.EncryptWithContextFunc(ctx, in, opts...)
+}
+
+// DecryptWithContext calls DecryptWithContextFunc
+func (m *KMS) DecryptWithContext(ctx aws.Context, in *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	if m.DecryptWithContextFunc == nil {
+		return m.KMSAPI.DecryptWithContext(ctx, in, opts...)
+	}
+	return m.DecryptWithContextFunc(ctx, in, opts...)
+}
+
+// GenerateDataKeyWithContext calls GenerateDataKeyWithContextFunc
+func (m *KMS) GenerateDataKeyWithContext(ctx aws.Context, in *kms.GenerateDataKeyInput, opts ...request.Option) (*kms.GenerateDataKeyOutput, error) {
+	if m.GenerateDataKeyWithContextFunc == nil {
+		return m.KMSAPI.GenerateDataKeyWithContext(ctx, in, opts...)
+	}
+	return m.GenerateDataKeyWithContextFunc(ctx, in, opts...)
+}
@@ -0,0 +1,29 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/pi"
+	"github.com/aws/aws-sdk-go/service/pi/piiface"
+)
+
+// PI is a mock of piiface.PIAPI
+type PI struct {
+	piiface.PIAPI
+	DescribeDimensionKeysFunc func(*pi.DescribeDimensionKeysInput) (*pi.DescribeDimensionKeysOutput, error)
+	GetResourceMetricsFunc    func(*pi.GetResourceMetricsInput) (*pi.GetResourceMetricsOutput, error)
+}
+
+// DescribeDimensionKeys calls DescribeDimensionKeysFunc
+func (m *PI) DescribeDimensionKeys(in *pi.DescribeDimensionKeysInput) (*pi.DescribeDimensionKeysOutput, error) {
+	if m.DescribeDimensionKeysFunc == nil {
+		return m.PIAPI.DescribeDimensionKeys(in)
+	}
+	return m.DescribeDimensionKeysFunc(in)
+}
+
+// GetResourceMetrics calls GetResourceMetricsFunc
+func (m *PI) GetResourceMetrics(in *pi.GetResourceMetricsInput) (*pi.GetResourceMetricsOutput, error) {
+	if m.GetResourceMetricsFunc == nil {
+		return m.PIAPI.GetResourceMetrics(in)
+	}
+	return m.GetResourceMetricsFunc(in)
+}
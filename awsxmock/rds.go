@@ -0,0 +1,199 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// RDS is a mock of rdsiface.RDSAPI
+type RDS struct {
+	rdsiface.RDSAPI
+
+	DescribeDBClustersFunc                      func(*rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error)
+	DescribeDBInstancesFunc                     func(*rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error)
+	DescribeDBClustersPagesFunc                 func(*rds.DescribeDBClustersInput, func(*rds.DescribeDBClustersOutput, bool) bool) error
+	DescribeDBInstancesPagesFunc                func(*rds.DescribeDBInstancesInput, func(*rds.DescribeDBInstancesOutput, bool) bool) error
+	CreateDBClusterWithContextFunc              func(aws.Context, *rds.CreateDBClusterInput, ...request.Option) (*rds.CreateDBClusterOutput, error)
+	CreateDBInstanceWithContextFunc             func(aws.Context, *rds.CreateDBInstanceInput, ...request.Option) (*rds.CreateDBInstanceOutput, error)
+	FailoverDBClusterWithContextFunc            func(aws.Context, *rds.FailoverDBClusterInput, ...request.Option) (*rds.FailoverDBClusterOutput, error)
+	DeleteDBInstanceWithContextFunc             func(aws.Context, *rds.DeleteDBInstanceInput, ...request.Option) (*rds.DeleteDBInstanceOutput, error)
+	DeleteDBClusterWithContextFunc              func(aws.Context, *rds.DeleteDBClusterInput, ...request.Option) (*rds.DeleteDBClusterOutput, error)
+	WaitUntilDBInstanceAvailableWithContextFunc func(aws.Context, *rds.DescribeDBInstancesInput, ...request.WaiterOption) error
+	WaitUntilDBInstanceDeletedWithContextFunc   func(aws.Context, *rds.DescribeDBInstancesInput, ...request.WaiterOption) error
+	DescribeBlueGreenDeploymentsFunc            func(*rds.DescribeBlueGreenDeploymentsInput) (*rds.DescribeBlueGreenDeploymentsOutput, error)
+	DescribeGlobalClustersFunc                  func(*rds.DescribeGlobalClustersInput) (*rds.DescribeGlobalClustersOutput, error)
+	DescribeEventsPagesWithContextFunc          func(aws.Context, *rds.DescribeEventsInput, func(*rds.DescribeEventsOutput, bool) bool, ...request.Option) error
+	DescribeDBParametersPagesFunc               func(*rds.DescribeDBParametersInput, func(*rds.DescribeDBParametersOutput, bool) bool) error
+	DescribeDBClusterParametersPagesFunc        func(*rds.DescribeDBClusterParametersInput, func(*rds.DescribeDBClusterParametersOutput, bool) bool) error
+	DescribeReservedDBInstancesPagesFunc        func(*rds.DescribeReservedDBInstancesInput, func(*rds.DescribeReservedDBInstancesOutput, bool) bool) error
+}
+
+// DescribeDBClusters calls DescribeDBClustersFunc
+func (m *RDS) DescribeDBClusters(in *rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error) {
+	if m.DescribeDBClustersFunc == nil {
+		return m.RDSAPI.DescribeDBClusters(in)
+	}
+	return m.DescribeDBClustersFunc(in)
+}
+
+// DescribeDBClustersWithContext calls DescribeDBClustersFunc, ignoring the context
+func (m *RDS) DescribeDBClustersWithContext(ctx aws.Context, in *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+	if m.DescribeDBClustersFunc == nil {
+		return m.RDSAPI.DescribeDBClustersWithContext(ctx, in, opts...)
+	}
+	return m.DescribeDBClustersFunc(in)
+}
+
+// DescribeDBInstances calls DescribeDBInstancesFunc
+func (m *RDS) DescribeDBInstances(in *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	if m.DescribeDBInstancesFunc == nil {
+		return m.RDSAPI.DescribeDBInstances(in)
+	}
+	return m.DescribeDBInstancesFunc(in)
+}
+
+// DescribeDBInstancesWithContext calls DescribeDBInstancesFunc, ignoring the context
+func (m *RDS) DescribeDBInstancesWithContext(ctx aws.Context, in *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+	if m.DescribeDBInstancesFunc == nil {
+		return m.RDSAPI.DescribeDBInstancesWithContext(ctx, in, opts...)
+	}
+	return m.DescribeDBInstancesFunc(in)
+}
+
+// DescribeDBClustersPages calls DescribeDBClustersPagesFunc
+func (m *RDS) DescribeDBClustersPages(in *rds.DescribeDBClustersInput, fn func(*rds.DescribeDBClustersOutput, bool) bool) error {
+	if m.DescribeDBClustersPagesFunc == nil {
+		return m.RDSAPI.DescribeDBClustersPages(in, fn)
+	}
+	return m.DescribeDBClustersPagesFunc(in, fn)
+}
+
+// DescribeDBInstancesPages calls DescribeDBInstancesPagesFunc
+func (m *RDS) DescribeDBInstancesPages(in *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool) error {
+	if m.DescribeDBInstancesPagesFunc == nil {
+		return m.RDSAPI.DescribeDBInstancesPages(in, fn)
+	}
+	return m.DescribeDBInstancesPagesFunc(in, fn)
+}
+
+// DescribeDBInstancesPagesWithContext calls DescribeDBInstancesPagesFunc, ignoring the context
+func (m *RDS) DescribeDBInstancesPagesWithContext(ctx aws.Context, in *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool, opts ...request.Option) error {
+	if m.DescribeDBInstancesPagesFunc == nil {
+		return m.RDSAPI.DescribeDBInstancesPagesWithContext(ctx, in, fn, opts...)
+	}
+	return m.DescribeDBInstancesPagesFunc(in, fn)
+}
+
+// CreateDBClusterWithContext calls CreateDBClusterWithContextFunc
+func (m *RDS) CreateDBClusterWithContext(ctx aws.Context, in *rds.CreateDBClusterInput, opts ...request.Option) (*rds.CreateDBClusterOutput, error) {
+	if m.CreateDBClusterWithContextFunc == nil {
+		return m.RDSAPI.CreateDBClusterWithContext(ctx, in, opts...)
+	}
+	return m.CreateDBClusterWithContextFunc(ctx, in, opts...)
+}
+
+// CreateDBInstanceWithContext calls CreateDBInstanceWithContextFunc
+func (m *RDS) CreateDBInstanceWithContext(ctx aws.Context, in *rds.CreateDBInstanceInput, opts ...request.Option) (*rds.CreateDBInstanceOutput, error) {
+	if m.CreateDBInstanceWithContextFunc == nil {
+		return m.RDSAPI.CreateDBInstanceWithContext(ctx, in, opts...)
+	}
+	return m.CreateDBInstanceWithContextFunc(ctx, in, opts...)
+}
+
+// FailoverDBClusterWithContext calls FailoverDBClusterWithContextFunc
+func (m *RDS) FailoverDBClusterWithContext(ctx aws.Context, in *rds.FailoverDBClusterInput, opts ...request.Option) (*rds.FailoverDBClusterOutput, error) {
+	if m.FailoverDBClusterWithContextFunc == nil {
+		return m.RDSAPI.FailoverDBClusterWithContext(ctx, in, opts...)
+	}
+	return m.FailoverDBClusterWithContextFunc(ctx, in, opts...)
+}
+
+// DeleteDBInstanceWithContext calls DeleteDBInstanceWithContextFunc
+func (m *RDS) DeleteDBInstanceWithContext(ctx aws.Context, in *rds.DeleteDBInstanceInput, opts ...request.Option) (*rds.DeleteDBInstanceOutput, error) {
+	if m.DeleteDBInstanceWithContextFunc == nil {
+		return m.RDSAPI.DeleteDBInstanceWithContext(ctx, in, opts...)
+	}
+	return m.DeleteDBInstanceWithContextFunc(ctx, in, opts...)
+}
+
+// DeleteDBClusterWithContext calls DeleteDBClusterWithContextFunc
+func (m *RDS) DeleteDBClusterWithContext(ctx aws.Context, in *rds.DeleteDBClusterInput, opts ...request.Option) (*rds.DeleteDBClusterOutput, error) {
+	if m.DeleteDBClusterWithContextFunc == nil {
+		return m.RDSAPI.DeleteDBClusterWithContext(ctx, in, opts...)
+	}
+	return m.DeleteDBClusterWithContextFunc(ctx, in, opts...)
+}
+
+// WaitUntilDBInstanceAvailableWithContext calls WaitUntilDBInstanceAvailableWithContextFunc
+func (m *RDS) WaitUntilDBInstanceAvailableWithContext(ctx aws.Context, in *rds.DescribeDBInstancesInput, opts ...request.WaiterOption) error {
+	if m.WaitUntilDBInstanceAvailableWithContextFunc == nil {
+		return m.RDSAPI.WaitUntilDBInstanceAvailableWithContext(ctx, in, opts...)
+	}
+	return m.WaitUntilDBInstanceAvailableWithContextFunc(ctx, in, opts...)
+}
+
+// WaitUntilDBInstanceDeletedWithContext calls WaitUntilDBInstanceDeletedWithContextFunc
+func (m *RDS) WaitUntilDBInstanceDeletedWithContext(ctx aws.Context, in *rds.DescribeDBInstancesInput, opts ...request.WaiterOption) error {
+	if m.WaitUntilDBInstanceDeletedWithContextFunc == nil {
+		return m.RDSAPI.WaitUntilDBInstanceDeletedWithContext(ctx, in, opts...)
+	}
+	return m.WaitUntilDBInstanceDeletedWithContextFunc(ctx, in, opts...)
+}
+
+// DescribeEventsPagesWithContext calls DescribeEventsPagesWithContextFunc
+func (m *RDS) DescribeEventsPagesWithContext(ctx aws.Context, in *rds.DescribeEventsInput, fn func(*rds.DescribeEventsOutput, bool) bool, opts ...request.Option) error {
+	if m.DescribeEventsPagesWithContextFunc == nil {
+		return m.RDSAPI.DescribeEventsPagesWithContext(ctx, in, fn, opts...)
+	}
+	return m.DescribeEventsPagesWithContextFunc(ctx, in, fn, opts...)
+}
+
+// DescribeBlueGreenDeployments calls DescribeBlueGreenDeploymentsFunc
+func (m *RDS) DescribeBlueGreenDeployments(in *rds.DescribeBlueGreenDeploymentsInput) (*rds.DescribeBlueGreenDeploymentsOutput, error) {
+	if m.DescribeBlueGreenDeploymentsFunc == nil {
+		return m.RDSAPI.DescribeBlueGreenDeployments(in)
+	}
+	return m.DescribeBlueGreenDeploymentsFunc(in)
+}
+
+// DescribeBlueGreenDeploymentsWithContext calls DescribeBlueGreenDeploymentsFunc, ignoring the context
+func (m *RDS) DescribeBlueGreenDeploymentsWithContext(ctx aws.Context, in *rds.DescribeBlueGreenDeploymentsInput, opts ...request.Option) (*rds.DescribeBlueGreenDeploymentsOutput, error) {
+	if m.DescribeBlueGreenDeploymentsFunc == nil {
+		return m.RDSAPI.DescribeBlueGreenDeploymentsWithContext(ctx, in, opts...)
+	}
+	return m.DescribeBlueGreenDeploymentsFunc(in)
+}
+
+// DescribeGlobalClusters calls DescribeGlobalClustersFunc
+func (m *RDS) DescribeGlobalClusters(in *rds.DescribeGlobalClustersInput) (*rds.DescribeGlobalClustersOutput, error) {
+	if m.DescribeGlobalClustersFunc == nil {
+		return m.RDSAPI.DescribeGlobalClusters(in)
+	}
+	return m.DescribeGlobalClustersFunc(in)
+}
+
+// DescribeDBParametersPages calls DescribeDBParametersPagesFunc
+func (m *RDS) DescribeDBParametersPages(in *rds.DescribeDBParametersInput, fn func(*rds.DescribeDBParametersOutput, bool) bool) error {
+	if m.DescribeDBParametersPagesFunc == nil {
+		return m.RDSAPI.DescribeDBParametersPages(in, fn)
+	}
+	return m.DescribeDBParametersPagesFunc(in, fn)
+}
+
+// DescribeDBClusterParametersPages calls DescribeDBClusterParametersPagesFunc
+func (m *RDS) DescribeDBClusterParametersPages(in *rds.DescribeDBClusterParametersInput, fn func(*rds.DescribeDBClusterParametersOutput, bool) bool) error {
+	if m.DescribeDBClusterParametersPagesFunc == nil {
+		return m.RDSAPI.DescribeDBClusterParametersPages(in, fn)
+	}
+	return m.DescribeDBClusterParametersPagesFunc(in, fn)
+}
+
+// DescribeReservedDBInstancesPages calls DescribeReservedDBInstancesPagesFunc
+func (m *RDS) DescribeReservedDBInstancesPages(in *rds.DescribeReservedDBInstancesInput, fn func(*rds.DescribeReservedDBInstancesOutput, bool) bool) error {
+	if m.DescribeReservedDBInstancesPagesFunc == nil {
+		return m.RDSAPI.DescribeReservedDBInstancesPages(in, fn)
+	}
+	return m.DescribeReservedDBInstancesPagesFunc(in, fn)
+}
@@ -0,0 +1,58 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// Route53 is a mock of route53iface.Route53API
+type Route53 struct {
+	route53iface.Route53API
+	ListHostedZonesByNameFunc               func(*route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error)
+	ListResourceRecordSetsPagesFunc         func(*route53.ListResourceRecordSetsInput, func(*route53.ListResourceRecordSetsOutput, bool) bool) error
+	ChangeResourceRecordSetsWithContextFunc func(aws.Context, *route53.ChangeResourceRecordSetsInput, ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error)
+	GetChangeFunc                           func(*route53.GetChangeInput) (*route53.GetChangeOutput, error)
+	GetHealthCheckStatusFunc                func(*route53.GetHealthCheckStatusInput) (*route53.GetHealthCheckStatusOutput, error)
+}
+
+// ListHostedZonesByName calls ListHostedZonesByNameFunc
+func (m *Route53) ListHostedZonesByName(in *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+	if m.ListHostedZonesByNameFunc == nil {
+		return m.Route53API.ListHostedZonesByName(in)
+	}
+	return m.ListHostedZonesByNameFunc(in)
+}
+
+// ListResourceRecordSetsPages calls ListResourceRecordSetsPagesFunc
+func (m *Route53) ListResourceRecordSetsPages(in *route53.ListResourceRecordSetsInput, fn func(*route53.ListResourceRecordSetsOutput, bool) bool) error {
+	if m.ListResourceRecordSetsPagesFunc == nil {
+		return m.Route53API.ListResourceRecordSetsPages(in, fn)
+	}
+	return m.ListResourceRecordSetsPagesFunc(in, fn)
+}
+
+// ChangeResourceRecordSetsWithContext calls ChangeResourceRecordSetsWithContextFunc
+func (m *Route53) ChangeResourceRecordSetsWithContext(ctx aws.Context, in *route53.ChangeResourceRecordSetsInput, opts ...request.Option) (*route53.ChangeResourceRecordSetsOutput, error) {
+	if m.ChangeResourceRecordSetsWithContextFunc == nil {
+		return m.Route53API.ChangeResourceRecordSetsWithContext(ctx, in, opts...)
+	}
+	return m.ChangeResourceRecordSetsWithContextFunc(ctx, in, opts...)
+}
+
+// GetChange calls GetChangeFunc
+func (m *Route53) GetChange(in *route53.GetChangeInput) (*route53.GetChangeOutput, error) {
+	if m.GetChangeFunc == nil {
+		return m.Route53API.GetChange(in)
+	}
+	return m.GetChangeFunc(in)
+}
+
+// GetHealthCheckStatus calls GetHealthCheckStatusFunc
+func (m *Route53) GetHealthCheckStatus(in *route53.GetHealthCheckStatusInput) (*route53.GetHealthCheckStatusOutput, error) {
+	if m.GetHealthCheckStatusFunc == nil {
+		return m.Route53API.GetHealthCheckStatus(in)
+	}
+	return m.GetHealthCheckStatusFunc(in)
+}
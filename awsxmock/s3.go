@@ -0,0 +1,30 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// S3 is a mock of s3iface.S3API
+type S3 struct {
+	s3iface.S3API
+
+	GetBucketLocationFunc func(*s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error)
+	GetBucketPolicyFunc   func(*s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error)
+}
+
+// GetBucketLocation calls GetBucketLocationFunc
+func (m *S3) GetBucketLocation(in *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+	if m.GetBucketLocationFunc == nil {
+		return m.S3API.GetBucketLocation(in)
+	}
+	return m.GetBucketLocationFunc(in)
+}
+
+// GetBucketPolicy calls GetBucketPolicyFunc
+func (m *S3) GetBucketPolicy(in *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+	if m.GetBucketPolicyFunc == nil {
+		return m.S3API.GetBucketPolicy(in)
+	}
+	return m.GetBucketPolicyFunc(in)
+}
@@ -0,0 +1,64 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/servicediscovery/servicediscoveryiface"
+)
+
+// CloudMap is a mock of servicediscoveryiface.ServiceDiscoveryAPI
+type CloudMap struct {
+	servicediscoveryiface.ServiceDiscoveryAPI
+	DiscoverInstancesFunc func(*servicediscovery.DiscoverInstancesInput) (*servicediscovery.DiscoverInstancesOutput, error)
+	GetServiceFunc        func(*servicediscovery.GetServiceInput) (*servicediscovery.GetServiceOutput, error)
+	GetNamespaceFunc      func(*servicediscovery.GetNamespaceInput) (*servicediscovery.GetNamespaceOutput, error)
+}
+
+// DiscoverInstances calls DiscoverInstancesFunc
+func (m *CloudMap) DiscoverInstances(in *servicediscovery.DiscoverInstancesInput) (*servicediscovery.DiscoverInstancesOutput, error) {
+	if m.DiscoverInstancesFunc == nil {
+		return m.ServiceDiscoveryAPI.DiscoverInstances(in)
+	}
+	return m.DiscoverInstancesFunc(in)
+}
+
+// DiscoverInstancesWithContext calls DiscoverInstancesFunc, ignoring the context
+func (m *CloudMap) DiscoverInstancesWithContext(ctx aws.Context, in *servicediscovery.DiscoverInstancesInput, opts ...request.Option) (*servicediscovery.DiscoverInstancesOutput, error) {
+	if m.DiscoverInstancesFunc == nil {
+		return m.ServiceDiscoveryAPI.DiscoverInstancesWithContext(ctx, in, opts...)
+	}
+	return m.DiscoverInstancesFunc(in)
+}
+
+// GetService calls GetServiceFunc
+func (m *CloudMap) GetService(in *servicediscovery.GetServiceInput) (*servicediscovery.GetServiceOutput, error) {
+	if m.GetServiceFunc == nil {
+		return m.ServiceDiscoveryAPI.GetService(in)
+	}
+	return m.GetServiceFunc(in)
+}
+
+// GetServiceWithContext calls GetServiceFunc, ignoring the context
+func (m *CloudMap) GetServiceWithContext(ctx aws.Context, in *servicediscovery.GetServiceInput, opts ...request.Option) (*servicediscovery.GetServiceOutput, error) {
+	if m.GetServiceFunc == nil {
+		return m.ServiceDiscoveryAPI.GetServiceWithContext(ctx, in, opts...)
+	}
+	return m.GetServiceFunc(in)
+}
+
+// GetNamespace calls GetNamespaceFunc
+func (m *CloudMap) GetNamespace(in *servicediscovery.GetNamespaceInput) (*servicediscovery.GetNamespaceOutput, error) {
+	if m.GetNamespaceFunc == nil {
+		return m.ServiceDiscoveryAPI.GetNamespace(in)
+	}
+	return m.GetNamespaceFunc(in)
+}
+
+// GetNamespaceWithContext calls GetNamespaceFunc, ignoring the context
+func (m *CloudMap) GetNamespaceWithContext(ctx aws.Context, in *servicediscovery.GetNamespaceInput, opts ...request.Option) (*servicediscovery.GetNamespaceOutput, error) {
+	if m.GetNamespaceFunc == nil {
+		return m.ServiceDiscoveryAPI.GetNamespaceWithContext(ctx, in, opts...)
+	}
+	return m.GetNamespaceFunc(in)
+}
@@ -0,0 +1,31 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+// SNS is a mock of snsiface.SNSAPI
+type SNS struct {
+	snsiface.SNSAPI
+	ListTopicsPagesFunc    func(*sns.ListTopicsInput, func(*sns.ListTopicsOutput, bool) bool) error
+	PublishWithContextFunc func(aws.Context, *sns.PublishInput, ...request.Option) (*sns.PublishOutput, error)
+}
+
+// ListTopicsPages calls ListTopicsPagesFunc
+func (m *SNS) ListTopicsPages(in *sns.ListTopicsInput, fn func(*sns.ListTopicsOutput, bool) bool) error {
+	if m.ListTopicsPagesFunc == nil {
+		return m.SNSAPI.ListTopicsPages(in, fn)
+	}
+	return m.ListTopicsPagesFunc(in, fn)
+}
+
+// PublishWithContext calls PublishWithContextFunc
+func (m *SNS) PublishWithContext(ctx aws.Context, in *sns.PublishInput, opts ...request.Option) (*sns.PublishOutput, error) {
+	if m.PublishWithContextFunc == nil {
+		return m.SNSAPI.PublishWithContext(ctx, in, opts...)
+	}
+	return m.PublishWithContextFunc(ctx, in, opts...)
+}
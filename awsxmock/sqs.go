@@ -0,0 +1,29 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+// SQS is a mock of sqsiface.SQSAPI
+type SQS struct {
+	sqsiface.SQSAPI
+	GetQueueUrlFunc        func(*sqs.GetQueueUrlInput) (*sqs.GetQueueUrlOutput, error)
+	GetQueueAttributesFunc func(*sqs.GetQueueAttributesInput) (*sqs.GetQueueAttributesOutput, error)
+}
+
+// GetQueueUrl calls GetQueueUrlFunc
+func (m *SQS) GetQueueUrl(in *sqs.GetQueueUrlInput) (*sqs.GetQueueUrlOutput, error) {
+	if m.GetQueueUrlFunc == nil {
+		return m.SQSAPI.GetQueueUrl(in)
+	}
+	return m.GetQueueUrlFunc(in)
+}
+
+// GetQueueAttributes calls GetQueueAttributesFunc
+func (m *SQS) GetQueueAttributes(in *sqs.GetQueueAttributesInput) (*sqs.GetQueueAttributesOutput, error) {
+	if m.GetQueueAttributesFunc == nil {
+		return m.SQSAPI.GetQueueAttributes(in)
+	}
+	return m.GetQueueAttributesFunc(in)
+}
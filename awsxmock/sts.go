@@ -0,0 +1,31 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// STS is a mock of stsiface.STSAPI
+type STS struct {
+	stsiface.STSAPI
+
+	GetCallerIdentityFunc        func(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+	GetCallerIdentityRequestFunc func(*sts.GetCallerIdentityInput) (*request.Request, *sts.GetCallerIdentityOutput)
+}
+
+// GetCallerIdentity calls GetCallerIdentityFunc
+func (m *STS) GetCallerIdentity(in *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	if m.GetCallerIdentityFunc == nil {
+		return m.STSAPI.GetCallerIdentity(in)
+	}
+	return m.GetCallerIdentityFunc(in)
+}
+
+// GetCallerIdentityRequest calls GetCallerIdentityRequestFunc
+func (m *STS) GetCallerIdentityRequest(in *sts.GetCallerIdentityInput) (*request.Request, *sts.GetCallerIdentityOutput) {
+	if m.GetCallerIdentityRequestFunc == nil {
+		return m.STSAPI.GetCallerIdentityRequest(in)
+	}
+	return m.GetCallerIdentityRequestFunc(in)
+}
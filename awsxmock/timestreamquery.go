@@ -0,0 +1,20 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/service/timestreamquery"
+	"github.com/aws/aws-sdk-go/service/timestreamquery/timestreamqueryiface"
+)
+
+// TimestreamQuery is a mock of timestreamqueryiface.TimestreamQueryAPI
+type TimestreamQuery struct {
+	timestreamqueryiface.TimestreamQueryAPI
+	DescribeEndpointsFunc func(*timestreamquery.DescribeEndpointsInput) (*timestreamquery.DescribeEndpointsOutput, error)
+}
+
+// DescribeEndpoints calls DescribeEndpointsFunc
+func (m *TimestreamQuery) DescribeEndpoints(in *timestreamquery.DescribeEndpointsInput) (*timestreamquery.DescribeEndpointsOutput, error) {
+	if m.DescribeEndpointsFunc == nil {
+		return m.TimestreamQueryAPI.DescribeEndpoints(in)
+	}
+	return m.DescribeEndpointsFunc(in)
+}
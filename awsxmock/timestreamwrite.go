@@ -0,0 +1,31 @@
+package awsxmock
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite/timestreamwriteiface"
+)
+
+// TimestreamWrite is a mock of timestreamwriteiface.TimestreamWriteAPI
+type TimestreamWrite struct {
+	timestreamwriteiface.TimestreamWriteAPI
+	DescribeEndpointsFunc       func(*timestreamwrite.DescribeEndpointsInput) (*timestreamwrite.DescribeEndpointsOutput, error)
+	WriteRecordsWithContextFunc func(aws.Context, *timestreamwrite.WriteRecordsInput, ...request.Option) (*timestreamwrite.WriteRecordsOutput, error)
+}
+
+// DescribeEndpoints calls DescribeEndpointsFunc
+func (m *TimestreamWrite) DescribeEndpoints(in *timestreamwrite.DescribeEndpointsInput) (*timestreamwrite.DescribeEndpointsOutput, error) {
+	if m.DescribeEndpointsFunc == nil {
+		return m.TimestreamWriteAPI.DescribeEndpoints(in)
+	}
+	return m.DescribeEndpointsFunc(in)
+}
+
+// WriteRecordsWithContext calls WriteRecordsWithContextFunc
+func (m *TimestreamWrite) WriteRecordsWithContext(ctx aws.Context, in *timestreamwrite.WriteRecordsInput, opts ...request.Option) (*timestreamwrite.WriteRecordsOutput, error) {
+	if m.WriteRecordsWithContextFunc == nil {
+		return m.TimestreamWriteAPI.WriteRecordsWithContext(ctx, in, opts...)
+	}
+	return m.WriteRecordsWithContextFunc(ctx, in, opts...)
+}
@@ -0,0 +1,194 @@
+package awsxpb
+
+import (
+	"errors"
+
+	"github.com/routebyintuition/awsx"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func init() {
+	awsx.RegisterCodec(Codec{})
+}
+
+// Codec is an awsx.Codec encoding state with protobuf. Values must be proto messages, so
+// awsx types are converted with the From functions first; it cannot be the StateFormat of
+// the endpoint store, which persists the awsx types themselves.
+type Codec struct{}
+
+// Name returns "protobuf"
+func (Codec) Name() string { return "protobuf" }
+
+// Marshal encodes a proto message
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, errors.New("protobuf codec can only marshal proto messages")
+	}
+	return proto.Marshal(m)
+}
+
+// Unmarshal decodes into a proto message
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return errors.New("protobuf codec can only unmarshal proto messages")
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// FromRedisEndpoint converts an awsx.RedisEndpoint
+func FromRedisEndpoint(re *awsx.RedisEndpoint) *RedisEndpoint {
+	if re == nil {
+		return nil
+	}
+	return &RedisEndpoint{
+		Host:             re.Host,
+		Port:             int32(re.Port),
+		Slots:            re.Slots,
+		Tls:              re.TLS,
+		AvailabilityZone: re.AvailabilityZone,
+		Role:             string(re.Role),
+	}
+}
+
+// ToAwsx converts to an awsx.RedisEndpoint
+func (x *RedisEndpoint) ToAwsx() *awsx.RedisEndpoint {
+	if x == nil {
+		return nil
+	}
+	return &awsx.RedisEndpoint{
+		Host:             x.Host,
+		Port:             int(x.Port),
+		Slots:            x.Slots,
+		TLS:              x.Tls,
+		AvailabilityZone: x.AvailabilityZone,
+		Role:             awsx.EndpointRole(x.Role),
+	}
+}
+
+// FromRedisEndpoints converts awsx.RedisEndpoints
+func FromRedisEndpoints(res *awsx.RedisEndpoints) *RedisEndpoints {
+	if res == nil {
+		return nil
+	}
+	x := &RedisEndpoints{
+		Primary:          FromRedisEndpoint(res.Primary),
+		ClusterConfig:    FromRedisEndpoint(res.ClusterConfig),
+		ReplicationGroup: res.ReplicationGroup,
+		ReadReplicas:     res.ReadReplicas,
+		ClusterEnabled:   res.ClusterEnabled,
+	}
+	for _, re := range res.ReadEndpoints {
+		x.ReadEndpoints = append(x.ReadEndpoints, FromRedisEndpoint(re))
+	}
+	return x
+}
+
+// ToAwsx converts to awsx.RedisEndpoints
+func (x *RedisEndpoints) ToAwsx() *awsx.RedisEndpoints {
+	if x == nil {
+		return nil
+	}
+	res := &awsx.RedisEndpoints{
+		Primary:          x.Primary.ToAwsx(),
+		ClusterConfig:    x.ClusterConfig.ToAwsx(),
+		ReadEndpoints:    make([]*awsx.RedisEndpoint, 0, len(x.ReadEndpoints)),
+		ReplicationGroup: x.ReplicationGroup,
+		ReadReplicas:     x.ReadReplicas,
+		ClusterEnabled:   x.ClusterEnabled,
+	}
+	for _, re := range x.ReadEndpoints {
+		res.ReadEndpoints = append(res.ReadEndpoints, re.ToAwsx())
+	}
+	return res
+}
+
+// FromAuroraEndpoint converts an awsx.AuroraEndpoint
+func FromAuroraEndpoint(ae *awsx.AuroraEndpoint) *AuroraEndpoint {
+	if ae == nil {
+		return nil
+	}
+	return &AuroraEndpoint{Host: ae.Host, Port: int32(ae.Port), Instance: ae.Instance}
+}
+
+// ToAwsx converts to an awsx.AuroraEndpoint
+func (x *AuroraEndpoint) ToAwsx() *awsx.AuroraEndpoint {
+	if x == nil {
+		return nil
+	}
+	return &awsx.AuroraEndpoint{Host: x.Host, Port: int(x.Port), Instance: x.Instance}
+}
+
+// FromAuroraEndpoints converts awsx.AuroraEndpoints
+func FromAuroraEndpoints(aes *awsx.AuroraEndpoints) *AuroraEndpoints {
+	if aes == nil {
+		return nil
+	}
+	x := &AuroraEndpoints{
+		Cluster:        aes.Cluster,
+		Engine:         aes.Engine,
+		Writer:         FromAuroraEndpoint(aes.Writer),
+		Reader:         FromAuroraEndpoint(aes.Reader),
+		WriterInstance: FromAuroraEndpoint(aes.WriterInstance),
+		ReadReplicas:   aes.ReadReplicas,
+	}
+	for _, ae := range aes.ReadEndpoints {
+		x.ReadEndpoints = append(x.ReadEndpoints, FromAuroraEndpoint(ae))
+	}
+	return x
+}
+
+// ToAwsx converts to awsx.AuroraEndpoints
+func (x *AuroraEndpoints) ToAwsx() *awsx.AuroraEndpoints {
+	if x == nil {
+		return nil
+	}
+	aes := &awsx.AuroraEndpoints{
+		Cluster:        x.Cluster,
+		Engine:         x.Engine,
+		Writer:         x.Writer.ToAwsx(),
+		Reader:         x.Reader.ToAwsx(),
+		WriterInstance: x.WriterInstance.ToAwsx(),
+		ReadEndpoints:  make([]*awsx.AuroraEndpoint, 0, len(x.ReadEndpoints)),
+		ReadReplicas:   x.ReadReplicas,
+	}
+	for _, ae := range x.ReadEndpoints {
+		aes.ReadEndpoints = append(aes.ReadEndpoints, ae.ToAwsx())
+	}
+	return aes
+}
+
+// FromTopologyEvent converts an awsx.TopologyEvent
+func FromTopologyEvent(ev awsx.TopologyEvent) *TopologyEvent {
+	x := &TopologyEvent{
+		Seq:     ev.Seq,
+		Cluster: ev.Cluster,
+		Time:    timestamppb.New(ev.Time),
+	}
+	if ev.Err != nil {
+		x.Error = ev.Err.Error()
+	}
+	if ev.Redis != nil {
+		x.Topology = &TopologyEvent_Redis{Redis: FromRedisEndpoints(ev.Redis)}
+	} else if ev.Aurora != nil {
+		x.Topology = &TopologyEvent_Aurora{Aurora: FromAuroraEndpoints(ev.Aurora)}
+	}
+	return x
+}
+
+// ToAwsx converts to an awsx.TopologyEvent
+func (x *TopologyEvent) ToAwsx() awsx.TopologyEvent {
+	ev := awsx.TopologyEvent{
+		Seq:     x.GetSeq(),
+		Cluster: x.GetCluster(),
+		Time:    x.GetTime().AsTime(),
+		Redis:   x.GetRedis().ToAwsx(),
+		Aurora:  x.GetAurora().ToAwsx(),
+	}
+	if x.GetError() != "" {
+		ev.Err = errors.New(x.GetError())
+	}
+	return ev
+}
@@ -0,0 +1,8 @@
+// Package awsxpb contains the protobuf and gRPC types generated from
+// proto/awsx/v1/discovery.proto, along with conversions to and from the awsx types.
+//
+// Importing the package registers a protobuf awsx.Codec, so state written by EncodeState
+// from awsxpb messages can be read back with DecodeState.
+package awsxpb
+
+//go:generate sh -c "cd ../proto && buf generate"
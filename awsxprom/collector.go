@@ -0,0 +1,128 @@
+package awsxprom
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/routebyintuition/awsx"
+)
+
+// Collector is a prometheus.Collector implementing awsx.Metrics and awsx.CanaryRecorder
+type Collector struct {
+	calls     *prometheus.CounterVec
+	errors    *prometheus.CounterVec
+	latency   *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+	throttles *prometheus.CounterVec
+	cache     *prometheus.CounterVec
+	canary    *prometheus.HistogramVec
+	canaryUp  *prometheus.GaugeVec
+}
+
+var (
+	_ awsx.Metrics        = (*Collector)(nil)
+	_ awsx.CanaryRecorder = (*Collector)(nil)
+)
+
+// NewCollector returns a Collector with metrics named <namespace>_awsx_*. The namespace
+// may be empty.
+func NewCollector(namespace string) *Collector {
+	const subsystem = "awsx"
+	labels := []string{"service", "operation"}
+
+	return &Collector{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "api_calls_total", Help: "AWS API operations made by awsx.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "api_errors_total", Help: "AWS API operations that failed after retries, by error code.",
+		}, append(labels, "code")),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "api_call_duration_seconds", Help: "Latency of AWS API operations including retries.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "api_retries_total", Help: "Retried attempts of AWS API operations.",
+		}, labels),
+		throttles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "api_throttles_total", Help: "Throttled attempts of AWS API operations.",
+		}, labels),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "cache_lookups_total", Help: "Discovery cache lookups by result kind and outcome.",
+		}, []string{"kind", "result"}),
+		canary: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "canary_duration_seconds", Help: "Latency of canary checks.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"canary", "result"}),
+		canaryUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: subsystem,
+			Name: "canary_up", Help: "Whether the last canary check succeeded.",
+		}, []string{"canary"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range c.collectors() {
+		m.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c.collectors() {
+		m.Collect(ch)
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.calls, c.errors, c.latency, c.retries, c.throttles, c.cache, c.canary, c.canaryUp}
+}
+
+// ObserveCall implements awsx.Metrics
+func (c *Collector) ObserveCall(service, operation string, latency time.Duration, retries int, err error) {
+	c.calls.WithLabelValues(service, operation).Inc()
+	c.latency.WithLabelValues(service, operation).Observe(latency.Seconds())
+	if retries > 0 {
+		c.retries.WithLabelValues(service, operation).Add(float64(retries))
+	}
+	if err != nil {
+		code := "unknown"
+		if aerr, ok := err.(awserr.Error); ok {
+			code = aerr.Code()
+		}
+		c.errors.WithLabelValues(service, operation, code).Inc()
+	}
+}
+
+// ObserveThrottle implements awsx.Metrics
+func (c *Collector) ObserveThrottle(service, operation string) {
+	c.throttles.WithLabelValues(service, operation).Inc()
+}
+
+// ObserveCache implements awsx.Metrics
+func (c *Collector) ObserveCache(kind string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.cache.WithLabelValues(kind, result).Inc()
+}
+
+// RecordCanary implements awsx.CanaryRecorder
+func (c *Collector) RecordCanary(r awsx.CanaryResult) {
+	result, up := "success", 1.0
+	if r.Err != nil {
+		result, up = "failure", 0
+	}
+	c.canary.WithLabelValues(r.Name, result).Observe(r.Latency.Seconds())
+	c.canaryUp.WithLabelValues(r.Name).Set(up)
+}
@@ -0,0 +1,10 @@
+// Package awsxprom exports the metrics of awsx to Prometheus. A Collector counts and
+// times the AWS calls made by a Config, its throttles and errors, discovery cache hits
+// and misses, and canary results:
+//
+//	c := awsxprom.NewCollector("myapp")
+//	prometheus.MustRegister(c)
+//
+//	a := awsx.NewAWS().WithAllProviders().SetMetrics(c)
+//	a.SetSession()
+package awsxprom
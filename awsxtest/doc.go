@@ -0,0 +1,10 @@
+// Package awsxtest provides in-memory fakes of the ElastiCache and RDS APIs that can be
+// seeded with replication groups, cache clusters and DB clusters. The fakes let tests
+// of code built on awsx exercise topology changes such as failovers deterministically:
+//
+//	ec := awsxtest.NewElastiCache()
+//	ec.AddReplicationGroup(awsxtest.ReplicationGroup("orders", "orders-001", "orders-002"))
+//	a := awsx.NewAWS().WithECClient(ec)
+//
+//	ec.Failover("orders", "0001", "orders-002")
+package awsxtest
@@ -0,0 +1,330 @@
+package awsxtest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+)
+
+// ElastiCache is an in-memory fake of elasticacheiface.ElastiCacheAPI. Operations that
+// are not faked panic when called.
+type ElastiCache struct {
+	elasticacheiface.ElastiCacheAPI
+
+	mu       sync.Mutex
+	groups   map[string]*elasticache.ReplicationGroup
+	clusters map[string]*elasticache.CacheCluster
+	tags     map[string]map[string]string
+}
+
+// NewElastiCache returns an empty ElastiCache fake
+func NewElastiCache() *ElastiCache {
+	return &ElastiCache{
+		groups:   map[string]*elasticache.ReplicationGroup{},
+		clusters: map[string]*elasticache.CacheCluster{},
+		tags:     map[string]map[string]string{},
+	}
+}
+
+// Tag sets a tag on the resource with the given ARN
+func (f *ElastiCache) Tag(arn, key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tags[arn] == nil {
+		f.tags[arn] = map[string]string{}
+	}
+	f.tags[arn][key] = value
+}
+
+// AddReplicationGroup adds or replaces a replication group in the fake
+func (f *ElastiCache) AddReplicationGroup(rg *elasticache.ReplicationGroup) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.groups[aws.StringValue(rg.ReplicationGroupId)] = rg
+}
+
+// RemoveReplicationGroup removes a replication group from the fake
+func (f *ElastiCache) RemoveReplicationGroup(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.groups, id)
+}
+
+// AddCacheCluster adds or replaces a cache cluster in the fake
+func (f *ElastiCache) AddCacheCluster(cc *elasticache.CacheCluster) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clusters[aws.StringValue(cc.CacheClusterId)] = cc
+}
+
+// RemoveCacheCluster removes a cache cluster from the fake
+func (f *ElastiCache) RemoveCacheCluster(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.clusters, id)
+}
+
+// Failover promotes the member cluster newPrimary to primary within a node group,
+// demoting the current primary to a replica
+func (f *ElastiCache) Failover(groupID, nodeGroupID, newPrimary string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rg, ok := f.groups[groupID]
+	if !ok {
+		return awserr.New(elasticache.ErrCodeReplicationGroupNotFoundFault, "replication group "+groupID+" not found", nil)
+	}
+
+	for _, ng := range rg.NodeGroups {
+		if aws.StringValue(ng.NodeGroupId) != nodeGroupID {
+			continue
+		}
+		found := false
+		for _, m := range ng.NodeGroupMembers {
+			if aws.StringValue(m.CacheClusterId) == newPrimary {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("member %s not found in node group %s", newPrimary, nodeGroupID)
+		}
+		for _, m := range ng.NodeGroupMembers {
+			if aws.StringValue(m.CacheClusterId) == newPrimary {
+				m.CurrentRole = aws.String("primary")
+			} else {
+				m.CurrentRole = aws.String("replica")
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("node group %s not found in replication group %s", nodeGroupID, groupID)
+}
+
+// DescribeReplicationGroups returns the seeded replication groups
+func (f *ElastiCache) DescribeReplicationGroups(in *elasticache.DescribeReplicationGroupsInput) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &elasticache.DescribeReplicationGroupsOutput{}
+	if in.ReplicationGroupId != nil {
+		rg, ok := f.groups[*in.ReplicationGroupId]
+		if !ok {
+			return nil, awserr.New(elasticache.ErrCodeReplicationGroupNotFoundFault, "replication group "+*in.ReplicationGroupId+" not found", nil)
+		}
+		out.ReplicationGroups = append(out.ReplicationGroups, copyReplicationGroup(rg))
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(f.groups))
+	for id := range f.groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		out.ReplicationGroups = append(out.ReplicationGroups, copyReplicationGroup(f.groups[id]))
+	}
+
+	return out, nil
+}
+
+// DescribeReplicationGroupsWithContext returns the seeded replication groups
+func (f *ElastiCache) DescribeReplicationGroupsWithContext(ctx aws.Context, in *elasticache.DescribeReplicationGroupsInput, opts ...request.Option) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	return f.DescribeReplicationGroups(in)
+}
+
+// DescribeReplicationGroupsPages returns the seeded replication groups as a single page
+func (f *ElastiCache) DescribeReplicationGroupsPages(in *elasticache.DescribeReplicationGroupsInput, fn func(*elasticache.DescribeReplicationGroupsOutput, bool) bool) error {
+	out, err := f.DescribeReplicationGroups(in)
+	if err != nil {
+		return err
+	}
+	fn(out, true)
+	return nil
+}
+
+// DescribeReplicationGroupsPagesWithContext returns the seeded replication groups as a
+// single page
+func (f *ElastiCache) DescribeReplicationGroupsPagesWithContext(ctx aws.Context, in *elasticache.DescribeReplicationGroupsInput, fn func(*elasticache.DescribeReplicationGroupsOutput, bool) bool, opts ...request.Option) error {
+	return f.DescribeReplicationGroupsPages(in, fn)
+}
+
+// DescribeCacheClusters returns the seeded cache clusters
+func (f *ElastiCache) DescribeCacheClusters(in *elasticache.DescribeCacheClustersInput) (*elasticache.DescribeCacheClustersOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &elasticache.DescribeCacheClustersOutput{}
+	if in.CacheClusterId != nil {
+		cc, ok := f.clusters[*in.CacheClusterId]
+		if !ok {
+			return nil, awserr.New(elasticache.ErrCodeCacheClusterNotFoundFault, "cache cluster "+*in.CacheClusterId+" not found", nil)
+		}
+		out.CacheClusters = append(out.CacheClusters, copyCacheCluster(cc))
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(f.clusters))
+	for id := range f.clusters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		out.CacheClusters = append(out.CacheClusters, copyCacheCluster(f.clusters[id]))
+	}
+
+	return out, nil
+}
+
+// DescribeCacheClustersWithContext returns the seeded cache clusters
+func (f *ElastiCache) DescribeCacheClustersWithContext(ctx aws.Context, in *elasticache.DescribeCacheClustersInput, opts ...request.Option) (*elasticache.DescribeCacheClustersOutput, error) {
+	return f.DescribeCacheClusters(in)
+}
+
+// DescribeCacheClustersPages returns the seeded cache clusters as a single page
+func (f *ElastiCache) DescribeCacheClustersPages(in *elasticache.DescribeCacheClustersInput, fn func(*elasticache.DescribeCacheClustersOutput, bool) bool) error {
+	out, err := f.DescribeCacheClusters(in)
+	if err != nil {
+		return err
+	}
+	fn(out, true)
+	return nil
+}
+
+// ListTagsForResource returns the tags set with Tag
+func (f *ElastiCache) ListTagsForResource(in *elasticache.ListTagsForResourceInput) (*elasticache.TagListMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &elasticache.TagListMessage{}
+	tags := f.tags[aws.StringValue(in.ResourceName)]
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out.TagList = append(out.TagList, &elasticache.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+
+	return out, nil
+}
+
+// ReplicationGroup builds a cluster mode disabled replication group with a single node
+// group. The first member is the primary and the remaining members are replicas.
+func ReplicationGroup(id string, members ...string) *elasticache.ReplicationGroup {
+	ng := &elasticache.NodeGroup{
+		NodeGroupId:     aws.String("0001"),
+		Status:          aws.String("available"),
+		PrimaryEndpoint: endpoint(id + ".fake.ng.0001.cache.amazonaws.com"),
+		ReaderEndpoint:  endpoint(id + "-ro.fake.ng.0001.cache.amazonaws.com"),
+	}
+	for i, m := range members {
+		role := "replica"
+		if i == 0 {
+			role = "primary"
+		}
+		ng.NodeGroupMembers = append(ng.NodeGroupMembers, &elasticache.NodeGroupMember{
+			CacheClusterId:            aws.String(m),
+			CacheNodeId:               aws.String("0001"),
+			CurrentRole:               aws.String(role),
+			PreferredAvailabilityZone: aws.String("us-east-1a"),
+			ReadEndpoint:              endpoint(m + ".fake.0001.cache.amazonaws.com"),
+		})
+	}
+
+	return &elasticache.ReplicationGroup{
+		ReplicationGroupId: aws.String(id),
+		ARN:                aws.String(groupARN(id)),
+		Status:             aws.String("available"),
+		ClusterEnabled:     aws.Bool(false),
+		MemberClusters:     aws.StringSlice(members),
+		NodeGroups:         []*elasticache.NodeGroup{ng},
+	}
+}
+
+// ClusterReplicationGroup builds a cluster mode enabled replication group with the
+// given number of shards, each with replicas read replicas. A group has at least one shard.
+func ClusterReplicationGroup(id string, shards, replicas int) *elasticache.ReplicationGroup {
+	if shards < 1 {
+		shards = 1
+	}
+	rg := &elasticache.ReplicationGroup{
+		ReplicationGroupId:    aws.String(id),
+		ARN:                   aws.String(groupARN(id)),
+		Status:                aws.String("available"),
+		ClusterEnabled:        aws.Bool(true),
+		ConfigurationEndpoint: endpoint(id + ".fake.clustercfg.cache.amazonaws.com"),
+	}
+
+	slotsPerShard := 16384 / shards
+	for s := 0; s < shards; s++ {
+		start := s * slotsPerShard
+		end := start + slotsPerShard - 1
+		if s == shards-1 {
+			end = 16383
+		}
+		ng := &elasticache.NodeGroup{
+			NodeGroupId: aws.String(fmt.Sprintf("%04d", s+1)),
+			Status:      aws.String("available"),
+			Slots:       aws.String(fmt.Sprintf("%d-%d", start, end)),
+		}
+		for r := 0; r <= replicas; r++ {
+			member := fmt.Sprintf("%s-%04d-%03d", id, s+1, r+1)
+			rg.MemberClusters = append(rg.MemberClusters, aws.String(member))
+			ng.NodeGroupMembers = append(ng.NodeGroupMembers, &elasticache.NodeGroupMember{
+				CacheClusterId:            aws.String(member),
+				CacheNodeId:               aws.String("0001"),
+				PreferredAvailabilityZone: aws.String("us-east-1a"),
+			})
+		}
+		rg.NodeGroups = append(rg.NodeGroups, ng)
+	}
+
+	return rg
+}
+
+// CacheCluster builds a single node cache cluster that is not part of a replication group
+func CacheCluster(id, engine string) *elasticache.CacheCluster {
+	return &elasticache.CacheCluster{
+		CacheClusterId:     aws.String(id),
+		CacheClusterStatus: aws.String("available"),
+		Engine:             aws.String(engine),
+		NumCacheNodes:      aws.Int64(1),
+		CacheNodes: []*elasticache.CacheNode{
+			{
+				CacheNodeId:     aws.String("0001"),
+				CacheNodeStatus: aws.String("available"),
+				Endpoint:        endpoint(id + ".fake.0001.cache.amazonaws.com"),
+			},
+		},
+	}
+}
+
+// groupARN returns the fake ARN of a replication group
+func groupARN(id string) string {
+	return "arn:aws:elasticache:us-east-1:123456789012:replicationgroup:" + id
+}
+
+func endpoint(host string) *elasticache.Endpoint {
+	return &elasticache.Endpoint{Address: aws.String(host), Port: aws.Int64(6379)}
+}
+
+func copyReplicationGroup(rg *elasticache.ReplicationGroup) *elasticache.ReplicationGroup {
+	cp := &elasticache.ReplicationGroup{}
+	awsutil.Copy(cp, rg)
+	return cp
+}
+
+func copyCacheCluster(cc *elasticache.CacheCluster) *elasticache.CacheCluster {
+	cp := &elasticache.CacheCluster{}
+	awsutil.Copy(cp, cc)
+	return cp
+}
@@ -0,0 +1,249 @@
+package awsxtest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// RDS is an in-memory fake of rdsiface.RDSAPI. Operations that are not faked panic
+// when called.
+type RDS struct {
+	rdsiface.RDSAPI
+
+	mu        sync.Mutex
+	clusters  map[string]*rds.DBCluster
+	instances map[string]*rds.DBInstance
+}
+
+// NewRDS returns an empty RDS fake
+func NewRDS() *RDS {
+	return &RDS{
+		clusters:  map[string]*rds.DBCluster{},
+		instances: map[string]*rds.DBInstance{},
+	}
+}
+
+// AddDBCluster adds or replaces a DB cluster and its instances in the fake
+func (f *RDS) AddDBCluster(c *rds.DBCluster, instances ...*rds.DBInstance) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clusters[aws.StringValue(c.DBClusterIdentifier)] = c
+	for _, i := range instances {
+		f.instances[aws.StringValue(i.DBInstanceIdentifier)] = i
+	}
+}
+
+// RemoveDBCluster removes a DB cluster and its member instances from the fake
+func (f *RDS) RemoveDBCluster(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.clusters[id]; ok {
+		for _, m := range c.DBClusterMembers {
+			delete(f.instances, aws.StringValue(m.DBInstanceIdentifier))
+		}
+	}
+	delete(f.clusters, id)
+}
+
+// AddDBInstance adds or replaces a DB instance in the fake
+func (f *RDS) AddDBInstance(i *rds.DBInstance) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.instances[aws.StringValue(i.DBInstanceIdentifier)] = i
+}
+
+// Failover makes newWriter the writer instance of the DB cluster
+func (f *RDS) Failover(clusterID, newWriter string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.clusters[clusterID]
+	if !ok {
+		return awserr.New(rds.ErrCodeDBClusterNotFoundFault, "db cluster "+clusterID+" not found", nil)
+	}
+
+	found := false
+	for _, m := range c.DBClusterMembers {
+		if aws.StringValue(m.DBInstanceIdentifier) == newWriter {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("instance %s is not a member of db cluster %s", newWriter, clusterID)
+	}
+
+	for _, m := range c.DBClusterMembers {
+		m.IsClusterWriter = aws.Bool(aws.StringValue(m.DBInstanceIdentifier) == newWriter)
+	}
+
+	return nil
+}
+
+// DescribeDBClusters returns the seeded DB clusters
+func (f *RDS) DescribeDBClusters(in *rds.DescribeDBClustersInput) (*rds.DescribeDBClustersOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &rds.DescribeDBClustersOutput{}
+	if in.DBClusterIdentifier != nil {
+		c, ok := f.clusters[*in.DBClusterIdentifier]
+		if !ok {
+			return nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "db cluster "+*in.DBClusterIdentifier+" not found", nil)
+		}
+		out.DBClusters = append(out.DBClusters, copyDBCluster(c))
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(f.clusters))
+	for id := range f.clusters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	engines := map[string]bool{}
+	for _, filter := range in.Filters {
+		if aws.StringValue(filter.Name) == "engine" {
+			for _, v := range filter.Values {
+				engines[aws.StringValue(v)] = true
+			}
+		}
+	}
+	for _, id := range ids {
+		if len(engines) > 0 && !engines[aws.StringValue(f.clusters[id].Engine)] {
+			continue
+		}
+		out.DBClusters = append(out.DBClusters, copyDBCluster(f.clusters[id]))
+	}
+
+	return out, nil
+}
+
+// DescribeDBClustersWithContext returns the seeded DB clusters
+func (f *RDS) DescribeDBClustersWithContext(ctx aws.Context, in *rds.DescribeDBClustersInput, opts ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+	return f.DescribeDBClusters(in)
+}
+
+// DescribeDBClustersPages returns the seeded DB clusters as a single page
+func (f *RDS) DescribeDBClustersPages(in *rds.DescribeDBClustersInput, fn func(*rds.DescribeDBClustersOutput, bool) bool) error {
+	out, err := f.DescribeDBClusters(in)
+	if err != nil {
+		return err
+	}
+	fn(out, true)
+	return nil
+}
+
+// DescribeDBInstances returns the seeded DB instances
+func (f *RDS) DescribeDBInstances(in *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &rds.DescribeDBInstancesOutput{}
+	if in.DBInstanceIdentifier != nil {
+		i, ok := f.instances[*in.DBInstanceIdentifier]
+		if !ok {
+			return nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "db instance "+*in.DBInstanceIdentifier+" not found", nil)
+		}
+		out.DBInstances = append(out.DBInstances, copyDBInstance(i))
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(f.instances))
+	for id := range f.instances {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	cluster := ""
+	for _, filter := range in.Filters {
+		if aws.StringValue(filter.Name) == "db-cluster-id" && len(filter.Values) > 0 {
+			cluster = aws.StringValue(filter.Values[0])
+		}
+	}
+	for _, id := range ids {
+		if cluster != "" && aws.StringValue(f.instances[id].DBClusterIdentifier) != cluster {
+			continue
+		}
+		out.DBInstances = append(out.DBInstances, copyDBInstance(f.instances[id]))
+	}
+
+	return out, nil
+}
+
+// DescribeDBInstancesWithContext returns the seeded DB instances
+func (f *RDS) DescribeDBInstancesWithContext(ctx aws.Context, in *rds.DescribeDBInstancesInput, opts ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+	return f.DescribeDBInstances(in)
+}
+
+// DescribeDBInstancesPages returns the seeded DB instances as a single page
+func (f *RDS) DescribeDBInstancesPages(in *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool) error {
+	out, err := f.DescribeDBInstances(in)
+	if err != nil {
+		return err
+	}
+	fn(out, true)
+	return nil
+}
+
+// DescribeDBInstancesPagesWithContext returns the seeded DB instances as a single page
+func (f *RDS) DescribeDBInstancesPagesWithContext(ctx aws.Context, in *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool, opts ...request.Option) error {
+	return f.DescribeDBInstancesPages(in, fn)
+}
+
+// AuroraCluster builds an Aurora DB cluster and its instances. The first instance is
+// the writer and the remaining instances are readers.
+func AuroraCluster(id, engine string, instances ...string) (*rds.DBCluster, []*rds.DBInstance) {
+	c := &rds.DBCluster{
+		DBClusterIdentifier: aws.String(id),
+		DBClusterArn:        aws.String("arn:aws:rds:us-east-1:123456789012:cluster:" + id),
+		Engine:              aws.String(engine),
+		Status:              aws.String("available"),
+		Endpoint:            aws.String(id + ".cluster-fake.us-east-1.rds.amazonaws.com"),
+		ReaderEndpoint:      aws.String(id + ".cluster-ro-fake.us-east-1.rds.amazonaws.com"),
+		Port:                aws.Int64(3306),
+		MultiAZ:             aws.Bool(len(instances) > 1),
+	}
+	if engine == "aurora-postgresql" {
+		c.Port = aws.Int64(5432)
+	}
+
+	list := make([]*rds.DBInstance, 0, len(instances))
+	for i, id := range instances {
+		c.DBClusterMembers = append(c.DBClusterMembers, &rds.DBClusterMember{
+			DBInstanceIdentifier: aws.String(id),
+			IsClusterWriter:      aws.Bool(i == 0),
+			PromotionTier:        aws.Int64(1),
+		})
+		list = append(list, &rds.DBInstance{
+			DBInstanceIdentifier: aws.String(id),
+			DBClusterIdentifier:  c.DBClusterIdentifier,
+			DBInstanceStatus:     aws.String("available"),
+			Engine:               aws.String(engine),
+			AvailabilityZone:     aws.String("us-east-1a"),
+			Endpoint: &rds.Endpoint{
+				Address: aws.String(id + ".fake.us-east-1.rds.amazonaws.com"),
+				Port:    c.Port,
+			},
+		})
+	}
+
+	return c, list
+}
+
+func copyDBCluster(c *rds.DBCluster) *rds.DBCluster {
+	cp := &rds.DBCluster{}
+	awsutil.Copy(cp, c)
+	return cp
+}
+
+func copyDBInstance(i *rds.DBInstance) *rds.DBInstance {
+	cp := &rds.DBInstance{}
+	awsutil.Copy(cp, i)
+	return cp
+}
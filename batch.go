@@ -0,0 +1,269 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+const (
+	// maxBatchConcurrency bounds the Describe calls a batch runs in parallel
+	maxBatchConcurrency = 4
+	// batchFilterSize is the number of cluster identifiers per RDS filter
+	batchFilterSize = 50
+)
+
+// batchResult is the prefetched discovery result of a cluster in a batch
+type batchResult struct {
+	value interface{}
+	err   error
+}
+
+// GetRedisEndpointsBatch resolves the endpoints of many replication groups or cache
+// clusters at once, as GetRedisPrimaryEndpoint would. Instead of describing each cluster,
+// the replication groups and cache clusters of the region are listed once, so resolving
+// dozens of caches at startup takes a few paged calls. Results are cached like those of
+// GetRedisPrimaryEndpoint, and clusters with a fresh cached result are not described at
+// all. Clusters that failed are in the error map instead of the result map.
+func (a *Config) GetRedisEndpointsBatch(clusters []string) (map[string]*RedisEndpoints, map[string]error) {
+	// the client and cache are created lazily, which must not happen in the goroutines
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+	a.results()
+
+	prefetched := map[string]batchResult{}
+	if misses := a.batchMisses("redis:", clusters); len(misses) > 0 {
+		prefetched = a.prefetchRedis(misses)
+	}
+
+	clusters = distinct(clusters)
+	results := map[string]*RedisEndpoints{}
+	errs := map[string]error{}
+	var mu sync.Mutex
+	a.eachBatch(len(clusters), func(i int) {
+		cluster := clusters[i]
+		res, err := a.traced("redis", cluster, func(ctx context.Context) (interface{}, error) {
+			return a.cached("redis:"+cluster, func() (interface{}, error) {
+				if r, ok := prefetched[cluster]; ok {
+					return r.value, r.err
+				}
+				// not listed, e.g. a fuzzy name, so describe it on its own
+				return a.getRedisPrimaryEndpoint(ctx, cluster)
+			})
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil && res == nil {
+			err = errors.New("no endpoints found for " + cluster)
+		}
+		if err != nil {
+			errs[cluster] = err
+			return
+		}
+		results[cluster] = res.(*RedisEndpoints)
+	})
+
+	return results, errs
+}
+
+// GetAuroraEndpointsBatch resolves the endpoints of many Aurora DB clusters at once, as
+// GetAuroraEndpoints would. The clusters and their instances are described with filters
+// of up to 50 clusters per call, with a few calls in parallel. Results are cached like
+// those of GetAuroraEndpoints, and clusters with a fresh cached result are not described
+// at all. Clusters that failed are in the error map instead of the result map.
+func (a *Config) GetAuroraEndpointsBatch(clusters []string) (map[string]*AuroraEndpoints, map[string]error) {
+	// the client and cache are created lazily, which must not happen in the goroutines
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+	a.results()
+
+	prefetched := map[string]batchResult{}
+	if misses := a.batchMisses("aurora:", clusters); len(misses) > 0 {
+		prefetched = a.prefetchAurora(misses)
+	}
+
+	clusters = distinct(clusters)
+	results := map[string]*AuroraEndpoints{}
+	errs := map[string]error{}
+	var mu sync.Mutex
+	a.eachBatch(len(clusters), func(i int) {
+		cluster := clusters[i]
+		aes, err := a.traced("aurora", cluster, func(ctx context.Context) (interface{}, error) {
+			return a.cached("aurora:"+cluster, func() (interface{}, error) {
+				if r, ok := prefetched[cluster]; ok {
+					return r.value, r.err
+				}
+				return a.getAuroraEndpoints(ctx, cluster)
+			})
+		})
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil && aes == nil {
+			err = errors.New("no endpoints found for " + cluster)
+		}
+		if err != nil {
+			errs[cluster] = err
+			return
+		}
+		results[cluster] = aes.(*AuroraEndpoints)
+	})
+
+	return results, errs
+}
+
+// batchMisses returns the clusters without a fresh cached result under prefix
+func (a *Config) batchMisses(prefix string, clusters []string) []string {
+	misses := make([]string, 0, len(clusters))
+	for _, c := range distinct(clusters) {
+		if c != "" && !a.fresh(prefix+c) {
+			misses = append(misses, c)
+		}
+	}
+	return misses
+}
+
+// distinct returns the values in order with duplicates removed
+func distinct(values []string) []string {
+	seen := map[string]bool{}
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// eachBatch calls fn for 0 to n-1 with bounded concurrency
+func (a *Config) eachBatch(n int, fn func(i int)) {
+	sem := make(chan struct{}, maxBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// prefetchRedis lists the replication groups and cache clusters of the region once and
+// builds the endpoints of the clusters found. Clusters not listed are left out.
+func (a *Config) prefetchRedis(clusters []string) map[string]batchResult {
+	groups := map[string]*elasticache.ReplicationGroup{}
+	nodes := map[string]*elasticache.CacheCluster{}
+
+	var wg sync.WaitGroup
+	var groupsErr, nodesErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		groupsErr = a.EachECReplicationGroup(func(rg *elasticache.ReplicationGroup) bool {
+			groups[aws.StringValue(rg.ReplicationGroupId)] = rg
+			return true
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		nodesErr = a.EachECCacheCluster(func(cc *elasticache.CacheCluster) bool {
+			nodes[aws.StringValue(cc.CacheClusterId)] = cc
+			return true
+		})
+	}()
+	wg.Wait()
+
+	results := map[string]batchResult{}
+	for _, cluster := range clusters {
+		if groupsErr != nil || nodesErr != nil {
+			err := groupsErr
+			if err == nil {
+				err = nodesErr
+			}
+			results[cluster] = batchResult{err: err}
+			continue
+		}
+
+		if rg, ok := groups[cluster]; ok {
+			res, err := redisEndpointsFromGroup(rg)
+			if err == nil && len(rg.MemberClusters) > 0 {
+				if cc, ok := nodes[aws.StringValue(rg.MemberClusters[0])]; ok {
+					res.setEngine(cc)
+				}
+			}
+			results[cluster] = batchResult{value: res, err: err}
+		} else if cc, ok := nodes[cluster]; ok {
+			res, err := redisEndpointsFromCacheCluster(cc)
+			results[cluster] = batchResult{value: res, err: err}
+		}
+	}
+
+	return results
+}
+
+// prefetchAurora describes the clusters and their instances in chunks filtered by cluster
+// identifier and builds their endpoints. Clusters not found are left out.
+func (a *Config) prefetchAurora(clusters []string) map[string]batchResult {
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	results := map[string]batchResult{}
+	var mu sync.Mutex
+
+	chunks := make([][]string, 0)
+	for start := 0; start < len(clusters); start += batchFilterSize {
+		end := start + batchFilterSize
+		if end > len(clusters) {
+			end = len(clusters)
+		}
+		chunks = append(chunks, clusters[start:end])
+	}
+
+	a.eachBatch(len(chunks), func(n int) {
+		ids := chunks[n]
+		filter := []*rds.Filter{{Name: aws.String("db-cluster-id"), Values: aws.StringSlice(ids)}}
+
+		described := map[string]*rds.DBCluster{}
+		err := a.eachRDSDBCluster(&rds.DescribeDBClustersInput{Filters: filter}, func(c *rds.DBCluster) bool {
+			described[aws.StringValue(c.DBClusterIdentifier)] = c
+			return true
+		})
+
+		instances := map[string][]*rds.DBInstance{}
+		if err == nil {
+			err = a.Service.Rds.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{Filters: filter, MaxRecords: aws.Int64(listPageSize)}, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+				for _, i := range page.DBInstances {
+					id := aws.StringValue(i.DBClusterIdentifier)
+					instances[id] = append(instances[id], i)
+				}
+				return true
+			})
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, id := range ids {
+			if err != nil {
+				results[id] = batchResult{err: err}
+			} else if c, ok := described[id]; ok {
+				results[id] = batchResult{value: auroraEndpointsFromCluster(id, c, instances[id])}
+			}
+		}
+	})
+
+	return results
+}
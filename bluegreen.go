@@ -0,0 +1,159 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// BlueGreenDeployment is the state of an RDS blue/green deployment
+type BlueGreenDeployment struct {
+	ID         string `json:"id" yaml:"id"`
+	Name       string `json:"name" yaml:"name"`
+	Status     string `json:"status" yaml:"status"` // e.g. AVAILABLE, SWITCHOVER_IN_PROGRESS or SWITCHOVER_COMPLETED
+	Source     string `json:"source" yaml:"source"` // ARN of the blue environment
+	Target     string `json:"target" yaml:"target"` // ARN of the green environment
+	Production string `json:"production" yaml:"production"`
+}
+
+// SwitchedOver reports whether the green environment has taken over production
+func (bg *BlueGreenDeployment) SwitchedOver() bool {
+	return bg.Status == "SWITCHOVER_COMPLETED"
+}
+
+// GetBlueGreenDeployment describes an RDS blue/green deployment and resolves which
+// environment is serving production: the green target once switchover has completed,
+// the blue source otherwise
+func (a *Config) GetBlueGreenDeployment(id string) (*BlueGreenDeployment, error) {
+	return a.getBlueGreenDeployment(a.context(), id)
+}
+
+// getBlueGreenDeployment is GetBlueGreenDeployment with the context of the call
+func (a *Config) getBlueGreenDeployment(ctx context.Context, id string) (*BlueGreenDeployment, error) {
+	if id == "" {
+		return nil, errors.New("no blue/green deployment identifier provided")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	result, err := a.Service.Rds.DescribeBlueGreenDeploymentsWithContext(ctx, &rds.DescribeBlueGreenDeploymentsInput{
+		BlueGreenDeploymentIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.BlueGreenDeployments) == 0 {
+		return nil, errors.New("no blue/green deployment matching " + id)
+	}
+
+	d := result.BlueGreenDeployments[0]
+	bg := &BlueGreenDeployment{
+		ID:     aws.StringValue(d.BlueGreenDeploymentIdentifier),
+		Name:   aws.StringValue(d.BlueGreenDeploymentName),
+		Status: aws.StringValue(d.Status),
+		Source: aws.StringValue(d.Source),
+		Target: aws.StringValue(d.Target),
+	}
+	bg.Production = bg.Source
+	if bg.SwitchedOver() {
+		bg.Production = bg.Target
+	}
+
+	return bg, nil
+}
+
+// GetBlueGreenEndpoints returns the endpoints of the environment of a blue/green
+// deployment that is currently serving production, with the deployment set on
+// AuroraEndpoints.BlueGreen. Both Aurora clusters and single DB instances are supported;
+// for an instance the writer is the instance endpoint.
+func (a *Config) GetBlueGreenEndpoints(id string) (*AuroraEndpoints, error) {
+	aes, err := a.traced("aurora", id, func(ctx context.Context) (interface{}, error) {
+		return a.cached("bluegreen:"+id, func() (interface{}, error) {
+			return a.getBlueGreenEndpoints(ctx, id)
+		})
+	})
+	if aes == nil {
+		return nil, err
+	}
+
+	return aes.(*AuroraEndpoints), err
+}
+
+// getBlueGreenEndpoints performs the uncached discovery for GetBlueGreenEndpoints
+func (a *Config) getBlueGreenEndpoints(ctx context.Context, id string) (*AuroraEndpoints, error) {
+	bg, err := a.getBlueGreenDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := arn.Parse(bg.Production)
+	if err != nil {
+		return nil, err
+	}
+	kind, name := resource.Resource, ""
+	if i := strings.Index(kind, ":"); i >= 0 {
+		kind, name = kind[:i], kind[i+1:]
+	}
+
+	var aes *AuroraEndpoints
+	switch kind {
+	case "cluster":
+		aes, err = a.getAuroraEndpoints(ctx, name)
+	case "db":
+		aes, err = a.getRDSInstanceEndpoints(ctx, name)
+	default:
+		err = errors.New("unsupported blue/green source " + bg.Production)
+	}
+	if err != nil {
+		return nil, err
+	}
+	aes.BlueGreen = bg
+
+	return aes, nil
+}
+
+// getRDSInstanceEndpoints returns the endpoint of a single DB instance as the writer
+func (a *Config) getRDSInstanceEndpoints(ctx context.Context, instance string) (*AuroraEndpoints, error) {
+	result, err := a.Service.Rds.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(instance),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.DBInstances) == 0 || result.DBInstances[0].Endpoint == nil {
+		return nil, errors.New("no endpoint found for db instance " + instance)
+	}
+
+	i := result.DBInstances[0]
+	entry := &AuroraEndpoint{
+		Host:          aws.StringValue(i.Endpoint.Address),
+		Port:          int(aws.Int64Value(i.Endpoint.Port)),
+		Instance:      instance,
+		CACertificate: aws.StringValue(i.CACertificateIdentifier),
+	}
+
+	return &AuroraEndpoints{
+		Cluster:        instance,
+		Engine:         aws.StringValue(i.Engine),
+		Writer:         entry,
+		WriterInstance: entry,
+		ReadEndpoints:  make([]*AuroraEndpoint, 0),
+		NetworkType:    aws.StringValue(i.NetworkType),
+	}, nil
+}
+
+// WatchBlueGreen returns a Watcher polling GetBlueGreenEndpoints for the deployment.
+// Events published when the deployment switches over have Switchover set.
+func (a *Config) WatchBlueGreen(id string, interval time.Duration) *Watcher {
+	return a.newWatcher(id, interval, func() (*RedisEndpoints, *AuroraEndpoints, error) {
+		aes, err := a.GetBlueGreenEndpoints(id)
+		return nil, aes, err
+	})
+}
@@ -0,0 +1,122 @@
+package awsx
+
+import (
+	"sync"
+	"time"
+)
+
+// cacheMu guards the lazy creation of the result cache of a Config built without NewAWS
+var cacheMu sync.Mutex
+
+// resultCache holds discovery results for Config.CacheTTL
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value  interface{}
+	stored time.Time
+}
+
+// SetCacheTTL enables caching of discovery results for the given duration. A zero
+// duration disables caching.
+func (a *Config) SetCacheTTL(ttl time.Duration) *Config {
+	a.CacheTTL = ttl
+	a.results()
+	return a
+}
+
+// results returns the result cache of the Config, creating it on first use
+func (a *Config) results() *resultCache {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if a.cache == nil {
+		a.cache = newResultCache()
+	}
+	return a.cache
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{entries: map[string]cacheEntry{}}
+}
+
+// FlushCache removes all cached discovery results
+func (a *Config) FlushCache() *Config {
+	cache := a.results()
+	cache.mu.Lock()
+	cache.entries = map[string]cacheEntry{}
+	cache.mu.Unlock()
+	return a
+}
+
+// forget removes the cached result for key, e.g. after the resource was changed
+func (a *Config) forget(key string) {
+	cache := a.results()
+	cache.mu.Lock()
+	delete(cache.entries, key)
+	cache.mu.Unlock()
+}
+
+// cached returns the cached value for key if it is younger than CacheTTL, otherwise
+// it calls fetch and caches a successful result. Endpoint results are also persisted
+// when an endpoint store is set, see SetEndpointStore. If fetch fails, an expired or
+// stored result may be returned instead depending on the StalePolicy.
+func (a *Config) cached(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if a.CacheTTL <= 0 {
+		value, _, err := a.stored(key, fetch)
+		return value, err
+	}
+
+	cache := a.results()
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	cache.mu.Unlock()
+	if ok && a.since(entry.stored) < a.CacheTTL {
+		a.observeCache(key, true)
+		return entry.value, nil
+	}
+	a.observeCache(key, false)
+
+	value, stale, err := a.stored(key, fetch)
+	if err != nil {
+		if ok {
+			if last := a.serveStale(key, entry.value, entry.stored, err); last != nil {
+				return last, nil
+			}
+		}
+		return value, err
+	}
+	// stale results are not cached so the next call tries discovery again
+	if !stale {
+		a.remember(key, value)
+	}
+
+	return value, nil
+}
+
+// fresh reports whether a result for key is cached and younger than CacheTTL
+func (a *Config) fresh(key string) bool {
+	if a.CacheTTL <= 0 {
+		return false
+	}
+
+	cache := a.results()
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	cache.mu.Unlock()
+	return ok && a.since(entry.stored) < a.CacheTTL
+}
+
+// remember caches the value for key when caching is enabled
+func (a *Config) remember(key string, value interface{}) {
+	if a.CacheTTL <= 0 {
+		return
+	}
+
+	cache := a.results()
+	cache.mu.Lock()
+	cache.entries[key] = cacheEntry{value: value, stored: a.clock().Now()}
+	cache.mu.Unlock()
+}
@@ -0,0 +1,318 @@
+package awsx
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCanaryInterval = time.Minute
+	defaultCanaryTimeout  = 5 * time.Second
+)
+
+// CanaryCheck performs a small end-to-end operation against the endpoint (host:port),
+// returning an error if the datastore did not respond correctly
+type CanaryCheck func(ctx context.Context, endpoint string) error
+
+// CanaryResult is the outcome of a single canary run
+type CanaryResult struct {
+	Name     string
+	Cluster  string
+	Endpoint string
+	Time     time.Time
+	Latency  time.Duration
+	Err      error
+}
+
+// CanaryRecorder receives the result of every canary run, e.g. to export success and
+// latency metrics
+type CanaryRecorder interface {
+	RecordCanary(result CanaryResult)
+}
+
+// CanaryRecorderFunc adapts a func to a CanaryRecorder
+type CanaryRecorderFunc func(result CanaryResult)
+
+// RecordCanary calls f
+func (f CanaryRecorderFunc) RecordCanary(result CanaryResult) { f(result) }
+
+// Canary periodically resolves the endpoint of a cluster with discovery and runs a
+// CanaryCheck against it, providing data-plane health alongside control-plane discovery.
+// Discovery failures are recorded as failed runs.
+type Canary struct {
+	Name     string
+	Cluster  string
+	Interval time.Duration
+	Timeout  time.Duration // per run timeout, defaults to 5 seconds
+
+	config    *Config
+	resolve   func() (string, error)
+	check     CanaryCheck
+	recorders []CanaryRecorder
+
+	mu      sync.Mutex
+	last    *CanaryResult
+	stop    chan struct{}
+	done    chan struct{}
+	running bool
+}
+
+// RedisCanary returns a Canary running check against the primary endpoint of the Redis
+// cluster, or its configuration endpoint when cluster mode is enabled
+func (a *Config) RedisCanary(cluster string, interval time.Duration, check CanaryCheck) *Canary {
+	return a.newCanary("redis "+cluster, cluster, interval, check, func() (string, error) {
+		res, err := a.GetRedisPrimaryEndpoint(cluster)
+		if err != nil {
+			return "", err
+		}
+		if res.ClusterEnabled {
+			return res.ClusterConfigString(), nil
+		}
+		return res.PrimaryString(), nil
+	})
+}
+
+// AuroraCanary returns a Canary running check against the writer endpoint of the Aurora
+// cluster
+func (a *Config) AuroraCanary(cluster string, interval time.Duration, check CanaryCheck) *Canary {
+	return a.newCanary("aurora "+cluster, cluster, interval, check, func() (string, error) {
+		aes, err := a.GetAuroraEndpoints(cluster)
+		if err != nil {
+			return "", err
+		}
+		return aes.WriterString(), nil
+	})
+}
+
+func (a *Config) newCanary(name, cluster string, interval time.Duration, check CanaryCheck, resolve func() (string, error)) *Canary {
+	if interval <= 0 {
+		interval = defaultCanaryInterval
+	}
+	return &Canary{
+		Name:     name,
+		Cluster:  cluster,
+		Interval: interval,
+		Timeout:  defaultCanaryTimeout,
+		config:   a,
+		resolve:  resolve,
+		check:    check,
+	}
+}
+
+// AddRecorder registers a recorder for the results of every run
+func (c *Canary) AddRecorder(r CanaryRecorder) *Canary {
+	c.mu.Lock()
+	c.recorders = append(c.recorders, r)
+	c.mu.Unlock()
+	return c
+}
+
+// Start begins running the canary in a background goroutine. The first run happens
+// immediately.
+func (c *Canary) Start() *Canary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return c
+	}
+	c.running = true
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.loop(c.stop, c.done)
+
+	return c
+}
+
+// Stop ends the background runs. A run that is in progress is allowed to finish; Stop
+// returns once it has.
+func (c *Canary) Stop() {
+	c.mu.Lock()
+	if !c.running {
+		c.mu.Unlock()
+		return
+	}
+	c.running = false
+	close(c.stop)
+	done := c.done
+	c.mu.Unlock()
+
+	<-done
+}
+
+// Last returns the result of the most recent run, or nil if the canary has not run yet
+func (c *Canary) Last() *CanaryResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last == nil {
+		return nil
+	}
+	last := *c.last
+	return &last
+}
+
+// Run resolves the endpoint, runs the check once and records the result
+func (c *Canary) Run(ctx context.Context) CanaryResult {
+	clock := c.config.clock()
+	result := CanaryResult{Name: c.Name, Cluster: c.Cluster, Time: clock.Now()}
+
+	endpoint, err := c.resolve()
+	if err != nil {
+		result.Err = fmt.Errorf("discovery failed: %v", err)
+	} else {
+		result.Endpoint = endpoint
+
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = defaultCanaryTimeout
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		start := clock.Now()
+		result.Err = c.check(ctx, endpoint)
+		result.Latency = clock.Now().Sub(start)
+		cancel()
+	}
+
+	c.mu.Lock()
+	c.last = &result
+	recorders := append([]CanaryRecorder(nil), c.recorders...)
+	c.mu.Unlock()
+
+	for _, r := range recorders {
+		r.RecordCanary(result)
+	}
+
+	return result
+}
+
+func (c *Canary) loop(stop, done chan struct{}) {
+	defer close(done)
+	for {
+		c.Run(context.Background())
+		if !c.config.wait(c.Interval, stop) {
+			return
+		}
+	}
+}
+
+// RedisSetGetCheck returns a CanaryCheck that writes a timestamp to key with SET, with a
+// one minute expiry, and reads it back with GET. The auth token is sent with AUTH when
+// not empty, and the connection uses TLS when tlsConfig is not nil.
+func RedisSetGetCheck(key string, auth Secret, tlsConfig *tls.Config) CanaryCheck {
+	return func(ctx context.Context, endpoint string) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", endpoint)
+		if err != nil {
+			return err
+		}
+		if tlsConfig != nil {
+			cfg := tlsConfig.Clone()
+			if cfg.ServerName == "" {
+				cfg.ServerName, _, _ = net.SplitHostPort(endpoint)
+			}
+			conn = tls.Client(conn, cfg)
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		r := bufio.NewReader(conn)
+		if auth != "" {
+			if _, err := redisCommand(conn, r, "AUTH", auth.UnsafeRaw()); err != nil {
+				return err
+			}
+		}
+
+		value := strconv.FormatInt(time.Now().UnixNano(), 10)
+		if _, err := redisCommand(conn, r, "SET", key, value, "EX", "60"); err != nil {
+			return err
+		}
+		got, err := redisCommand(conn, r, "GET", key)
+		if err != nil {
+			return err
+		}
+		if got != value {
+			return errors.New("redis canary read back " + got + " instead of " + value)
+		}
+
+		return nil
+	}
+}
+
+// redisCommand sends a command in the RESP protocol and returns the simple or bulk
+// string reply
+func redisCommand(conn net.Conn, r *bufio.Reader, args ...string) (string, error) {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		b.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return "", err
+	}
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", errors.New("redis " + args[0] + ": " + line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", err
+		}
+		if n < 0 {
+			return "", nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:n]), nil
+	}
+
+	return "", errors.New("unexpected redis reply " + line)
+}
+
+// SQLSelectOneCheck returns a CanaryCheck that opens a database/sql connection with the
+// driver and the DSN built for the endpoint by dsn, and runs SELECT 1. The driver must
+// be registered by the application, e.g. by importing github.com/go-sql-driver/mysql.
+func SQLSelectOneCheck(driver string, dsn func(endpoint string) string) CanaryCheck {
+	return func(ctx context.Context, endpoint string) error {
+		db, err := sql.Open(driver, dsn(endpoint))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var one int
+		if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+			return err
+		}
+		if one != 1 {
+			return errors.New("SELECT 1 returned " + strconv.Itoa(one))
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package awsx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultCheckTimeout = 3 * time.Second
+
+// CheckOptions configures the reachability checks of Check
+type CheckOptions struct {
+	Timeout   time.Duration // optional: per endpoint timeout, defaults to 3 seconds
+	TLSConfig *tls.Config   // optional: complete a TLS handshake after connecting, e.g. for in-transit encryption
+}
+
+// EndpointHealth is the result of checking a single discovered endpoint
+type EndpointHealth struct {
+	Role     string // primary, cluster, reader, writer or instance
+	Endpoint string
+	Addrs    []string // addresses the host resolved to
+	Latency  time.Duration
+	Err      error
+}
+
+// OK reports whether the endpoint resolved and accepted a connection
+func (h EndpointHealth) OK() bool {
+	return h.Err == nil
+}
+
+// EndpointHealthReport is the result of Check
+type EndpointHealthReport []EndpointHealth
+
+// OK reports whether every endpoint is reachable
+func (r EndpointHealthReport) OK() bool {
+	for _, h := range r {
+		if !h.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns an error listing the unreachable endpoints, or nil if every endpoint is reachable
+func (r EndpointHealthReport) Err() error {
+	failed := make([]string, 0)
+	for _, h := range r {
+		if !h.OK() {
+			failed = append(failed, h.Role+" "+h.Endpoint+": "+h.Err.Error())
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.New("endpoint check failed: " + strings.Join(failed, "; "))
+}
+
+// Check resolves the hostname of every endpoint and dials it over TCP, so that stale DNS
+// or security group issues are detected right after discovery rather than on first use
+func (res *RedisEndpoints) Check(ctx context.Context) EndpointHealthReport {
+	return res.CheckWith(ctx, nil)
+}
+
+// CheckWith is Check with options, e.g. to complete a TLS handshake
+func (res *RedisEndpoints) CheckWith(ctx context.Context, opts *CheckOptions) EndpointHealthReport {
+	targets := make([]checkTarget, 0, len(res.ReadEndpoints)+1)
+	if res.ClusterEnabled && res.ClusterConfig != nil {
+		targets = append(targets, checkTarget{"cluster", res.ClusterConfig.Host, res.ClusterConfig.PortString()})
+	} else if res.Primary != nil {
+		targets = append(targets, checkTarget{"primary", res.Primary.Host, res.Primary.PortString()})
+	}
+	for _, r := range res.ReadEndpoints {
+		targets = append(targets, checkTarget{"reader", r.Host, r.PortString()})
+	}
+
+	return checkEndpoints(ctx, res.IPNetwork(), targets, opts)
+}
+
+// Check resolves the hostname of the cluster endpoints and every instance and dials them
+// over TCP, so that stale DNS or security group issues are detected right after discovery
+// rather than on first use
+func (aes *AuroraEndpoints) Check(ctx context.Context) EndpointHealthReport {
+	return aes.CheckWith(ctx, nil)
+}
+
+// CheckWith is Check with options, e.g. to complete a TLS handshake
+func (aes *AuroraEndpoints) CheckWith(ctx context.Context, opts *CheckOptions) EndpointHealthReport {
+	targets := make([]checkTarget, 0, len(aes.ReadEndpoints)+3)
+	if aes.Writer != nil {
+		targets = append(targets, checkTarget{"writer", aes.Writer.Host, aes.Writer.PortString()})
+	}
+	if aes.Reader != nil {
+		targets = append(targets, checkTarget{"reader", aes.Reader.Host, aes.Reader.PortString()})
+	}
+	if aes.WriterInstance != nil {
+		targets = append(targets, checkTarget{"instance", aes.WriterInstance.Host, aes.WriterInstance.PortString()})
+	}
+	for _, r := range aes.ReadEndpoints {
+		targets = append(targets, checkTarget{"instance", r.Host, r.PortString()})
+	}
+
+	return checkEndpoints(ctx, aes.IPNetwork(), targets, opts)
+}
+
+type checkTarget struct {
+	role, host, port string
+}
+
+// checkEndpoints checks the targets concurrently, returning the results in target order
+func checkEndpoints(ctx context.Context, network string, targets []checkTarget, opts *CheckOptions) EndpointHealthReport {
+	if opts == nil {
+		opts = &CheckOptions{}
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	report := make(EndpointHealthReport, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t checkTarget) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			report[i] = checkEndpoint(ctx, network, t, opts.TLSConfig)
+		}(i, t)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func checkEndpoint(ctx context.Context, network string, t checkTarget, tlsConfig *tls.Config) (h EndpointHealth) {
+	h = EndpointHealth{Role: t.role, Endpoint: net.JoinHostPort(t.host, t.port)}
+	start := time.Now()
+	defer func() { h.Latency = time.Since(start) }()
+
+	h.Addrs, h.Err = ResolveHost(ctx, t.host, network)
+	if h.Err != nil {
+		return h
+	}
+	if len(h.Addrs) == 0 {
+		h.Err = errors.New("no addresses found for " + t.host)
+		return h
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(h.Addrs[0], t.port))
+	if err != nil {
+		h.Err = err
+		return h
+	}
+	defer conn.Close()
+
+	if tlsConfig != nil {
+		cfg := tlsConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName = t.host
+		}
+		tc := tls.Client(conn, cfg)
+		if h.Err = tc.HandshakeContext(ctx); h.Err != nil {
+			return h
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,82 @@
+package awsx
+
+import (
+	"time"
+)
+
+// Sleeper pauses the calling goroutine for a duration
+type Sleeper interface {
+	Sleep(d time.Duration)
+}
+
+// Clock is the source of time for everything in awsx that waits or polls: snapshot
+// and lifecycle pollers, SDK waiters, and sweep backoff. Tests can set a fake Clock
+// with SetClock, such as awsxtest.Clock, to fast-forward time without real sleeps.
+type Clock interface {
+	Sleeper
+	Now() time.Time
+}
+
+// RealClock is the Clock backed by the time package
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// SetClock sets the Clock used by pollers, waiters and backoff
+func (a *Config) SetClock(clock Clock) *Config {
+	a.Clock = clock
+	return a
+}
+
+// clock returns the configured Clock or the RealClock
+func (a *Config) clock() Clock {
+	if a.Clock == nil {
+		return RealClock
+	}
+	return a.Clock
+}
+
+// since returns the time elapsed since t on the configured Clock
+func (a *Config) since(t time.Time) time.Duration {
+	return a.clock().Now().Sub(t)
+}
+
+// wait sleeps for d on the configured Clock and reports whether the full duration
+// passed, returning false as soon as stop is closed. Pollers use it so that Stop and
+// context cancellation take effect without waiting out the poll interval.
+func (a *Config) wait(d time.Duration, stop <-chan struct{}) bool {
+	if d <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+
+	if a.Clock == nil {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		a.Clock.Sleep(d)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-stop:
+		return false
+	}
+}
@@ -0,0 +1,199 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/servicediscovery/servicediscoveryiface"
+)
+
+const (
+	// CloudMapRoleAttribute is the custom instance attribute holding the role of a
+	// self-managed node registered in Cloud Map: primary or writer for the node taking
+	// writes, anything else for replicas
+	CloudMapRoleAttribute = "role"
+
+	cloudMapHost = "AWS_INSTANCE_CNAME"
+	cloudMapIPv4 = "AWS_INSTANCE_IPV4"
+	cloudMapIPv6 = "AWS_INSTANCE_IPV6"
+	cloudMapPort = "AWS_INSTANCE_PORT"
+	cloudMapAZ   = "AVAILABILITY_ZONE"
+)
+
+// GetCloudMapClient returns a client for use with AWS Cloud Map
+func (a *Config) GetCloudMapClient() servicediscoveryiface.ServiceDiscoveryAPI {
+	return a.Service.CloudMap
+}
+
+// SetCloudMapClient sets a client for use with AWS Cloud Map
+func (a *Config) SetCloudMapClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.CloudMap = servicediscovery.New(a.ClientConfig(servicediscovery.EndpointsID))
+
+	return a
+}
+
+// WithCloudMapClient sets the client used for AWS Cloud Map calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithCloudMapClient(client servicediscoveryiface.ServiceDiscoveryAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.CloudMap = client
+
+	return a
+}
+
+// cloudMapInstance is a discovered instance with the attributes awsx uses
+type cloudMapInstance struct {
+	id, host, port, az string
+	primary            bool
+}
+
+// GetCloudMapRedisEndpoints resolves the healthy instances of a Cloud Map service into
+// RedisEndpoints, so that self-managed Redis registered in Cloud Map is consumed through
+// the same API as ElastiCache. The instance with the role attribute set to primary is the
+// primary, every other instance a reader.
+func (a *Config) GetCloudMapRedisEndpoints(namespace, service string) (*RedisEndpoints, error) {
+	res, err := a.traced("cloudmap", namespace+"/"+service, func(ctx context.Context) (interface{}, error) {
+		return a.cached("cloudmap-redis:"+namespace+"/"+service, func() (interface{}, error) {
+			instances, err := a.discoverCloudMapInstances(ctx, namespace, service, "")
+			if err != nil {
+				return nil, err
+			}
+
+			res := &RedisEndpoints{ReadEndpoints: make([]*RedisEndpoint, 0)}
+			for _, i := range instances {
+				port, err := ParsePort(i.port)
+				if err != nil {
+					return nil, errors.New("instance " + i.id + " of " + service + ": " + err.Error())
+				}
+				entry := &RedisEndpoint{Host: i.host, Port: port, AvailabilityZone: i.az, CacheClusterID: i.id}
+				if i.primary && res.Primary == nil {
+					entry.Role = RolePrimary
+					res.Primary = entry
+					continue
+				}
+				entry.Role = RoleReplica
+				res.ReadEndpoints = append(res.ReadEndpoints, entry)
+			}
+			if res.Primary == nil {
+				return nil, errors.New("no instance of " + namespace + "/" + service + " has " + CloudMapRoleAttribute + "=primary")
+			}
+			res.ReplicationGroup = len(res.ReadEndpoints) > 0
+			res.ReadReplicas = len(res.ReadEndpoints) > 0
+
+			return res, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*RedisEndpoints), nil
+}
+
+// GetCloudMapAuroraEndpoints resolves the healthy instances of a Cloud Map service into
+// AuroraEndpoints, so that self-managed PostgreSQL or MySQL registered in Cloud Map is
+// consumed through the same API as Aurora. The instance with the role attribute set to
+// writer or primary is the writer, every other instance a reader. As there is no reader
+// endpoint, Reader is the first reader, or the writer when there are no readers.
+func (a *Config) GetCloudMapAuroraEndpoints(namespace, service string) (*AuroraEndpoints, error) {
+	aes, err := a.traced("cloudmap", namespace+"/"+service, func(ctx context.Context) (interface{}, error) {
+		return a.cached("cloudmap-aurora:"+namespace+"/"+service, func() (interface{}, error) {
+			instances, err := a.discoverCloudMapInstances(ctx, namespace, service, "")
+			if err != nil {
+				return nil, err
+			}
+
+			aes := &AuroraEndpoints{Cluster: service, ReadEndpoints: make([]*AuroraEndpoint, 0)}
+			for _, i := range instances {
+				port, err := ParsePort(i.port)
+				if err != nil {
+					return nil, errors.New("instance " + i.id + " of " + service + ": " + err.Error())
+				}
+				entry := &AuroraEndpoint{Host: i.host, Port: port, Instance: i.id}
+				if i.primary && aes.Writer == nil {
+					aes.Writer = entry
+					aes.WriterInstance = entry
+					continue
+				}
+				aes.ReadEndpoints = append(aes.ReadEndpoints, entry)
+			}
+			if aes.Writer == nil {
+				return nil, errors.New("no instance of " + namespace + "/" + service + " has " + CloudMapRoleAttribute + "=writer")
+			}
+			aes.Reader = aes.Writer
+			if len(aes.ReadEndpoints) > 0 {
+				aes.Reader = aes.ReadEndpoints[0]
+				aes.ReadReplicas = true
+			}
+
+			return aes, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return aes.(*AuroraEndpoints), nil
+}
+
+// discoverCloudMapInstances returns the healthy instances of the service sorted by ID, or
+// every instance when none is healthy. Instances registered without a port get
+// defaultPort.
+func (a *Config) discoverCloudMapInstances(ctx context.Context, namespace, service, defaultPort string) ([]cloudMapInstance, error) {
+	if namespace == "" || service == "" {
+		return nil, errors.New("must provide a namespace and service name")
+	}
+
+	if a.Service.CloudMap == nil {
+		a.SetCloudMapClient()
+	}
+
+	out, err := a.Service.CloudMap.DiscoverInstancesWithContext(ctx, &servicediscovery.DiscoverInstancesInput{
+		NamespaceName: aws.String(namespace),
+		ServiceName:   aws.String(service),
+		HealthStatus:  aws.String(servicediscovery.HealthStatusFilterHealthyOrElseAll),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Instances) == 0 {
+		return nil, errors.New("no instances registered for " + namespace + "/" + service)
+	}
+
+	instances := make([]cloudMapInstance, 0, len(out.Instances))
+	for _, summary := range out.Instances {
+		attrs := aws.StringValueMap(summary.Attributes)
+		i := cloudMapInstance{
+			id:   aws.StringValue(summary.InstanceId),
+			host: attrs[cloudMapHost],
+			port: attrs[cloudMapPort],
+			az:   attrs[cloudMapAZ],
+		}
+		if i.host == "" {
+			i.host = attrs[cloudMapIPv4]
+		}
+		if i.host == "" {
+			i.host = attrs[cloudMapIPv6]
+		}
+		if i.port == "" {
+			i.port = defaultPort
+		}
+		if i.host == "" || i.port == "" {
+			return nil, errors.New("instance " + i.id + " of " + namespace + "/" + service + " has no address or port")
+		}
+		role := attrs[CloudMapRoleAttribute]
+		i.primary = role == "primary" || role == "writer"
+		instances = append(instances, i)
+	}
+	sort.Slice(instances, func(x, y int) bool { return instances[x].id < instances[y].id })
+
+	return instances, nil
+}
@@ -0,0 +1,309 @@
+package awsx
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+const (
+	// metricLookback is how far back the latest datapoint of a metric is looked for
+	metricLookback = 5 * time.Minute
+	// highResolutionRetention is how long CloudWatch keeps one minute datapoints
+	highResolutionRetention = 15 * 24 * time.Hour
+	// maxMetricQueries is the maximum number of queries of a GetMetricData call
+	maxMetricQueries = 500
+)
+
+// GetCloudWatchClient returns a client for use with AWS CloudWatch
+func (a *Config) GetCloudWatchClient() cloudwatchiface.CloudWatchAPI {
+	return a.Service.Cw
+}
+
+// SetCloudWatchClient sets a client for use with AWS CloudWatch
+func (a *Config) SetCloudWatchClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Cw = cloudwatch.New(a.ClientConfig(cloudwatch.EndpointsID))
+
+	return a
+}
+
+// WithCloudWatchClient sets the client used for AWS CloudWatch calls, such as a mock
+// from the awsxmock package
+func (a *Config) WithCloudWatchClient(client cloudwatchiface.CloudWatchAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Cw = client
+
+	return a
+}
+
+// DefaultECMetrics are the ElastiCache metrics fetched by GetECMetrics when none are given
+var DefaultECMetrics = []string{"CPUUtilization", "CurrConnections", "Evictions", "ReplicationLag"}
+
+// DefaultRDSMetrics are the RDS metrics fetched by GetRDSMetrics when none are given
+var DefaultRDSMetrics = []string{"DatabaseConnections", "CPUUtilization", "FreeableMemory", "AuroraReplicaLag"}
+
+// MetricSeries is the datapoints of a CloudWatch metric for one node or instance, oldest
+// first
+type MetricSeries struct {
+	Metric string        `json:"metric" yaml:"metric"`
+	ID     string        `json:"id" yaml:"id"` // cache cluster (node) or DB instance ID
+	Period time.Duration `json:"period" yaml:"period"`
+	Points []MetricPoint `json:"points" yaml:"points"`
+}
+
+// MetricPoint is a datapoint of a MetricSeries, the average over the period from Time
+type MetricPoint struct {
+	Time  time.Time `json:"time" yaml:"time"`
+	Value float64   `json:"value" yaml:"value"`
+}
+
+// Latest returns the most recent value of the series and whether there is one
+func (s *MetricSeries) Latest() (float64, bool) {
+	if s == nil || len(s.Points) == 0 {
+		return 0, false
+	}
+	return s.Points[len(s.Points)-1].Value, true
+}
+
+// GetECMetrics returns the metrics of every node of the replication group or cache
+// cluster over the last period, keyed by node ID and then metric name. Metrics default to
+// DefaultECMetrics. Datapoints are one minute averages, or coarser for periods longer than
+// a day so the series stay a manageable size. Nodes and metrics without datapoints are
+// returned with empty series.
+func (a *Config) GetECMetrics(cluster string, metricNames []string, period time.Duration) (map[string]map[string]*MetricSeries, error) {
+	if cluster == "" {
+		return nil, errors.New("no cluster name provided")
+	}
+	if len(metricNames) == 0 {
+		metricNames = DefaultECMetrics
+	}
+
+	nodes := []string{cluster}
+	result, count, err := a.GetECReplicationGroupE(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if count == 1 {
+		nodes = aws.StringValueSlice(result.ReplicationGroups[0].MemberClusters)
+	}
+
+	return a.metricSeries("AWS/ElastiCache", "CacheClusterId", nodes, metricNames, period)
+}
+
+// GetRDSMetrics returns the metrics of every instance of the Aurora DB cluster over the
+// last period, keyed by instance ID and then metric name, like GetECMetrics. Metrics
+// default to DefaultRDSMetrics. An identifier that is not a DB cluster is taken as a DB
+// instance ID.
+func (a *Config) GetRDSMetrics(cluster string, metricNames []string, period time.Duration) (map[string]map[string]*MetricSeries, error) {
+	if cluster == "" {
+		return nil, errors.New("no cluster name provided")
+	}
+	if len(metricNames) == 0 {
+		metricNames = DefaultRDSMetrics
+	}
+
+	instances := []string{cluster}
+	if result, err := a.GetRDSClusterDetails(cluster); err == nil && len(result.DBClusters) == 1 {
+		instances = make([]string, 0, len(result.DBClusters[0].DBClusterMembers))
+		for _, m := range result.DBClusters[0].DBClusterMembers {
+			instances = append(instances, aws.StringValue(m.DBInstanceIdentifier))
+		}
+	}
+
+	return a.metricSeries("AWS/RDS", "DBInstanceIdentifier", instances, metricNames, period)
+}
+
+// GetRedisReplicationLag returns the latest ReplicationLag of each ElastiCache cache
+// cluster (node) ID. Nodes without a recent datapoint, such as the primary, are left out.
+func (a *Config) GetRedisReplicationLag(cacheClusterIDs []string) (map[string]time.Duration, error) {
+	values, err := a.latestMetricValues("AWS/ElastiCache", "ReplicationLag", "CacheClusterId", cacheClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lag := make(map[string]time.Duration, len(values))
+	for id, v := range values {
+		lag[id] = time.Duration(v * float64(time.Second))
+	}
+	return lag, nil
+}
+
+// GetRDSReplicaLag returns the latest replica lag of each DB instance ID, taken from
+// AuroraReplicaLag for Aurora replicas and ReplicaLag for RDS read replicas. Instances
+// without a recent datapoint, such as writers, are left out.
+func (a *Config) GetRDSReplicaLag(instanceIDs []string) (map[string]time.Duration, error) {
+	replica, err := a.latestMetricValues("AWS/RDS", "ReplicaLag", "DBInstanceIdentifier", instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+	aurora, err := a.latestMetricValues("AWS/RDS", "AuroraReplicaLag", "DBInstanceIdentifier", instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lag := make(map[string]time.Duration, len(instanceIDs))
+	for id, v := range replica {
+		lag[id] = time.Duration(v * float64(time.Second))
+	}
+	for id, v := range aurora {
+		// AuroraReplicaLag is reported in milliseconds
+		lag[id] = time.Duration(v * float64(time.Millisecond))
+	}
+	return lag, nil
+}
+
+// metricSeries returns the series of each metric for each value of the dimension over the
+// last period, fetching as many series per GetMetricData call as allowed
+func (a *Config) metricSeries(namespace, dimension string, ids, metrics []string, period time.Duration) (map[string]map[string]*MetricSeries, error) {
+	if period <= 0 {
+		return nil, errors.New("metric period must be positive")
+	}
+	if a.Service.Cw == nil {
+		a.SetCloudWatchClient()
+	}
+
+	step := metricResolution(period)
+	series := make([]*MetricSeries, 0, len(ids)*len(metrics))
+	result := make(map[string]map[string]*MetricSeries, len(ids))
+	for _, id := range ids {
+		result[id] = make(map[string]*MetricSeries, len(metrics))
+		for _, m := range metrics {
+			s := &MetricSeries{Metric: m, ID: id, Period: step, Points: make([]MetricPoint, 0)}
+			result[id][m] = s
+			series = append(series, s)
+		}
+	}
+
+	end := a.clock().Now()
+	for start := 0; start < len(series); start += maxMetricQueries {
+		chunk := series[start:]
+		if len(chunk) > maxMetricQueries {
+			chunk = chunk[:maxMetricQueries]
+		}
+
+		queries := make([]*cloudwatch.MetricDataQuery, 0, len(chunk))
+		for i, s := range chunk {
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String("m" + strconv.Itoa(i)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(namespace),
+						MetricName: aws.String(s.Metric),
+						Dimensions: []*cloudwatch.Dimension{{Name: aws.String(dimension), Value: aws.String(s.ID)}},
+					},
+					Period: aws.Int64(int64(step / time.Second)),
+					Stat:   aws.String("Average"),
+				},
+			})
+		}
+
+		input := &cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-period)),
+			EndTime:           aws.Time(end),
+			ScanBy:            aws.String(cloudwatch.ScanByTimestampAscending),
+			MetricDataQueries: queries,
+		}
+		for {
+			out, err := a.Service.Cw.GetMetricData(input)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, r := range out.MetricDataResults {
+				i, err := strconv.Atoi(aws.StringValue(r.Id)[1:])
+				if err != nil || i >= len(chunk) {
+					continue
+				}
+				for j := 0; j < len(r.Values) && j < len(r.Timestamps); j++ {
+					chunk[i].Points = append(chunk[i].Points, MetricPoint{
+						Time:  aws.TimeValue(r.Timestamps[j]),
+						Value: aws.Float64Value(r.Values[j]),
+					})
+				}
+			}
+
+			if aws.StringValue(out.NextToken) == "" {
+				break
+			}
+			input.NextToken = out.NextToken
+		}
+	}
+
+	return result, nil
+}
+
+// metricResolution returns the datapoint period for series covering the period: one
+// minute up to a day, then about 1440 datapoints in whole minutes, and at least five
+// minutes once one minute datapoints are no longer retained
+func metricResolution(period time.Duration) time.Duration {
+	step := time.Minute
+	if period > 24*time.Hour {
+		step = (period / 1440).Truncate(time.Minute)
+	}
+	if period > highResolutionRetention && step < 5*time.Minute {
+		step = 5 * time.Minute
+	}
+	return step
+}
+
+// latestMetricValues returns the most recent one minute average of the metric for each
+// value of the dimension, leaving out values without a datapoint in the lookback window
+func (a *Config) latestMetricValues(namespace, metric, dimension string, values []string) (map[string]float64, error) {
+	if a.Service.Cw == nil {
+		a.SetCloudWatchClient()
+	}
+
+	latest := make(map[string]float64, len(values))
+	end := a.clock().Now()
+	for start := 0; start < len(values); start += maxMetricQueries {
+		chunk := values[start:]
+		if len(chunk) > maxMetricQueries {
+			chunk = chunk[:maxMetricQueries]
+		}
+
+		queries := make([]*cloudwatch.MetricDataQuery, 0, len(chunk))
+		for i, v := range chunk {
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String("m" + strconv.Itoa(i)),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(namespace),
+						MetricName: aws.String(metric),
+						Dimensions: []*cloudwatch.Dimension{{Name: aws.String(dimension), Value: aws.String(v)}},
+					},
+					Period: aws.Int64(60),
+					Stat:   aws.String("Average"),
+				},
+			})
+		}
+
+		result, err := a.Service.Cw.GetMetricData(&cloudwatch.GetMetricDataInput{
+			StartTime:         aws.Time(end.Add(-metricLookback)),
+			EndTime:           aws.Time(end),
+			ScanBy:            aws.String(cloudwatch.ScanByTimestampDescending),
+			MetricDataQueries: queries,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range result.MetricDataResults {
+			i, err := strconv.Atoi(aws.StringValue(r.Id)[1:])
+			if err != nil || i >= len(chunk) || len(r.Values) == 0 {
+				continue
+			}
+			latest[chunk[i]] = aws.Float64Value(r.Values[0])
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,92 @@
+// Command awsx exposes awsx endpoint discovery to shell scripts:
+//
+//	awsx [flags] redis endpoints <cluster>
+//	awsx [flags] rds endpoints <cluster>
+//	awsx [flags] whoami
+//
+// Flags:
+//
+//	-region   AWS region, detected if empty
+//	-profile  shared credentials profile
+//	-o        output format: plain (default), json or env
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/routebyintuition/awsx"
+)
+
+const usage = `usage: awsx [flags] <command>
+
+commands:
+  redis endpoints <cluster>   discover the endpoints of an ElastiCache Redis cluster
+  rds endpoints <cluster>     discover the endpoints of an Aurora DB cluster
+  whoami                      show the identity of the credential chain
+
+flags:
+`
+
+func main() {
+	region := flag.String("region", "", "AWS region, detected if empty")
+	profile := flag.String("profile", "", "shared credentials profile")
+	output := flag.String("o", "plain", "output format: plain, json or env")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	out, err := newPrinter(*output, os.Stdout)
+	if err != nil {
+		fail(err)
+	}
+
+	a := awsx.NewAWS()
+	if *profile != "" {
+		a.SetProfile(*profile)
+	}
+	a.WithAllProviders()
+	if *region != "" {
+		a.SetRegion(*region)
+	}
+	a.SetSession()
+
+	args := flag.Args()
+	switch {
+	case len(args) == 3 && args[0] == "redis" && args[1] == "endpoints":
+		res, err := a.GetRedisPrimaryEndpoint(args[2])
+		if err != nil {
+			fail(err)
+		}
+		if err := out.redis(res); err != nil {
+			fail(err)
+		}
+	case len(args) == 3 && args[0] == "rds" && args[1] == "endpoints":
+		aes, err := a.GetAuroraEndpoints(args[2])
+		if err != nil {
+			fail(err)
+		}
+		if err := out.aurora(aes); err != nil {
+			fail(err)
+		}
+	case len(args) == 1 && args[0] == "whoami":
+		id, err := a.WhoAmI()
+		if err != nil {
+			fail(err)
+		}
+		if err := out.identity(id); err != nil {
+			fail(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "awsx:", err)
+	os.Exit(1)
+}
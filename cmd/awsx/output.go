@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/routebyintuition/awsx"
+)
+
+// printer writes discovery results in one of the output formats
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	switch format {
+	case "plain", "json", "env":
+		return &printer{format: format, w: w}, nil
+	}
+	return nil, errors.New("unknown output format " + format)
+}
+
+func (p *printer) redis(res *awsx.RedisEndpoints) error {
+	if p.format == "json" {
+		return p.json(res)
+	}
+	return p.pairs(res.Env())
+}
+
+func (p *printer) aurora(aes *awsx.AuroraEndpoints) error {
+	if p.format == "json" {
+		return p.json(aes)
+	}
+	return p.pairs(aes.Env())
+}
+
+func (p *printer) identity(id *awsx.CallerIdentity) error {
+	if p.format == "json" {
+		return p.json(id)
+	}
+
+	return p.pairs([][2]string{
+		{"AWS_ACCOUNT_ID", id.Account},
+		{"AWS_CALLER_ARN", id.ARN},
+		{"AWS_USER_ID", id.UserID},
+		{"AWS_CREDENTIAL_PROVIDER", id.Provider},
+	})
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// pairs prints name/value pairs as shell-quoted assignments for env, or the values one
+// per line for plain
+func (p *printer) pairs(pairs [][2]string) error {
+	for _, kv := range pairs {
+		var err error
+		if p.format == "env" {
+			_, err = fmt.Fprintf(p.w, "%s='%s'\n", kv[0], strings.Replace(kv[1], "'", `'\''`, -1))
+		} else {
+			_, err = fmt.Fprintln(p.w, kv[1])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
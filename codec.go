@@ -0,0 +1,238 @@
+package awsx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// StateSchemaVersion is the schema version written by EncodeState. Files written with
+// an older version are upgraded on decode by the registered StateMigrations.
+const StateSchemaVersion = 1
+
+// stateMagic starts every encoded state file
+const stateMagic = "AWSX"
+
+// Compression names used in StateFormat
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+)
+
+// Codec marshals the persisted state of awsx, such as cached topology and history
+type Codec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StateMigration upgrades a decompressed payload from the schema version it was
+// registered for to the next version. The payload is encoded with codec.
+type StateMigration func(payload []byte, codec Codec) ([]byte, error)
+
+// StateFormat selects how state is encoded
+type StateFormat struct {
+	Codec       Codec  // defaults to JSONCodec
+	Compression string // CompressionNone or CompressionGzip
+}
+
+// JSONCodec encodes state as JSON, the default and the easiest to inspect
+type JSONCodec struct{}
+
+// Name returns "json"
+func (JSONCodec) Name() string { return "json" }
+
+// Marshal encodes v as JSON
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal decodes JSON into v
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// GobCodec encodes state with encoding/gob, which is smaller and faster to load than
+// JSON for large multi-account state
+type GobCodec struct{}
+
+// Name returns "gob"
+func (GobCodec) Name() string { return "gob" }
+
+// Marshal encodes v with gob
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data into v
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+var (
+	codecsMu   sync.RWMutex
+	codecs     = map[string]Codec{"json": JSONCodec{}, "gob": GobCodec{}}
+	migrations = map[int]StateMigration{}
+)
+
+// RegisterCodec makes a Codec available for decoding state files by name
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[c.Name()] = c
+}
+
+// RegisterStateMigration registers the migration from schema version from to from+1
+func RegisterStateMigration(from int, m StateMigration) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	migrations[from] = m
+}
+
+// EncodeState encodes v with a header recording the schema version, codec and
+// compression so DecodeState can read it back regardless of the current format
+func EncodeState(v interface{}, f StateFormat) ([]byte, error) {
+	if f.Codec == nil {
+		f.Codec = JSONCodec{}
+	}
+
+	payload, err := f.Codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.Compression {
+	case CompressionNone:
+	case CompressionGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		payload = buf.Bytes()
+	default:
+		return nil, errors.New("unknown state compression " + f.Compression)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(stateMagic)
+	binary.Write(&buf, binary.BigEndian, uint32(StateSchemaVersion))
+	writeField(&buf, f.Codec.Name())
+	writeField(&buf, f.Compression)
+	buf.Write(payload)
+
+	return buf.Bytes(), nil
+}
+
+// DecodeState decodes state written by EncodeState into v, migrating it to the current
+// schema version first if needed
+func DecodeState(data []byte, v interface{}) error {
+	r := bytes.NewReader(data)
+
+	magic := make([]byte, len(stateMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != stateMagic {
+		return errors.New("not an awsx state file")
+	}
+
+	var schema uint32
+	if err := binary.Read(r, binary.BigEndian, &schema); err != nil {
+		return err
+	}
+	codecName, err := readField(r)
+	if err != nil {
+		return err
+	}
+	compression, err := readField(r)
+	if err != nil {
+		return err
+	}
+
+	codecsMu.RLock()
+	codec, ok := codecs[codecName]
+	codecsMu.RUnlock()
+	if !ok {
+		return errors.New("unknown state codec " + codecName)
+	}
+
+	payload, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	switch compression {
+	case CompressionNone:
+	case CompressionGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		payload, err = ioutil.ReadAll(zr)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.New("unknown state compression " + compression)
+	}
+
+	if int(schema) > StateSchemaVersion {
+		return fmt.Errorf("state schema version %d is newer than supported version %d", schema, StateSchemaVersion)
+	}
+	for version := int(schema); version < StateSchemaVersion; version++ {
+		codecsMu.RLock()
+		m, ok := migrations[version]
+		codecsMu.RUnlock()
+		if !ok {
+			return fmt.Errorf("no state migration registered from schema version %d", version)
+		}
+		if payload, err = m(payload, codec); err != nil {
+			return err
+		}
+	}
+
+	return codec.Unmarshal(payload, v)
+}
+
+// WriteStateFile encodes v and atomically replaces the file at path
+func WriteStateFile(path string, v interface{}, f StateFormat) error {
+	data, err := EncodeState(v, f)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// ReadStateFile decodes the state file at path into v
+func ReadStateFile(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return DecodeState(data, v)
+}
+
+func writeField(buf *bytes.Buffer, s string) {
+	buf.WriteByte(byte(len(s)))
+	buf.WriteString(s)
+}
+
+func readField(r *bytes.Reader) (string, error) {
+	n, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,46 @@
+package awsx
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// WithContext returns a copy of the Config whose AWS calls are made with ctx, so they
+// are cancelled with it and traced as its children. The copy shares the discovery cache
+// of the Config:
+//
+//	res, err := a.WithContext(ctx).GetRedisPrimaryEndpoint("cluster-name")
+func (a *Config) WithContext(ctx context.Context) *Config {
+	if a.Session == nil {
+		a.SetSession()
+	}
+	a.results()
+
+	c := *a
+	c.ctx = ctx
+	c.Service = &Services{}
+	if a.Session != nil {
+		c.Session = a.Session.Copy()
+		c.Session.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+			Name: "awsx.context",
+			Fn: func(r *request.Request) {
+				// calls made with an SDK *WithContext operation keep their own context
+				if r.Context() == aws.BackgroundContext() {
+					r.SetContext(ctx)
+				}
+			},
+		})
+	}
+
+	return &c
+}
+
+// context returns the context set with WithContext, or the background context
+func (a *Config) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
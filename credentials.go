@@ -0,0 +1,152 @@
+package awsx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// lazyProvider defers building a credential provider until credentials are first
+// retrieved, so that adding providers to the chain never touches the network or the
+// metadata service. A provider that fails to build fails the Retrieve, letting the chain
+// move on to the next provider, and is built again on the next Retrieve.
+type lazyProvider struct {
+	build func() (credentials.Provider, error)
+
+	mu       sync.Mutex
+	provider credentials.Provider
+}
+
+// newLazyProvider returns a provider built by build on first use
+func newLazyProvider(build func() (credentials.Provider, error)) *lazyProvider {
+	return &lazyProvider{build: build}
+}
+
+// Retrieve builds the provider if needed and retrieves its credentials
+func (p *lazyProvider) Retrieve() (credentials.Value, error) {
+	provider, err := p.get()
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	return provider.Retrieve()
+}
+
+// get returns the provider, building it if it has not been built successfully yet
+func (p *lazyProvider) get() (credentials.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider == nil {
+		provider, err := p.build()
+		if err != nil {
+			return nil, err
+		}
+		p.provider = provider
+	}
+	return p.provider, nil
+}
+
+// built returns the provider, or nil if it has not been built
+func (p *lazyProvider) built() credentials.Provider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.provider
+}
+
+// IsExpired reports whether the credentials have expired, which is always the case
+// before the provider has been built
+func (p *lazyProvider) IsExpired() bool {
+	provider := p.built()
+	if provider == nil {
+		return true
+	}
+	return provider.IsExpired()
+}
+
+// ExpiresAt returns the expiry of the built provider's credentials, or the zero time if
+// the provider has not been built or does not know when its credentials expire
+func (p *lazyProvider) ExpiresAt() time.Time {
+	if e, ok := p.built().(expirer); ok {
+		return e.ExpiresAt()
+	}
+	return time.Time{}
+}
+
+// stsSession returns a session for STS calls made by credential providers, signed with
+// creds if not nil. It uses the region, STS endpoint and HTTP client of the Config, and
+// the regional STS endpoint so that it works in every partition.
+func (a *Config) stsSession(creds *credentials.Credentials) (*session.Session, error) {
+	cfg := aws.NewConfig().WithSTSRegionalEndpoint(endpoints.RegionalSTSEndpoint)
+	if creds != nil {
+		cfg.WithCredentials(creds)
+	}
+	if region, err := a.ResolveRegion(); err == nil {
+		cfg.WithRegion(region)
+	}
+	if endpoint, ok := a.ServiceEndpoints[sts.EndpointsID]; ok && endpoint != "" {
+		cfg.WithEndpoint(endpoint)
+	}
+	if a.HTTPClient != nil {
+		cfg.WithHTTPClient(a.HTTPClient)
+	}
+
+	return session.NewSession(cfg)
+}
+
+// chainProvider is the credential chain of a Config. Unlike credentials.ChainProvider it
+// forwards ExpiresAt to the provider that returned the current credentials, so the
+// session credentials report their real expiry.
+type chainProvider struct {
+	providers []credentials.Provider
+
+	mu   sync.Mutex
+	curr credentials.Provider
+}
+
+// Retrieve returns the credentials of the first provider in the chain that has them
+func (c *chainProvider) Retrieve() (credentials.Value, error) {
+	for _, p := range c.providers {
+		v, err := p.Retrieve()
+		if err == nil {
+			c.mu.Lock()
+			c.curr = p
+			c.mu.Unlock()
+			return v, nil
+		}
+	}
+
+	c.mu.Lock()
+	c.curr = nil
+	c.mu.Unlock()
+	return credentials.Value{ProviderName: "ChainProvider"}, credentials.ErrNoValidProvidersFoundInChain
+}
+
+// IsExpired reports whether the credentials of the current provider have expired
+func (c *chainProvider) IsExpired() bool {
+	c.mu.Lock()
+	curr := c.curr
+	c.mu.Unlock()
+
+	if curr == nil {
+		return true
+	}
+	return curr.IsExpired()
+}
+
+// ExpiresAt returns the expiry of the current provider's credentials, or the zero time
+// if it does not know when they expire
+func (c *chainProvider) ExpiresAt() time.Time {
+	c.mu.Lock()
+	curr := c.curr
+	c.mu.Unlock()
+
+	if e, ok := curr.(expirer); ok {
+		return e.ExpiresAt()
+	}
+	return time.Time{}
+}
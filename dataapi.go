@@ -0,0 +1,180 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rdsdataservice"
+	"github.com/aws/aws-sdk-go/service/rdsdataservice/rdsdataserviceiface"
+)
+
+// dataAPITimeFormat is the format of TIMESTAMP parameters of the RDS Data API
+const dataAPITimeFormat = "2006-01-02 15:04:05.999999"
+
+// GetDataAPIClient returns a client for use with the RDS Data API
+func (a *Config) GetDataAPIClient() rdsdataserviceiface.RDSDataServiceAPI {
+	return a.Service.Data
+}
+
+// SetDataAPIClient sets a client for use with the RDS Data API
+func (a *Config) SetDataAPIClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Data = rdsdataservice.New(a.ClientConfig(rdsdataservice.EndpointsID))
+
+	return a
+}
+
+// WithDataAPIClient sets the client used for RDS Data API calls, such as a mock
+func (a *Config) WithDataAPIClient(client rdsdataserviceiface.RDSDataServiceAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Data = client
+
+	return a
+}
+
+// DataAPIStatement is a SQL statement run by ExecuteStatement
+type DataAPIStatement struct {
+	ClusterARN    string                 // ARN of the Aurora cluster, see AuroraEndpoints.ARN
+	SecretARN     string                 // ARN of the Secrets Manager secret with the database credentials
+	SQL           string                 // the statement, with :name placeholders for Params
+	Database      string                 // optional: database to run the statement in
+	Schema        string                 // optional: schema, PostgreSQL only
+	Params        map[string]interface{} // optional: string, bool, int, int64, float64, []byte, time.Time or nil
+	TransactionID string                 // optional: run within a transaction started with BeginTransaction
+}
+
+// StatementResult is the result of ExecuteStatement. Rows hold string, bool, int64,
+// float64, []byte or nil values.
+type StatementResult struct {
+	Columns     []string
+	Rows        [][]interface{}
+	RowsUpdated int64
+	Generated   []interface{}
+}
+
+// ExecuteStatement runs a SQL statement over the RDS Data API, for Aurora Serverless
+// users who don't hold persistent connections. The Data API must be enabled on the
+// cluster, see AuroraEndpoints.DataAPI.
+func (a *Config) ExecuteStatement(ctx context.Context, stmt *DataAPIStatement) (*StatementResult, error) {
+	if stmt == nil || stmt.ClusterARN == "" || stmt.SecretARN == "" || stmt.SQL == "" {
+		return nil, errors.New("must provide a cluster arn, secret arn and sql")
+	}
+
+	if a.Service.Data == nil {
+		a.SetDataAPIClient()
+	}
+
+	input := &rdsdataservice.ExecuteStatementInput{
+		ResourceArn:           aws.String(stmt.ClusterARN),
+		SecretArn:             aws.String(stmt.SecretARN),
+		Sql:                   aws.String(stmt.SQL),
+		IncludeResultMetadata: aws.Bool(true),
+	}
+	if stmt.Database != "" {
+		input.Database = aws.String(stmt.Database)
+	}
+	if stmt.Schema != "" {
+		input.Schema = aws.String(stmt.Schema)
+	}
+	if stmt.TransactionID != "" {
+		input.TransactionId = aws.String(stmt.TransactionID)
+	}
+	for name, v := range stmt.Params {
+		p, err := dataAPIParameter(name, v)
+		if err != nil {
+			return nil, err
+		}
+		input.Parameters = append(input.Parameters, p)
+	}
+
+	result, err := a.Service.Data.ExecuteStatementWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &StatementResult{
+		Columns:     make([]string, 0, len(result.ColumnMetadata)),
+		Rows:        make([][]interface{}, 0, len(result.Records)),
+		RowsUpdated: aws.Int64Value(result.NumberOfRecordsUpdated),
+	}
+	for _, c := range result.ColumnMetadata {
+		sr.Columns = append(sr.Columns, aws.StringValue(c.Name))
+	}
+	for _, record := range result.Records {
+		row := make([]interface{}, 0, len(record))
+		for _, f := range record {
+			row = append(row, dataAPIValue(f))
+		}
+		sr.Rows = append(sr.Rows, row)
+	}
+	for _, f := range result.GeneratedFields {
+		sr.Generated = append(sr.Generated, dataAPIValue(f))
+	}
+
+	return sr, nil
+}
+
+// dataAPIParameter converts a Go value to a Data API parameter
+func dataAPIParameter(name string, v interface{}) (*rdsdataservice.SqlParameter, error) {
+	p := &rdsdataservice.SqlParameter{Name: aws.String(name), Value: &rdsdataservice.Field{}}
+	switch val := v.(type) {
+	case nil:
+		p.Value.IsNull = aws.Bool(true)
+	case string:
+		p.Value.StringValue = aws.String(val)
+	case bool:
+		p.Value.BooleanValue = aws.Bool(val)
+	case int:
+		p.Value.LongValue = aws.Int64(int64(val))
+	case int64:
+		p.Value.LongValue = aws.Int64(val)
+	case float64:
+		p.Value.DoubleValue = aws.Float64(val)
+	case []byte:
+		p.Value.BlobValue = val
+	case time.Time:
+		p.Value.StringValue = aws.String(val.UTC().Format(dataAPITimeFormat))
+		p.TypeHint = aws.String(rdsdataservice.TypeHintTimestamp)
+	default:
+		return nil, errors.New("unsupported type for data api parameter " + name)
+	}
+	return p, nil
+}
+
+// dataAPIValue converts a Data API field to a Go value
+func dataAPIValue(f *rdsdataservice.Field) interface{} {
+	switch {
+	case f == nil || aws.BoolValue(f.IsNull):
+		return nil
+	case f.StringValue != nil:
+		return *f.StringValue
+	case f.LongValue != nil:
+		return *f.LongValue
+	case f.DoubleValue != nil:
+		return *f.DoubleValue
+	case f.BooleanValue != nil:
+		return *f.BooleanValue
+	case f.BlobValue != nil:
+		return f.BlobValue
+	case f.ArrayValue != nil:
+		return f.ArrayValue.String()
+	}
+	return nil
+}
+
+// ACURange returns the Serverless v2 capacity range as min-max ACUs, e.g. 0.5-16, or an
+// empty string when the cluster is not Serverless v2
+func (aes *AuroraEndpoints) ACURange() string {
+	if aes.ServerlessV2 == nil {
+		return ""
+	}
+	return strconv.FormatFloat(aes.ServerlessV2.MinACU, 'f', -1, 64) + "-" +
+		strconv.FormatFloat(aes.ServerlessV2.MaxACU, 'f', -1, 64)
+}
@@ -0,0 +1,219 @@
+package awsx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+)
+
+// defaultDBConnMaxLifetime bounds how long OpenDB connections are reused, so the pool
+// re-resolves the writer and reconnects to the new writer within minutes of a failover
+const defaultDBConnMaxLifetime = 5 * time.Minute
+
+// DBOption configures the *sql.DB returned by OpenDB
+type DBOption func(*dbOptions)
+
+type dbOptions struct {
+	user        string
+	password    Secret
+	database    string
+	driver      string
+	tls         string
+	reader      bool
+	maxLifetime time.Duration
+	dsn         func(endpoint, user, password string) string
+}
+
+// DBUser sets the database user, required. With IAM authentication the user must be
+// granted the rds_iam role (PostgreSQL) or use the AWSAuthenticationPlugin (MySQL).
+func DBUser(user string) DBOption {
+	return func(o *dbOptions) { o.user = user }
+}
+
+// DBPassword authenticates with a password instead of an IAM authentication token
+func DBPassword(password Secret) DBOption {
+	return func(o *dbOptions) { o.password = password }
+}
+
+// DBName sets the database to connect to
+func DBName(name string) DBOption {
+	return func(o *dbOptions) { o.database = name }
+}
+
+// DBDriver sets the registered database/sql driver, which defaults to mysql for MySQL
+// engines and postgres for PostgreSQL engines. The application must import the driver.
+func DBDriver(name string) DBOption {
+	return func(o *dbOptions) { o.driver = name }
+}
+
+// DBTLS sets the TLS setting of the DSN: the tls parameter for MySQL, e.g. the name of a
+// config registered with mysql.RegisterTLSConfig or RegisterRDSTLSConfig, or the sslmode for PostgreSQL.
+// Required for MySQL, as tls=true verifies against the system roots, which do not include
+// the RDS CA; defaults to require for PostgreSQL.
+func DBTLS(setting string) DBOption {
+	return func(o *dbOptions) { o.tls = setting }
+}
+
+// DBReader connects to the reader endpoint instead of the writer
+func DBReader() DBOption {
+	return func(o *dbOptions) { o.reader = true }
+}
+
+// DBConnMaxLifetime sets how long connections are reused, defaults to 5 minutes
+func DBConnMaxLifetime(d time.Duration) DBOption {
+	return func(o *dbOptions) { o.maxLifetime = d }
+}
+
+// DBDSN replaces the DSN built for the driver with the result of dsn, called with the
+// host:port, user and password or token of every new connection
+func DBDSN(dsn func(endpoint, user, password string) string) DBOption {
+	return func(o *dbOptions) { o.dsn = dsn }
+}
+
+// OpenDB returns a *sql.DB for an Aurora cluster whose connector resolves the writer
+// endpoint and generates a fresh IAM authentication token for every new connection.
+// When a connection fails the endpoints are rediscovered before retrying once, so
+// failovers are handled by the connection pool without restarting the application.
+func (a *Config) OpenDB(ctx context.Context, cluster string, opts ...DBOption) (*sql.DB, error) {
+	o := dbOptions{maxLifetime: defaultDBConnMaxLifetime}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.user == "" {
+		return nil, errors.New("must provide a database user with DBUser")
+	}
+
+	aes, err := a.GetAuroraEndpoints(cluster)
+	if err != nil {
+		return nil, err
+	}
+	postgres := strings.Contains(aes.Engine, "postgres")
+	if o.driver == "" {
+		o.driver = "mysql"
+		if postgres {
+			o.driver = "postgres"
+		}
+	}
+	if o.dsn == nil {
+		if !postgres && o.tls == "" {
+			return nil, errors.New("must provide the name of a tls config verifying the rds ca with DBTLS, see RegisterRDSTLSConfig")
+		}
+		o.dsn = mysqlDSN(o.database, o.tls)
+		if postgres {
+			o.dsn = postgresDSN(o.database, o.tls)
+		}
+	}
+
+	// sql.Open does not connect, it is only used to look up the registered driver
+	probe, err := sql.Open(o.driver, "")
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+
+	c := &dbConnector{config: a, cluster: cluster, opts: o, driver: drv}
+	db := sql.OpenDB(c)
+	db.SetConnMaxLifetime(o.maxLifetime)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// dbConnector is a driver.Connector resolving the endpoint and credentials per connection
+type dbConnector struct {
+	config  *Config
+	cluster string
+	opts    dbOptions
+	driver  driver.Driver
+}
+
+// Connect opens a connection to the current endpoint, rediscovering it and retrying
+// once if the connection fails
+func (c *dbConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connect(ctx)
+	if err == nil {
+		return conn, nil
+	}
+
+	c.config.forget("aurora:" + c.cluster)
+	return c.connect(ctx)
+}
+
+func (c *dbConnector) connect(ctx context.Context) (driver.Conn, error) {
+	aes, err := c.config.GetAuroraEndpoints(c.cluster)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := aes.WriterString()
+	if c.opts.reader {
+		endpoint = aes.ReaderString()
+	}
+
+	password := c.opts.password.UnsafeRaw()
+	if password == "" {
+		if c.config.Session == nil {
+			c.config.SetSession()
+		}
+		if c.config.Session == nil {
+			return nil, errors.New("no session to sign the iam authentication token with")
+		}
+		password, err = rdsutils.BuildAuthToken(endpoint, c.config.GetRegion(), c.opts.user, c.config.Session.Config.Credentials)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dsn := c.opts.dsn(endpoint, c.opts.user, password)
+	if dc, ok := c.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return connector.Connect(ctx)
+	}
+	return c.driver.Open(dsn)
+}
+
+// Driver returns the underlying driver
+func (c *dbConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// mysqlDSN returns a DSN builder in the go-sql-driver/mysql format. Cleartext passwords
+// are allowed since IAM tokens are sent over TLS.
+func mysqlDSN(database, tls string) func(endpoint, user, password string) string {
+	return func(endpoint, user, password string) string {
+		return user + ":" + password + "@tcp(" + endpoint + ")/" + database +
+			"?tls=" + url.QueryEscape(tls) + "&allowCleartextPasswords=true"
+	}
+}
+
+// postgresDSN returns a DSN builder in the URL format understood by lib/pq and pgx
+func postgresDSN(database, sslmode string) func(endpoint, user, password string) string {
+	if sslmode == "" {
+		sslmode = "require"
+	}
+	return func(endpoint, user, password string) string {
+		host, port, _ := net.SplitHostPort(endpoint)
+		u := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(user, password),
+			Host:     net.JoinHostPort(host, port),
+			Path:     "/" + database,
+			RawQuery: "sslmode=" + url.QueryEscape(sslmode),
+		}
+		return u.String()
+	}
+}
@@ -0,0 +1,44 @@
+package awsx
+
+import (
+	"sync"
+)
+
+// defaultConfig holds the process-wide Config returned by Default
+var defaultConfig struct {
+	mu     sync.Mutex
+	config *Config
+}
+
+// Default returns the process-wide Config, building it on first use from the AWSX_
+// environment variables, see FromEnv, with an initialized session. If the environment is
+// invalid the error is printed and a QuickConfig is used instead. Small programs can call
+// awsx.Default().GetRedisPrimaryEndpoint(...) from anywhere instead of passing a Config
+// around. Default is safe for concurrent use; the first caller builds the Config and the
+// others wait for it.
+func Default() *Config {
+	defaultConfig.mu.Lock()
+	defer defaultConfig.mu.Unlock()
+
+	if defaultConfig.config == nil {
+		a, err := FromEnv()
+		if err != nil {
+			stderrLogger.Println("Error building the default config from the environment, using QuickConfig: ", err)
+			a = QuickConfig()
+		} else if a.Session == nil {
+			a.SetSession()
+		}
+		defaultConfig.config = a
+	}
+
+	return defaultConfig.config
+}
+
+// SetDefault replaces the process-wide Config returned by Default, e.g. with one built
+// from a config file at startup. Passing nil makes the next call to Default build a new
+// one from the environment.
+func SetDefault(a *Config) {
+	defaultConfig.mu.Lock()
+	defaultConfig.config = a
+	defaultConfig.mu.Unlock()
+}
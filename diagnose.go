@@ -0,0 +1,217 @@
+package awsx
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// regionPattern matches AWS region names such as us-east-1 or us-gov-west-1
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d$`)
+
+// Validate checks the Config for mistakes that can be found without calling AWS: the
+// credential chain, region name, role ARN, endpoint URLs and durations. All problems are
+// returned together in a single error.
+func (a *Config) Validate() error {
+	problems := make([]string, 0)
+
+	if len(a.Providers) == 0 {
+		problems = append(problems, "no credential providers, use the With*() methods")
+	}
+	if a.Region != "" && !regionPattern.MatchString(a.Region) {
+		problems = append(problems, "region "+a.Region+" is not a valid region name")
+	}
+	for _, r := range a.Regions {
+		if !regionPattern.MatchString(r) {
+			problems = append(problems, "region "+r+" in Regions is not a valid region name")
+		}
+	}
+	if a.Role != "" {
+		if _, err := arn.Parse(a.Role); err != nil {
+			problems = append(problems, "role "+a.Role+" is not a valid ARN")
+		}
+	}
+	if a.Endpoint != "" {
+		if err := validateEndpointURL(a.Endpoint); err != nil {
+			problems = append(problems, "endpoint: "+err.Error())
+		}
+	}
+	for svc, endpoint := range a.ServiceEndpoints {
+		if err := validateEndpointURL(endpoint); err != nil {
+			problems = append(problems, svc+" endpoint: "+err.Error())
+		}
+	}
+	if a.CacheTTL < 0 {
+		problems = append(problems, "cache TTL must not be negative")
+	}
+	if a.AccessKey != "" && a.SecretKey == "" || a.AccessKey == "" && a.SecretKey != "" {
+		problems = append(problems, "access key and secret key must be set together")
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid config: " + strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// validateEndpointURL checks that an endpoint is an absolute http or https URL
+func validateEndpointURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", endpoint)
+	}
+	return nil
+}
+
+// DiagnosticCheck is the result of a single check run by Diagnose
+type DiagnosticCheck struct {
+	Name     string
+	OK       bool
+	Detail   string
+	Duration time.Duration
+}
+
+// DiagnosticReport is the result of Diagnose
+type DiagnosticReport struct {
+	Region   string
+	Identity *CallerIdentity
+	Checks   []DiagnosticCheck
+}
+
+// OK reports whether every check passed
+func (r *DiagnosticReport) OK() bool {
+	for _, c := range r.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns an error listing the failed checks, or nil if every check passed
+func (r *DiagnosticReport) Err() error {
+	failed := make([]string, 0)
+	for _, c := range r.Checks {
+		if !c.OK {
+			failed = append(failed, c.Name+": "+c.Detail)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.New("diagnostics failed: " + strings.Join(failed, "; "))
+}
+
+// String provides a line per check
+func (r *DiagnosticReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Checks {
+		status := "ok  "
+		if !c.OK {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s %-24s %-8s %s\n", status, c.Name, c.Duration.Round(time.Millisecond), c.Detail)
+	}
+	return b.String()
+}
+
+// Diagnose runs Validate and then checks the Config against AWS: the region resolves,
+// the credential chain authenticates with GetCallerIdentity, and the principal is allowed
+// the ElastiCache and RDS describe calls used by discovery. Every check is run even when
+// an earlier one fails, so the report shows all problems at once.
+func (a *Config) Diagnose() *DiagnosticReport {
+	r := &DiagnosticReport{}
+
+	r.run(a, "config", func() (string, error) {
+		return "valid", a.Validate()
+	})
+
+	r.run(a, "region", func() (string, error) {
+		region, err := a.ResolveRegion()
+		if err != nil {
+			return "", err
+		}
+		r.Region = region
+		if !regionPattern.MatchString(region) {
+			return "", errors.New(region + " is not a valid region name")
+		}
+		return region, nil
+	})
+
+	if a.Session == nil {
+		a.SetSession()
+	}
+	if a.Session == nil {
+		r.Checks = append(r.Checks, DiagnosticCheck{Name: "session", Detail: "could not create a session"})
+		return r
+	}
+
+	r.run(a, "sts:GetCallerIdentity", func() (string, error) {
+		id, err := a.WhoAmI()
+		if err != nil {
+			return "", err
+		}
+		r.Identity = id
+		return id.ARN + " via " + id.Provider, nil
+	})
+
+	r.run(a, "elasticache:Describe", func() (string, error) {
+		if a.Service.Ec == nil {
+			a.SetECClient()
+		}
+		_, err := a.Service.Ec.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{
+			MaxRecords: aws.Int64(20),
+		})
+		return "allowed", permissionError(err)
+	})
+
+	r.run(a, "rds:Describe", func() (string, error) {
+		if a.Service.Rds == nil {
+			a.SetRDSClient()
+		}
+		_, err := a.Service.Rds.DescribeDBClusters(&rds.DescribeDBClustersInput{
+			MaxRecords: aws.Int64(20),
+		})
+		return "allowed", permissionError(err)
+	})
+
+	return r
+}
+
+// run times a check and appends its result to the report
+func (r *DiagnosticReport) run(a *Config, name string, check func() (string, error)) {
+	start := a.clock().Now()
+	detail, err := check()
+	c := DiagnosticCheck{Name: name, OK: err == nil, Detail: detail, Duration: a.since(start)}
+	if err != nil {
+		c.Detail = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// permissionError explains authorization failures of a describe call
+func permissionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation":
+			return errors.New("permission denied: " + aerr.Message())
+		}
+	}
+	return err
+}
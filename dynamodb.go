@@ -0,0 +1,236 @@
+package awsx
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dax"
+	"github.com/aws/aws-sdk-go/service/dax/daxiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+)
+
+// GetDynamoDBClient returns a client for use with Amazon DynamoDB
+func (a *Config) GetDynamoDBClient() dynamodbiface.DynamoDBAPI {
+	return a.Service.Ddb
+}
+
+// SetDynamoDBClient sets a client for use with Amazon DynamoDB
+func (a *Config) SetDynamoDBClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ddb = dynamodb.New(a.ClientConfig(dynamodb.EndpointsID))
+
+	return a
+}
+
+// WithDynamoDBClient sets the client used for Amazon DynamoDB calls, such as a mock from
+// the awsxmock package or a DAX client
+func (a *Config) WithDynamoDBClient(client dynamodbiface.DynamoDBAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ddb = client
+
+	return a
+}
+
+// GetDAXClient returns a client for use with the Amazon DAX control plane
+func (a *Config) GetDAXClient() daxiface.DAXAPI {
+	return a.Service.Dax
+}
+
+// SetDAXClient sets a client for use with the Amazon DAX control plane
+func (a *Config) SetDAXClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Dax = dax.New(a.ClientConfig(dax.EndpointsID))
+
+	return a
+}
+
+// WithDAXClient sets the client used for Amazon DAX control plane calls, such as a mock
+// from the awsxmock package
+func (a *Config) WithDAXClient(client daxiface.DAXAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Dax = client
+
+	return a
+}
+
+// DynamoKey is an attribute of a key schema
+type DynamoKey struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"` // S, N or B
+}
+
+// DynamoIndex is a global or local secondary index of a table
+type DynamoIndex struct {
+	Name         string     `json:"name" yaml:"name"`
+	PartitionKey DynamoKey  `json:"partition_key" yaml:"partition_key"`
+	SortKey      *DynamoKey `json:"sort_key,omitempty" yaml:"sort_key,omitempty"`
+	Projection   string     `json:"projection" yaml:"projection"` // ALL, KEYS_ONLY or INCLUDE
+	Status       string     `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// DynamoTable is a DynamoDB table returned by GetDynamoTable
+type DynamoTable struct {
+	Name           string         `json:"name" yaml:"name"`
+	ARN            string         `json:"arn" yaml:"arn"`
+	Status         string         `json:"status" yaml:"status"`
+	BillingMode    string         `json:"billing_mode" yaml:"billing_mode"` // PROVISIONED or PAY_PER_REQUEST
+	PartitionKey   DynamoKey      `json:"partition_key" yaml:"partition_key"`
+	SortKey        *DynamoKey     `json:"sort_key,omitempty" yaml:"sort_key,omitempty"`
+	GlobalIndexes  []*DynamoIndex `json:"global_indexes,omitempty" yaml:"global_indexes,omitempty"`
+	LocalIndexes   []*DynamoIndex `json:"local_indexes,omitempty" yaml:"local_indexes,omitempty"`
+	StreamARN      string         `json:"stream_arn,omitempty" yaml:"stream_arn,omitempty"`
+	StreamViewType string         `json:"stream_view_type,omitempty" yaml:"stream_view_type,omitempty"`
+	ItemCount      int64          `json:"item_count" yaml:"item_count"`
+	SizeBytes      int64          `json:"size_bytes" yaml:"size_bytes"`
+}
+
+// GlobalIndex returns the global secondary index with the name, or nil if the table has no
+// such index
+func (t *DynamoTable) GlobalIndex(name string) *DynamoIndex {
+	for _, idx := range t.GlobalIndexes {
+		if idx.Name == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+// GetDynamoTable returns the key schema, secondary indexes, billing mode and stream of the
+// table, so that services can validate its shape at startup
+func (a *Config) GetDynamoTable(name string) (*DynamoTable, error) {
+	if name == "" {
+		return nil, errors.New("no table name provided")
+	}
+
+	if a.Service.Ddb == nil {
+		a.SetDynamoDBClient()
+	}
+
+	out, err := a.Service.Ddb.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(name)})
+	if err != nil {
+		return nil, err
+	}
+	d := out.Table
+	if d == nil {
+		return nil, errors.New("no description returned for table " + name)
+	}
+
+	types := make(map[string]string, len(d.AttributeDefinitions))
+	for _, ad := range d.AttributeDefinitions {
+		types[aws.StringValue(ad.AttributeName)] = aws.StringValue(ad.AttributeType)
+	}
+
+	t := &DynamoTable{
+		Name:        aws.StringValue(d.TableName),
+		ARN:         aws.StringValue(d.TableArn),
+		Status:      aws.StringValue(d.TableStatus),
+		BillingMode: dynamodb.BillingModeProvisioned,
+		StreamARN:   aws.StringValue(d.LatestStreamArn),
+		ItemCount:   aws.Int64Value(d.ItemCount),
+		SizeBytes:   aws.Int64Value(d.TableSizeBytes),
+	}
+	t.PartitionKey, t.SortKey = dynamoKeys(d.KeySchema, types)
+	if d.BillingModeSummary != nil {
+		t.BillingMode = aws.StringValue(d.BillingModeSummary.BillingMode)
+	}
+	if d.StreamSpecification != nil && aws.BoolValue(d.StreamSpecification.StreamEnabled) {
+		t.StreamViewType = aws.StringValue(d.StreamSpecification.StreamViewType)
+	}
+	for _, g := range d.GlobalSecondaryIndexes {
+		idx := &DynamoIndex{Name: aws.StringValue(g.IndexName), Status: aws.StringValue(g.IndexStatus)}
+		idx.PartitionKey, idx.SortKey = dynamoKeys(g.KeySchema, types)
+		if g.Projection != nil {
+			idx.Projection = aws.StringValue(g.Projection.ProjectionType)
+		}
+		t.GlobalIndexes = append(t.GlobalIndexes, idx)
+	}
+	for _, l := range d.LocalSecondaryIndexes {
+		idx := &DynamoIndex{Name: aws.StringValue(l.IndexName)}
+		idx.PartitionKey, idx.SortKey = dynamoKeys(l.KeySchema, types)
+		if l.Projection != nil {
+			idx.Projection = aws.StringValue(l.Projection.ProjectionType)
+		}
+		t.LocalIndexes = append(t.LocalIndexes, idx)
+	}
+
+	return t, nil
+}
+
+// dynamoKeys returns the partition (HASH) and sort (RANGE) key of a key schema
+func dynamoKeys(schema []*dynamodb.KeySchemaElement, types map[string]string) (DynamoKey, *DynamoKey) {
+	var partition DynamoKey
+	var sort *DynamoKey
+	for _, k := range schema {
+		key := DynamoKey{Name: aws.StringValue(k.AttributeName), Type: types[aws.StringValue(k.AttributeName)]}
+		if aws.StringValue(k.KeyType) == dynamodb.KeyTypeRange {
+			sort = &key
+		} else {
+			partition = key
+		}
+	}
+	return partition, sort
+}
+
+// GetDAXEndpoint returns the cluster discovery endpoint (host:port) of a DAX cluster
+func (a *Config) GetDAXEndpoint(cluster string) (string, error) {
+	if cluster == "" {
+		return "", errors.New("no cluster name provided")
+	}
+
+	if a.Service.Dax == nil {
+		a.SetDAXClient()
+	}
+
+	out, err := a.Service.Dax.DescribeClusters(&dax.DescribeClustersInput{ClusterNames: aws.StringSlice([]string{cluster})})
+	if err != nil {
+		return "", err
+	}
+	if len(out.Clusters) == 0 || out.Clusters[0].ClusterDiscoveryEndpoint == nil {
+		return "", errors.New("no discovery endpoint found for DAX cluster " + cluster)
+	}
+
+	e := out.Clusters[0].ClusterDiscoveryEndpoint
+	return net.JoinHostPort(aws.StringValue(e.Address), strconv.FormatInt(aws.Int64Value(e.Port), 10)), nil
+}
+
+// DAXClientFunc creates a DAX data plane client for the discovery endpoint with the
+// settings of the Config, e.g. a wrapper of dax.New from github.com/aws/aws-dax-go
+type DAXClientFunc func(endpoint string, a *Config) (dynamodbiface.DynamoDBAPI, error)
+
+// DynamoDBClientWithDAX returns a DynamoDB client served by the DAX cluster, falling back
+// to the DynamoDB client of the Config with a warning when the cluster cannot be found
+// or the DAX client cannot be created. awsx does not depend on the DAX client itself:
+//
+//	ddb := a.DynamoDBClientWithDAX("sessions-dax", func(endpoint string, a *awsx.Config) (dynamodbiface.DynamoDBAPI, error) {
+//		cfg := daxclient.DefaultConfig()
+//		cfg.HostPorts = []string{endpoint}
+//		cfg.Region = a.GetRegion()
+//		return daxclient.New(cfg)
+//	})
+func (a *Config) DynamoDBClientWithDAX(cluster string, newDAX DAXClientFunc) dynamodbiface.DynamoDBAPI {
+	if a.Service.Ddb == nil {
+		a.SetDynamoDBClient()
+	}
+
+	endpoint, err := a.GetDAXEndpoint(cluster)
+	if err == nil {
+		var client dynamodbiface.DynamoDBAPI
+		if client, err = newDAX(endpoint, a); err == nil {
+			return client
+		}
+	}
+
+	a.warn("Using DynamoDB instead of DAX cluster " + cluster + ": " + err.Error())
+	return a.Service.Ddb
+}
@@ -0,0 +1,118 @@
+package awsx
+
+import (
+	"net"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// GetEC2Client returns a client for use with AWS EC2
+func (a *Config) GetEC2Client() ec2iface.EC2API {
+	return a.Service.Ec2
+}
+
+// SetEC2Client sets a client for use with AWS EC2
+func (a *Config) SetEC2Client() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ec2 = ec2.New(a.ClientConfig(ec2.EndpointsID))
+
+	return a
+}
+
+// WithEC2Client sets the client used for AWS EC2 calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithEC2Client(client ec2iface.EC2API) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ec2 = client
+
+	return a
+}
+
+// EC2Instance is an EC2 instance returned by ListEC2InstancesByTag
+type EC2Instance struct {
+	ID               string            `json:"id" yaml:"id"`
+	Name             string            `json:"name,omitempty" yaml:"name,omitempty"` // value of the Name tag
+	InstanceType     string            `json:"instance_type" yaml:"instance_type"`
+	State            string            `json:"state" yaml:"state"`
+	AvailabilityZone string            `json:"availability_zone" yaml:"availability_zone"`
+	PrivateIP        string            `json:"private_ip,omitempty" yaml:"private_ip,omitempty"`
+	PublicIP         string            `json:"public_ip,omitempty" yaml:"public_ip,omitempty"`
+	IPv6             string            `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+	PrivateDNS       string            `json:"private_dns,omitempty" yaml:"private_dns,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Endpoint returns host:port of the private IP of the instance and the port
+func (i *EC2Instance) Endpoint(port string) string {
+	return net.JoinHostPort(i.PrivateIP, port)
+}
+
+// ListEC2InstancesByTag returns the running instances carrying every tag of filters,
+// sorted by instance ID, so that self-managed datastores on EC2 can be discovered
+// alongside the managed services. A filter with an empty value matches any value of
+// the tag key.
+func (a *Config) ListEC2InstancesByTag(filters map[string]string) ([]*EC2Instance, error) {
+	if a.Service.Ec2 == nil {
+		a.SetEC2Client()
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("instance-state-name"), Values: aws.StringSlice([]string{ec2.InstanceStateNameRunning})},
+		},
+		MaxResults: aws.Int64(listPageSize),
+	}
+	for key, value := range filters {
+		if value == "" {
+			input.Filters = append(input.Filters, &ec2.Filter{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{key})})
+		} else {
+			input.Filters = append(input.Filters, &ec2.Filter{Name: aws.String("tag:" + key), Values: aws.StringSlice([]string{value})})
+		}
+	}
+
+	list := make([]*EC2Instance, 0)
+	err := a.Service.Ec2.DescribeInstancesPages(input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, r := range page.Reservations {
+			for _, i := range r.Instances {
+				list = append(list, ec2Instance(i))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	return list, nil
+}
+
+func ec2Instance(i *ec2.Instance) *EC2Instance {
+	inst := &EC2Instance{
+		ID:           aws.StringValue(i.InstanceId),
+		InstanceType: aws.StringValue(i.InstanceType),
+		PrivateIP:    aws.StringValue(i.PrivateIpAddress),
+		PublicIP:     aws.StringValue(i.PublicIpAddress),
+		IPv6:         aws.StringValue(i.Ipv6Address),
+		PrivateDNS:   aws.StringValue(i.PrivateDnsName),
+		Tags:         make(map[string]string, len(i.Tags)),
+	}
+	if i.State != nil {
+		inst.State = aws.StringValue(i.State.Name)
+	}
+	if i.Placement != nil {
+		inst.AvailabilityZone = aws.StringValue(i.Placement.AvailabilityZone)
+	}
+	for _, t := range i.Tags {
+		inst.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	inst.Name = inst.Tags["Name"]
+	return inst
+}
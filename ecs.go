@@ -0,0 +1,290 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+)
+
+// GetECSClient returns a client for use with Amazon ECS
+func (a *Config) GetECSClient() ecsiface.ECSAPI {
+	return a.Service.Ecs
+}
+
+// SetECSClient sets a client for use with Amazon ECS
+func (a *Config) SetECSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ecs = ecs.New(a.ClientConfig(ecs.EndpointsID))
+
+	return a
+}
+
+// WithECSClient sets the client used for Amazon ECS calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithECSClient(client ecsiface.ECSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ecs = client
+
+	return a
+}
+
+// ECSTask is the task metadata of the ECS task awsx runs in. The JSON names are those
+// of the task metadata document.
+type ECSTask struct {
+	TaskARN          string          `json:"TaskARN" yaml:"task_arn"`
+	Cluster          string          `json:"Cluster" yaml:"cluster"`
+	Family           string          `json:"Family" yaml:"family"`
+	Revision         string          `json:"Revision" yaml:"revision"`
+	AvailabilityZone string          `json:"AvailabilityZone" yaml:"availability_zone"`
+	LaunchType       string          `json:"LaunchType" yaml:"launch_type"` // EC2, FARGATE or EXTERNAL
+	Containers       []*ECSContainer `json:"Containers" yaml:"containers"`
+}
+
+// ECSContainer is a container of the ECS task with its networks
+type ECSContainer struct {
+	Name     string        `json:"Name" yaml:"name"`
+	DockerID string        `json:"DockerId" yaml:"docker_id"`
+	Image    string        `json:"Image" yaml:"image"`
+	Networks []*ECSNetwork `json:"Networks" yaml:"networks"`
+}
+
+// ECSNetwork is a network a container of the task is attached to
+type ECSNetwork struct {
+	NetworkMode   string   `json:"NetworkMode" yaml:"network_mode"` // awsvpc, bridge or host
+	IPv4Addresses []string `json:"IPv4Addresses" yaml:"ipv4_addresses"`
+	IPv6Addresses []string `json:"IPv6Addresses" yaml:"ipv6_addresses"`
+}
+
+// Region returns the region of the task, taken from the task ARN
+func (t *ECSTask) Region() (string, error) {
+	parsed, err := arn.Parse(t.TaskARN)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Region, nil
+}
+
+// ClusterName returns the name of the cluster of the task, which the metadata endpoint
+// reports as an ARN on EC2 and Fargate
+func (t *ECSTask) ClusterName() string {
+	if parsed, err := arn.Parse(t.Cluster); err == nil {
+		return strings.TrimPrefix(parsed.Resource, "cluster/")
+	}
+	return t.Cluster
+}
+
+// IPv4 returns the first IPv4 address of the containers of the task, the task address
+// in awsvpc network mode
+func (t *ECSTask) IPv4() string {
+	for _, c := range t.Containers {
+		for _, n := range c.Networks {
+			if len(n.IPv4Addresses) > 0 {
+				return n.IPv4Addresses[0]
+			}
+		}
+	}
+	return ""
+}
+
+// GetECSTaskMetadata returns the metadata of the ECS task from the task metadata endpoint
+func GetECSTaskMetadata() (*ECSTask, error) {
+	base := os.Getenv("ECS_CONTAINER_METADATA_URI_V4")
+	if base == "" {
+		base = os.Getenv("ECS_CONTAINER_METADATA_URI")
+	}
+	if base == "" {
+		return nil, errors.New("not running in an ECS task")
+	}
+
+	client := &http.Client{Timeout: metadataTimeout}
+	resp, err := client.Get(strings.TrimRight(base, "/") + "/task")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ecs task metadata returned status %d", resp.StatusCode)
+	}
+
+	task := &ECSTask{}
+	if err := json.NewDecoder(resp.Body).Decode(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// ECSServiceEndpoints are the endpoints a sibling ECS service is reachable on
+type ECSServiceEndpoints struct {
+	Cluster        string        `json:"cluster" yaml:"cluster"`
+	Service        string        `json:"service" yaml:"service"`
+	ServiceConnect bool          `json:"service_connect" yaml:"service_connect"`         // endpoints are Service Connect aliases
+	Endpoints      []string      `json:"endpoints" yaml:"endpoints"`                     // host:port
+	Staleness      time.Duration `json:"staleness,omitempty" yaml:"staleness,omitempty"` // age of an earlier result served after discovery failed, see StalePolicy
+}
+
+// GetECSServiceEndpoints resolves the endpoints of an ECS service in the cluster, or in
+// the cluster of the running task when cluster is empty. Services using Service Connect
+// resolve to their client aliases, which are reachable from tasks in the same namespace;
+// services registered with service discovery resolve to the addresses of their healthy
+// tasks in Cloud Map.
+func (a *Config) GetECSServiceEndpoints(cluster, service string) (*ECSServiceEndpoints, error) {
+	if service == "" {
+		return nil, errors.New("no ecs service name provided")
+	}
+	if cluster == "" {
+		task, err := GetECSTaskMetadata()
+		if err != nil {
+			return nil, errors.New("no ecs cluster provided and none detected: " + err.Error())
+		}
+		cluster = task.ClusterName()
+	}
+
+	res, err := a.traced("ecs", cluster+"/"+service, func(ctx context.Context) (interface{}, error) {
+		return a.cached("ecs:"+cluster+"/"+service, func() (interface{}, error) {
+			return a.getECSServiceEndpoints(ctx, cluster, service)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*ECSServiceEndpoints), nil
+}
+
+func (a *Config) getECSServiceEndpoints(ctx context.Context, cluster, service string) (*ECSServiceEndpoints, error) {
+	if a.Service.Ecs == nil {
+		a.SetECSClient()
+	}
+
+	out, err := a.Service.Ecs.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: aws.StringSlice([]string{service}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Services) == 0 {
+		return nil, errors.New("no ecs service found with name " + service + " in cluster " + cluster)
+	}
+	svc := out.Services[0]
+
+	res := &ECSServiceEndpoints{Cluster: cluster, Service: aws.StringValue(svc.ServiceName), Endpoints: make([]string, 0)}
+
+	for _, d := range svc.Deployments {
+		if aws.StringValue(d.Status) != "PRIMARY" || d.ServiceConnectConfiguration == nil {
+			continue
+		}
+		sc := d.ServiceConnectConfiguration
+		if !aws.BoolValue(sc.Enabled) {
+			continue
+		}
+		for _, s := range sc.Services {
+			for _, alias := range s.ClientAliases {
+				host := aws.StringValue(alias.DnsName)
+				if host == "" {
+					// the alias defaults to the discovery name in the namespace
+					namespace, err := a.cloudMapNamespaceName(ctx, aws.StringValue(sc.Namespace))
+					if err != nil {
+						return nil, err
+					}
+					host = stringOr(aws.StringValue(s.DiscoveryName), aws.StringValue(s.PortName)) + "." + namespace
+				}
+				res.Endpoints = append(res.Endpoints, net.JoinHostPort(host, strconv.FormatInt(aws.Int64Value(alias.Port), 10)))
+			}
+		}
+		if len(res.Endpoints) > 0 {
+			res.ServiceConnect = true
+			return res, nil
+		}
+	}
+
+	if len(svc.ServiceRegistries) == 0 {
+		return nil, errors.New("ecs service " + service + " uses neither service connect nor service discovery")
+	}
+
+	registry := svc.ServiceRegistries[0]
+	namespace, name, err := a.cloudMapServiceName(ctx, aws.StringValue(registry.RegistryArn))
+	if err != nil {
+		return nil, err
+	}
+	port := ""
+	if registry.ContainerPort != nil {
+		port = strconv.FormatInt(aws.Int64Value(registry.ContainerPort), 10)
+	} else if registry.Port != nil {
+		port = strconv.FormatInt(aws.Int64Value(registry.Port), 10)
+	}
+
+	instances, err := a.discoverCloudMapInstances(ctx, namespace, name, port)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range instances {
+		res.Endpoints = append(res.Endpoints, net.JoinHostPort(i.host, i.port))
+	}
+
+	return res, nil
+}
+
+// cloudMapServiceName returns the namespace and name of the Cloud Map service with the ARN
+func (a *Config) cloudMapServiceName(ctx context.Context, serviceARN string) (string, string, error) {
+	parsed, err := arn.Parse(serviceARN)
+	if err != nil {
+		return "", "", err
+	}
+
+	if a.Service.CloudMap == nil {
+		a.SetCloudMapClient()
+	}
+
+	out, err := a.Service.CloudMap.GetServiceWithContext(ctx, &servicediscovery.GetServiceInput{Id: aws.String(strings.TrimPrefix(parsed.Resource, "service/"))})
+	if err != nil {
+		return "", "", err
+	}
+	namespace, err := a.cloudMapNamespaceName(ctx, aws.StringValue(out.Service.NamespaceId))
+	if err != nil {
+		return "", "", err
+	}
+
+	return namespace, aws.StringValue(out.Service.Name), nil
+}
+
+// cloudMapNamespaceName returns the name of the Cloud Map namespace with the ARN or ID.
+// Anything else is taken to be a name already.
+func (a *Config) cloudMapNamespaceName(ctx context.Context, namespace string) (string, error) {
+	id := namespace
+	if parsed, err := arn.Parse(namespace); err == nil {
+		id = strings.TrimPrefix(parsed.Resource, "namespace/")
+	} else if !strings.HasPrefix(namespace, "ns-") {
+		return namespace, nil
+	}
+
+	if a.Service.CloudMap == nil {
+		a.SetCloudMapClient()
+	}
+
+	out, err := a.Service.CloudMap.GetNamespaceWithContext(ctx, &servicediscovery.GetNamespaceInput{Id: aws.String(id)})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(out.Namespace.Name), nil
+}
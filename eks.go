@@ -0,0 +1,130 @@
+package awsx
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+const (
+	// eksTokenPrefix marks a bearer token as a presigned STS URL for the EKS authenticator
+	eksTokenPrefix = "k8s-aws-v1."
+	// eksClusterHeader is the signed header binding a token to a cluster
+	eksClusterHeader = "x-k8s-aws-id"
+	// eksTokenPresign is the lifetime of the presigned URL, which the authenticator caps
+	// at 15 minutes regardless
+	eksTokenPresign = 60 * time.Second
+	// eksTokenLifetime is how long a token is reported valid, a minute short of the
+	// 15 minutes the authenticator accepts it for
+	eksTokenLifetime = 14 * time.Minute
+)
+
+// GetEKSClient returns a client for use with Amazon EKS
+func (a *Config) GetEKSClient() eksiface.EKSAPI {
+	return a.Service.Eks
+}
+
+// SetEKSClient sets a client for use with Amazon EKS
+func (a *Config) SetEKSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Eks = eks.New(a.ClientConfig(eks.EndpointsID))
+
+	return a
+}
+
+// WithEKSClient sets the client used for Amazon EKS calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithEKSClient(client eksiface.EKSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Eks = client
+
+	return a
+}
+
+// EKSCluster is the API server endpoint of an EKS cluster with the CA to verify it
+type EKSCluster struct {
+	Name     string `json:"name" yaml:"name"`
+	ARN      string `json:"arn" yaml:"arn"`
+	Status   string `json:"status" yaml:"status"`
+	Version  string `json:"version" yaml:"version"` // Kubernetes version
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	CAData   []byte `json:"ca_data" yaml:"ca_data"` // PEM encoded cluster CA
+}
+
+// EKSToken is a bearer token for the Kubernetes API server of an EKS cluster
+type EKSToken struct {
+	Token      string    `json:"token" yaml:"token"`
+	Expiration time.Time `json:"expiration" yaml:"expiration"`
+}
+
+// GetEKSCluster returns the API server endpoint and CA of the EKS cluster, which with
+// GetEKSToken is everything a Kubernetes client config needs
+func (a *Config) GetEKSCluster(name string) (*EKSCluster, error) {
+	if name == "" {
+		return nil, errors.New("no eks cluster name provided")
+	}
+
+	if a.Service.Eks == nil {
+		a.SetEKSClient()
+	}
+
+	out, err := a.Service.Eks.DescribeCluster(&eks.DescribeClusterInput{Name: aws.String(name)})
+	if err != nil {
+		return nil, err
+	}
+	if out.Cluster == nil {
+		return nil, errors.New("no eks cluster found with name " + name)
+	}
+	c := out.Cluster
+
+	cluster := &EKSCluster{
+		Name:     aws.StringValue(c.Name),
+		ARN:      aws.StringValue(c.Arn),
+		Status:   aws.StringValue(c.Status),
+		Version:  aws.StringValue(c.Version),
+		Endpoint: aws.StringValue(c.Endpoint),
+	}
+	if c.CertificateAuthority != nil && c.CertificateAuthority.Data != nil {
+		cluster.CAData, err = base64.StdEncoding.DecodeString(aws.StringValue(c.CertificateAuthority.Data))
+		if err != nil {
+			return nil, errors.New("invalid certificate authority data for eks cluster " + name + ": " + err.Error())
+		}
+	}
+
+	return cluster, nil
+}
+
+// GetEKSToken returns a bearer token for the EKS cluster, as generated by
+// aws-iam-authenticator and aws eks get-token: a presigned STS GetCallerIdentity URL
+// bound to the cluster name, signed with the credential chain of the Config. Tokens
+// are valid for 15 minutes, Expiration leaves a minute to refresh them.
+func (a *Config) GetEKSToken(cluster string) (*EKSToken, error) {
+	if cluster == "" {
+		return nil, errors.New("no eks cluster name provided")
+	}
+
+	if a.Service.Sts == nil {
+		a.SetSTSClient()
+	}
+
+	req, _ := a.Service.Sts.GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+	req.HTTPRequest.Header.Add(eksClusterHeader, cluster)
+	signed, err := req.Presign(eksTokenPresign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EKSToken{
+		Token:      eksTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(signed)),
+		Expiration: a.clock().Now().Add(eksTokenLifetime),
+	}, nil
+}
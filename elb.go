@@ -0,0 +1,182 @@
+package awsx
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+)
+
+// GetELBClient returns a client for use with Elastic Load Balancing (ALB and NLB)
+func (a *Config) GetELBClient() elbv2iface.ELBV2API {
+	return a.Service.Elb
+}
+
+// SetELBClient sets a client for use with Elastic Load Balancing (ALB and NLB)
+func (a *Config) SetELBClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Elb = elbv2.New(a.ClientConfig(elbv2.EndpointsID))
+
+	return a
+}
+
+// WithELBClient sets the client used for Elastic Load Balancing calls, such as a mock from
+// the awsxmock package
+func (a *Config) WithELBClient(client elbv2iface.ELBV2API) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Elb = client
+
+	return a
+}
+
+// LoadBalancer is an application, network or gateway load balancer returned by
+// GetLoadBalancerEndpoint
+type LoadBalancer struct {
+	Name      string        `json:"name" yaml:"name"`
+	ARN       string        `json:"arn" yaml:"arn"`
+	Type      string        `json:"type" yaml:"type"`     // application, network or gateway
+	Scheme    string        `json:"scheme" yaml:"scheme"` // internet-facing or internal
+	State     string        `json:"state" yaml:"state"`
+	DNSName   string        `json:"dns_name" yaml:"dns_name"`
+	Listeners []*LBListener `json:"listeners" yaml:"listeners"`
+}
+
+// LBListener is a listener of a load balancer with the health of the target groups it
+// forwards to
+type LBListener struct {
+	Port         int64            `json:"port" yaml:"port"`
+	Protocol     string           `json:"protocol" yaml:"protocol"`
+	TargetGroups []*LBTargetGroup `json:"target_groups" yaml:"target_groups"`
+}
+
+// LBTargetGroup counts the targets of a target group by health
+type LBTargetGroup struct {
+	ARN       string `json:"arn" yaml:"arn"`
+	Healthy   int    `json:"healthy" yaml:"healthy"`
+	Unhealthy int    `json:"unhealthy" yaml:"unhealthy"`
+	Total     int    `json:"total" yaml:"total"` // including initial, draining and unused targets
+}
+
+// Endpoint returns host:port of the DNS name and the listener port
+func (lb *LoadBalancer) Endpoint(port int64) string {
+	return net.JoinHostPort(lb.DNSName, strconv.FormatInt(port, 10))
+}
+
+// Listener returns the listener on the port, or nil if there is none
+func (lb *LoadBalancer) Listener(port int64) *LBListener {
+	for _, l := range lb.Listeners {
+		if l.Port == port {
+			return l
+		}
+	}
+	return nil
+}
+
+// HealthyTargets returns the number of healthy targets behind the listener
+func (l *LBListener) HealthyTargets() int {
+	n := 0
+	for _, tg := range l.TargetGroups {
+		n += tg.Healthy
+	}
+	return n
+}
+
+// GetLoadBalancerEndpoint returns the DNS name and listeners of the load balancer with the
+// healthy target count of each listener, so that services fronted by a load balancer,
+// such as a self-hosted Redis Sentinel behind an NLB, are discovered through awsx
+func (a *Config) GetLoadBalancerEndpoint(name string) (*LoadBalancer, error) {
+	if name == "" {
+		return nil, errors.New("no load balancer name provided")
+	}
+
+	if a.Service.Elb == nil {
+		a.SetELBClient()
+	}
+
+	out, err := a.Service.Elb.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{name})})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.LoadBalancers) == 0 {
+		return nil, errors.New("no load balancer found with name " + name)
+	}
+	d := out.LoadBalancers[0]
+
+	lb := &LoadBalancer{
+		Name:      aws.StringValue(d.LoadBalancerName),
+		ARN:       aws.StringValue(d.LoadBalancerArn),
+		Type:      aws.StringValue(d.Type),
+		Scheme:    aws.StringValue(d.Scheme),
+		DNSName:   aws.StringValue(d.DNSName),
+		Listeners: make([]*LBListener, 0),
+	}
+	if d.State != nil {
+		lb.State = aws.StringValue(d.State.Code)
+	}
+
+	groups := map[string]*LBTargetGroup{}
+	err = a.Service.Elb.DescribeListenersPages(&elbv2.DescribeListenersInput{LoadBalancerArn: d.LoadBalancerArn}, func(page *elbv2.DescribeListenersOutput, lastPage bool) bool {
+		for _, l := range page.Listeners {
+			listener := &LBListener{Port: aws.Int64Value(l.Port), Protocol: aws.StringValue(l.Protocol)}
+			for _, arn := range forwardTargetGroups(l.DefaultActions) {
+				tg, ok := groups[arn]
+				if !ok {
+					tg = &LBTargetGroup{ARN: arn}
+					groups[arn] = tg
+				}
+				listener.TargetGroups = append(listener.TargetGroups, tg)
+			}
+			lb.Listeners = append(lb.Listeners, listener)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for arn, tg := range groups {
+		health, err := a.Service.Elb.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(arn)})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range health.TargetHealthDescriptions {
+			tg.Total++
+			if t.TargetHealth == nil {
+				continue
+			}
+			switch aws.StringValue(t.TargetHealth.State) {
+			case elbv2.TargetHealthStateEnumHealthy:
+				tg.Healthy++
+			case elbv2.TargetHealthStateEnumUnhealthy:
+				tg.Unhealthy++
+			}
+		}
+	}
+
+	return lb, nil
+}
+
+// forwardTargetGroups returns the target groups the forward actions send traffic to
+func forwardTargetGroups(actions []*elbv2.Action) []string {
+	arns := make([]string, 0)
+	for _, action := range actions {
+		if aws.StringValue(action.Type) != elbv2.ActionTypeEnumForward {
+			continue
+		}
+		if action.ForwardConfig != nil && len(action.ForwardConfig.TargetGroups) > 0 {
+			for _, tg := range action.ForwardConfig.TargetGroups {
+				arns = append(arns, aws.StringValue(tg.TargetGroupArn))
+			}
+		} else if action.TargetGroupArn != nil {
+			arns = append(arns, aws.StringValue(action.TargetGroupArn))
+		}
+	}
+	return arns
+}
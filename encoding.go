@@ -0,0 +1,243 @@
+package awsx
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"text/template"
+)
+
+// The endpoint types marshal to JSON and YAML as objects with stable snake_case field
+// names. Single endpoints also implement encoding.TextMarshaler as host:port so they can
+// be used as map keys, flag values and in text based configuration.
+
+type redisEndpointFields RedisEndpoint
+
+type auroraEndpointFields AuroraEndpoint
+
+// MarshalText returns host:port
+func (re *RedisEndpoint) MarshalText() ([]byte, error) {
+	return []byte(re.String()), nil
+}
+
+// UnmarshalText parses host:port
+func (re *RedisEndpoint) UnmarshalText(text []byte) error {
+	host, port, err := net.SplitHostPort(string(text))
+	if err != nil {
+		return err
+	}
+	p, err := ParsePort(port)
+	if err != nil {
+		return err
+	}
+	*re = RedisEndpoint{Host: host, Port: p}
+	return nil
+}
+
+// MarshalJSON marshals the endpoint as an object rather than the text form
+func (re *RedisEndpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*redisEndpointFields)(re))
+}
+
+// UnmarshalJSON accepts the object form or a host:port string. The port of the object
+// form may also be a string, as written before Port was an int.
+func (re *RedisEndpoint) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return re.UnmarshalText([]byte(text))
+	}
+
+	v := struct {
+		*redisEndpointFields
+		Port json.RawMessage `json:"port"`
+	}{redisEndpointFields: (*redisEndpointFields)(re)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return unmarshalPort(v.Port, &re.Port)
+}
+
+// MarshalYAML marshals the endpoint as a mapping rather than the text form
+func (re *RedisEndpoint) MarshalYAML() (interface{}, error) {
+	return (*redisEndpointFields)(re), nil
+}
+
+// MarshalText returns host:port
+func (ae *AuroraEndpoint) MarshalText() ([]byte, error) {
+	return []byte(ae.String()), nil
+}
+
+// UnmarshalText parses host:port
+func (ae *AuroraEndpoint) UnmarshalText(text []byte) error {
+	host, port, err := net.SplitHostPort(string(text))
+	if err != nil {
+		return err
+	}
+	p, err := ParsePort(port)
+	if err != nil {
+		return err
+	}
+	*ae = AuroraEndpoint{Host: host, Port: p}
+	return nil
+}
+
+// MarshalJSON marshals the endpoint as an object rather than the text form
+func (ae *AuroraEndpoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*auroraEndpointFields)(ae))
+}
+
+// UnmarshalJSON accepts the object form or a host:port string. The port of the object
+// form may also be a string, as written before Port was an int.
+func (ae *AuroraEndpoint) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return ae.UnmarshalText([]byte(text))
+	}
+
+	v := struct {
+		*auroraEndpointFields
+		Port json.RawMessage `json:"port"`
+	}{auroraEndpointFields: (*auroraEndpointFields)(ae)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return unmarshalPort(v.Port, &ae.Port)
+}
+
+// unmarshalPort decodes a JSON port that is a number or, as written before Port was an
+// int, a string
+func unmarshalPort(data json.RawMessage, port *int) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] != '"' {
+		return json.Unmarshal(data, port)
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	p, err := ParsePort(s)
+	if err != nil {
+		return err
+	}
+	*port = p
+	return nil
+}
+
+// MarshalYAML marshals the endpoint as a mapping rather than the text form
+func (ae *AuroraEndpoint) MarshalYAML() (interface{}, error) {
+	return (*auroraEndpointFields)(ae), nil
+}
+
+// EnvFile returns the endpoints as an env file for init containers and configuration
+// systems, with the variables of Env
+func (res *RedisEndpoints) EnvFile() string {
+	return envFile(res.Env())
+}
+
+// Env returns the endpoints as environment variable name/value pairs: REDIS_PRIMARY or,
+// with cluster mode enabled, REDIS_CLUSTER, followed by REDIS_READERS as a comma
+// separated list when there are read replicas. The awsx command uses the same names.
+func (res *RedisEndpoints) Env() [][2]string {
+	env := make([][2]string, 0, 2)
+	if res.ClusterEnabled && res.ClusterConfig != nil {
+		env = append(env, [2]string{"REDIS_CLUSTER", res.ClusterConfig.String()})
+	} else if res.Primary != nil {
+		env = append(env, [2]string{"REDIS_PRIMARY", res.Primary.String()})
+	}
+
+	readers := make([]string, 0, len(res.ReadEndpoints))
+	for _, r := range res.ReadEndpoints {
+		readers = append(readers, r.String())
+	}
+	if len(readers) > 0 {
+		env = append(env, [2]string{"REDIS_READERS", strings.Join(readers, ",")})
+	}
+
+	return env
+}
+
+// EnvFile returns the endpoints as an env file for init containers and configuration
+// systems, with the variables of Env
+func (aes *AuroraEndpoints) EnvFile() string {
+	return envFile(aes.Env())
+}
+
+// Env returns the endpoints as environment variable name/value pairs: AURORA_WRITER,
+// AURORA_READER and, when there are reader instances, AURORA_READERS as a comma
+// separated list. The awsx command uses the same names.
+func (aes *AuroraEndpoints) Env() [][2]string {
+	env := make([][2]string, 0, 3)
+	if aes.Writer != nil {
+		env = append(env, [2]string{"AURORA_WRITER", aes.Writer.String()})
+	}
+	if aes.Reader != nil {
+		env = append(env, [2]string{"AURORA_READER", aes.Reader.String()})
+	}
+	if readers := aes.Readers(); len(readers) > 0 {
+		env = append(env, [2]string{"AURORA_READERS", strings.Join(readers, ",")})
+	}
+
+	return env
+}
+
+// envFile formats name/value pairs one per line as NAME=value
+func envFile(env [][2]string) string {
+	var b strings.Builder
+	for _, kv := range env {
+		b.WriteString(kv[0] + "=" + kv[1] + "\n")
+	}
+	return b.String()
+}
+
+// renderFuncs are the functions available to the templates of Render besides the
+// text/template builtins
+var renderFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+}
+
+// Render executes the text/template with the endpoints as data, so any configuration
+// format can be written from a discovery result, e.g. HAProxy server lines:
+//
+//	{{range $i, $r := .ReadEndpoints}}server redis{{$i}} {{$r}} check{{if $r.TLS}} ssl{{end}}
+//	{{end}}
+//
+// Endpoints print as host:port, and the functions join, lower, upper and replace are
+// available besides the builtins.
+func (res *RedisEndpoints) Render(tmpl string) (string, error) {
+	return render(tmpl, res)
+}
+
+// Render executes the text/template with the endpoints as data, e.g. a pgbouncer
+// database entry:
+//
+//	orders = host={{.Writer.Host}} port={{.Writer.Port}} dbname=orders
+//
+// See RedisEndpoints.Render for the functions available.
+func (aes *AuroraEndpoints) Render(tmpl string) (string, error) {
+	return render(tmpl, aes)
+}
+
+func render(tmpl string, data interface{}) (string, error) {
+	t, err := template.New("awsx").Funcs(renderFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,202 @@
+package awsx
+
+import (
+	"net"
+	"strconv"
+)
+
+// Endpoint is a single discovered endpoint of any service, so generic connection pool
+// code can consume any awsx discovery result through an EndpointSet. ElastiCache Redis,
+// Aurora, RDS and DocumentDB clusters, Cloud Map services, ECS services and load
+// balancers are adapted; Memcached and MSK have no discovery functions yet.
+type Endpoint interface {
+	Host() string
+	Port() int
+	TLS() bool // connect with TLS
+	Role() EndpointRole
+	String() string // host:port
+}
+
+// EndpointSet is the endpoints of a discovery result, primaries first
+type EndpointSet []Endpoint
+
+// redisEndpoint adapts a RedisEndpoint to an Endpoint
+type redisEndpoint struct {
+	e *RedisEndpoint
+}
+
+func (r redisEndpoint) Host() string   { return r.e.Host }
+func (r redisEndpoint) Port() int      { return r.e.Port }
+func (r redisEndpoint) TLS() bool      { return r.e.TLS }
+func (r redisEndpoint) String() string { return r.e.String() }
+
+func (r redisEndpoint) Role() EndpointRole {
+	if r.e.Role == "" {
+		return RoleReplica
+	}
+	return r.e.Role
+}
+
+// auroraEndpoint adapts an AuroraEndpoint to an Endpoint with the role it has in the
+// cluster. MySQL and PostgreSQL negotiate TLS inside their own protocol after connecting
+// in plain text, so Aurora endpoints never require a TLS connection; drivers enable it
+// with RDSTLSConfig. DocumentDB clusters require TLS from the start by default.
+type auroraEndpoint struct {
+	e    *AuroraEndpoint
+	role EndpointRole
+	tls  bool
+}
+
+func (r auroraEndpoint) Host() string       { return r.e.Host }
+func (r auroraEndpoint) Port() int          { return r.e.Port }
+func (r auroraEndpoint) TLS() bool          { return r.tls }
+func (r auroraEndpoint) Role() EndpointRole { return r.role }
+func (r auroraEndpoint) String() string     { return r.e.String() }
+
+// endpoint is an Endpoint of a result without an endpoint type of its own, such as the
+// host:port strings of an ECS service or the listeners of a load balancer
+type endpoint struct {
+	host string
+	port int
+	tls  bool
+	role EndpointRole
+}
+
+func (e endpoint) Host() string       { return e.host }
+func (e endpoint) Port() int          { return e.port }
+func (e endpoint) TLS() bool          { return e.tls }
+func (e endpoint) Role() EndpointRole { return e.role }
+func (e endpoint) String() string     { return net.JoinHostPort(e.host, strconv.Itoa(e.port)) }
+
+// EndpointSet returns the endpoints as an EndpointSet: the configuration endpoint when
+// cluster mode is enabled, otherwise the primary endpoint followed by the nodes
+func (res *RedisEndpoints) EndpointSet() EndpointSet {
+	set := make(EndpointSet, 0, len(res.ReadEndpoints)+1)
+	if res.ClusterEnabled {
+		if res.ClusterConfig != nil {
+			set = append(set, redisEndpoint{res.ClusterConfig})
+		}
+		return set
+	}
+
+	if res.Primary != nil && res.Primary.Host != "" {
+		set = append(set, redisEndpoint{res.Primary})
+	}
+	for _, re := range res.ReadEndpoints {
+		set = append(set, redisEndpoint{re})
+	}
+	return set
+}
+
+// EndpointSet returns the endpoints as an EndpointSet: the writer endpoint as the
+// primary, followed by the reader instances as replicas, or the reader endpoint when
+// the instances are not known
+func (aes *AuroraEndpoints) EndpointSet() EndpointSet {
+	tls := aes.Engine == "docdb"
+	set := make(EndpointSet, 0, len(aes.ReadEndpoints)+1)
+	if aes.Writer != nil {
+		set = append(set, auroraEndpoint{aes.Writer, RolePrimary, tls})
+	}
+	for _, ae := range aes.ReadEndpoints {
+		set = append(set, auroraEndpoint{ae, RoleReplica, tls})
+	}
+	if len(aes.ReadEndpoints) == 0 && aes.Reader != nil {
+		set = append(set, auroraEndpoint{aes.Reader, RoleReplica, tls})
+	}
+	return set
+}
+
+// EndpointSet returns the writer endpoint as the primary followed by the reader endpoint
+// as a replica
+func (s *RDSClusterSummary) EndpointSet() EndpointSet {
+	tls := s.Engine == "docdb"
+	set := make(EndpointSet, 0, 2)
+	if s.Writer != nil {
+		set = append(set, auroraEndpoint{s.Writer, RolePrimary, tls})
+	}
+	if s.Reader != nil {
+		set = append(set, auroraEndpoint{s.Reader, RoleReplica, tls})
+	}
+	return set
+}
+
+// EndpointSet returns the endpoints of the service as replicas, as every task serves
+// the same traffic. Endpoints that are not host:port are left out.
+func (res *ECSServiceEndpoints) EndpointSet() EndpointSet {
+	set := make(EndpointSet, 0, len(res.Endpoints))
+	for _, hp := range res.Endpoints {
+		host, port, err := net.SplitHostPort(hp)
+		if err != nil {
+			continue
+		}
+		p, err := ParsePort(port)
+		if err != nil {
+			continue
+		}
+		set = append(set, endpoint{host: host, port: p, role: RoleReplica})
+	}
+	return set
+}
+
+// EndpointSet returns the DNS name of the load balancer on each listener port as a
+// primary, with TLS for HTTPS and TLS listeners
+func (lb *LoadBalancer) EndpointSet() EndpointSet {
+	set := make(EndpointSet, 0, len(lb.Listeners))
+	for _, l := range lb.Listeners {
+		tls := l.Protocol == "HTTPS" || l.Protocol == "TLS"
+		set = append(set, endpoint{host: lb.DNSName, port: int(l.Port), tls: tls, role: RolePrimary})
+	}
+	return set
+}
+
+// Primary returns the first primary or configuration endpoint, or nil if there is none
+func (s EndpointSet) Primary() Endpoint {
+	for _, e := range s {
+		if e.Role() == RolePrimary || e.Role() == RoleConfig {
+			return e
+		}
+	}
+	return nil
+}
+
+// Replicas returns the replica endpoints
+func (s EndpointSet) Replicas() EndpointSet {
+	return s.WithRole(RoleReplica)
+}
+
+// ReplicasOrPrimary returns the replica endpoints, or the primary when there are none,
+// for read traffic that may fall back to the primary
+func (s EndpointSet) ReplicasOrPrimary() EndpointSet {
+	if replicas := s.Replicas(); len(replicas) > 0 {
+		return replicas
+	}
+	if p := s.Primary(); p != nil {
+		return EndpointSet{p}
+	}
+	return EndpointSet{}
+}
+
+// WithRole returns the endpoints with the role
+func (s EndpointSet) WithRole(role EndpointRole) EndpointSet {
+	return s.Filter(func(e Endpoint) bool { return e.Role() == role })
+}
+
+// Filter returns the endpoints for which keep returns true
+func (s EndpointSet) Filter(keep func(e Endpoint) bool) EndpointSet {
+	set := make(EndpointSet, 0, len(s))
+	for _, e := range s {
+		if keep(e) {
+			set = append(set, e)
+		}
+	}
+	return set
+}
+
+// Strings returns host:port of every endpoint
+func (s EndpointSet) Strings() []string {
+	str := make([]string, 0, len(s))
+	for _, e := range s {
+		str = append(str, net.JoinHostPort(e.Host(), strconv.Itoa(e.Port())))
+	}
+	return str
+}
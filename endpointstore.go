@@ -0,0 +1,216 @@
+package awsx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// endpointStoreVersion is the version of the endpoint store file format
+	endpointStoreVersion = 1
+	// storeRefreshMin and storeRefreshMax bound the backoff of the background refresh
+	// of a discovery result served from the endpoint store
+	storeRefreshMin = time.Second
+	storeRefreshMax = time.Minute
+)
+
+// storedTypes returns an empty result of the type stored under the cache key prefix.
+// Only endpoint results are persisted.
+var storedTypes = map[string]func() interface{}{
+	"redis":           func() interface{} { return &RedisEndpoints{} },
+	"aurora":          func() interface{} { return &AuroraEndpoints{} },
+	"cloudmap-redis":  func() interface{} { return &RedisEndpoints{} },
+	"cloudmap-aurora": func() interface{} { return &AuroraEndpoints{} },
+	"ecs":             func() interface{} { return &ECSServiceEndpoints{} },
+}
+
+// endpointStore persists the last successful discovery results in a state file, see
+// EncodeState
+type endpointStore struct {
+	path   string
+	maxAge time.Duration
+	format StateFormat
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+// endpointStoreFile is the file format of the endpoint store
+type endpointStoreFile struct {
+	Version int                            `json:"version"`
+	Entries map[string]*endpointStoreEntry `json:"entries"`
+}
+
+// endpointStoreEntry is a stored discovery result with the time it was discovered. The
+// value is encoded with the codec of the store.
+type endpointStoreEntry struct {
+	Stored  time.Time       `json:"stored"`
+	Expires time.Time       `json:"expires"` // after this the result is not used
+	Value   json.RawMessage `json:"value"`
+}
+
+// SetEndpointStore persists the last successful result of the endpoint discovery
+// functions to a state file at path, encoded as JSON. When a Describe call fails, e.g.
+// while the AWS control plane is briefly unavailable, a stored result younger than maxAge
+// is returned instead and discovery is retried in the background until it succeeds, so
+// services can start and connect with the last known endpoints. Results are stored per
+// region. The file is only rewritten when a result changed or half its max age passed.
+func (a *Config) SetEndpointStore(path string, maxAge time.Duration) *Config {
+	return a.SetEndpointStoreFormat(path, maxAge, StateFormat{})
+}
+
+// SetEndpointStoreFormat is SetEndpointStore with the codec and compression of the file,
+// e.g. GobCodec for many accounts and regions. The codec must encode plain Go values, as
+// JSONCodec and GobCodec do; codecs limited to their own message types, such as the
+// protobuf codec of awsxpb, are rejected.
+func (a *Config) SetEndpointStoreFormat(path string, maxAge time.Duration, f StateFormat) *Config {
+	if path == "" || maxAge <= 0 {
+		a.warn("No path or max age specified in call to SetEndpointStore(path string, maxAge time.Duration)")
+		a.endpointStore = nil
+		return a
+	}
+	if f.Codec == nil {
+		f.Codec = JSONCodec{}
+	}
+	if _, err := EncodeState(&endpointStoreFile{Version: endpointStoreVersion}, f); err != nil {
+		a.warn("Endpoint store format cannot encode the store: " + err.Error())
+		a.endpointStore = nil
+		return a
+	}
+	a.endpointStore = &endpointStore{path: path, maxAge: maxAge, format: f, refreshing: map[string]bool{}}
+	return a
+}
+
+// stored calls fetch and stores a successful result of a persisted type in the endpoint
+// store. If fetch fails and the stale policy allows it, a stored result is returned
+// instead with stale set, and fetch is retried in the background.
+func (a *Config) stored(key string, fetch func() (interface{}, error)) (interface{}, bool, error) {
+	s := a.endpointStore
+	newValue, persisted := storedTypes[strings.SplitN(key, ":", 2)[0]]
+	if s == nil || !persisted {
+		value, err := fetch()
+		return value, false, err
+	}
+	storeKey := a.GetRegion() + "/" + key
+
+	value, err := fetch()
+	if err == nil {
+		if err := s.save(storeKey, value, a.clock().Now()); err != nil {
+			a.warn("Error writing endpoint store " + s.path + ": " + err.Error())
+		}
+		return value, false, nil
+	}
+
+	entry, ok := s.load(storeKey)
+	if !ok || !a.clock().Now().Before(entry.Expires) {
+		return nil, false, err
+	}
+	last := newValue()
+	if s.format.Codec.Unmarshal(entry.Value, last) != nil {
+		return nil, false, err
+	}
+	stale := a.serveStale(key, last, entry.Stored, err)
+	if stale == nil {
+		return nil, false, err
+	}
+
+	go a.refreshStored(key, storeKey, entry.Expires, fetch)
+
+	return stale, true, nil
+}
+
+// refreshStored retries fetch with backoff until it succeeds or the stored result it
+// replaces expires, then updates the store and the result cache
+func (a *Config) refreshStored(key, storeKey string, expires time.Time, fetch func() (interface{}, error)) {
+	s := a.endpointStore
+	s.mu.Lock()
+	if s.refreshing[storeKey] {
+		s.mu.Unlock()
+		return
+	}
+	s.refreshing[storeKey] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.refreshing, storeKey)
+		s.mu.Unlock()
+	}()
+
+	clock := a.clock()
+	wait := storeRefreshMin
+	for clock.Now().Before(expires) {
+		clock.Sleep(wait)
+
+		value, err := fetch()
+		if err == nil {
+			if err := s.save(storeKey, value, clock.Now()); err != nil {
+				a.warn("Error writing endpoint store " + s.path + ": " + err.Error())
+			}
+			a.remember(key, value)
+			return
+		}
+
+		wait *= 2
+		if wait > storeRefreshMax {
+			wait = storeRefreshMax
+		}
+	}
+}
+
+// load returns the stored entry for key
+func (s *endpointStore) load(key string) (*endpointStoreEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.read().Entries[key]
+	return entry, ok
+}
+
+// save stores the value under key, replacing the file atomically. The file is left as
+// it is when the stored value is the same and was stored less than half the max age ago,
+// so steady discovery doesn't rewrite it on every call.
+func (s *endpointStore) save(key string, value interface{}, now time.Time) error {
+	data, err := s.format.Codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file := s.read()
+	if last, ok := file.Entries[key]; ok && bytes.Equal(last.Value, data) && now.Sub(last.Stored) < s.maxAge/2 {
+		return nil
+	}
+
+	file.Entries[key] = &endpointStoreEntry{Stored: now.UTC(), Expires: now.Add(s.maxAge).UTC(), Value: data}
+	for k, e := range file.Entries {
+		if !now.Before(e.Expires) {
+			delete(file.Entries, k)
+		}
+	}
+
+	return WriteStateFile(s.path, file, s.format)
+}
+
+// read returns the contents of the store file, or an empty store if the file does not
+// exist or cannot be decoded. Plain JSON files written before the store used the state
+// encoding are still read.
+func (s *endpointStore) read() *endpointStoreFile {
+	file := &endpointStoreFile{}
+	if data, err := ioutil.ReadFile(s.path); err == nil {
+		if DecodeState(data, file) != nil && json.Unmarshal(data, file) != nil || file.Version != endpointStoreVersion {
+			file = &endpointStoreFile{}
+		}
+	}
+	file.Version = endpointStoreVersion
+	if file.Entries == nil {
+		file.Entries = map[string]*endpointStoreEntry{}
+	}
+	return file
+}
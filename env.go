@@ -0,0 +1,166 @@
+package awsx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultEnvPrefix is the environment variable prefix used when none is provided
+const defaultEnvPrefix = "AWSX"
+
+// identifierPattern matches valid ElastiCache and RDS cluster identifiers: a letter
+// followed by letters, digits and single hyphens, not ending in a hyphen
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z](-?[a-zA-Z0-9])*$`)
+
+// EnvEndpoints is the bundle of endpoints resolved by ResolveFromEnv. Entries are nil
+// when the corresponding environment variable is not set.
+type EnvEndpoints struct {
+	Redis  *RedisEndpoints
+	Aurora *AuroraEndpoints
+}
+
+// ResolveFromEnv reads cluster identifiers from <prefix>_REDIS_CLUSTER and
+// <prefix>_AURORA_CLUSTER, validates them, and discovers their endpoints so that
+// services can fail at startup when their datastores are misconfigured. The prefix
+// defaults to AWSX.
+func (a *Config) ResolveFromEnv(prefix string) (*EnvEndpoints, error) {
+	prefix = envPrefix(prefix)
+	env := &EnvEndpoints{}
+
+	redisVar := prefix + "REDIS_CLUSTER"
+	if cluster, ok := os.LookupEnv(redisVar); ok {
+		if err := validateIdentifier(cluster, 40); err != nil {
+			return nil, fmt.Errorf("%s: %v", redisVar, err)
+		}
+		res, err := a.GetRedisPrimaryEndpoint(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", redisVar, err)
+		}
+		env.Redis = res
+	}
+
+	auroraVar := prefix + "AURORA_CLUSTER"
+	if cluster, ok := os.LookupEnv(auroraVar); ok {
+		if err := validateIdentifier(cluster, 63); err != nil {
+			return nil, fmt.Errorf("%s: %v", auroraVar, err)
+		}
+		aes, err := a.GetAuroraEndpoints(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", auroraVar, err)
+		}
+		env.Aurora = aes
+	}
+
+	if env.Redis == nil && env.Aurora == nil {
+		return nil, errors.New("none of " + redisVar + " or " + auroraVar + " are set")
+	}
+
+	return env, nil
+}
+
+// FromEnv builds a Config from AWSX_ prefixed environment variables, for deployments
+// without a config file. See FromEnvPrefix for the variables read.
+func FromEnv() (*Config, error) {
+	return FromEnvPrefix(defaultEnvPrefix)
+}
+
+// FromEnvPrefix builds a Config from environment variables with the prefix, which
+// defaults to AWSX. The variables mirror the fields of FileConfig:
+//
+//	AWSX_REGION, AWSX_REGIONS (comma separated), AWSX_FALLBACK_REGION
+//	AWSX_PROFILE, AWSX_CRED_FILE, AWSX_PROVIDERS (comma separated)
+//	AWSX_ROLE_ARN, AWSX_EXTERNAL_ID
+//	AWSX_ENDPOINT, AWSX_ENDPOINT_<SERVICE> (e.g. AWSX_ENDPOINT_ELASTICACHE)
+//	AWSX_CACHE_TTL, AWSX_METADATA_TIMEOUT, AWSX_HTTP_TIMEOUT (durations such as 30s)
+//	AWSX_MAX_RETRIES, AWSX_PANIC
+func FromEnvPrefix(prefix string) (*Config, error) {
+	prefix = envPrefix(prefix)
+
+	fc := &FileConfig{
+		Region:           os.Getenv(prefix + "REGION"),
+		Regions:          envList(prefix + "REGIONS"),
+		Profile:          os.Getenv(prefix + "PROFILE"),
+		CredFile:         os.Getenv(prefix + "CRED_FILE"),
+		Providers:        envList(prefix + "PROVIDERS"),
+		Role:             os.Getenv(prefix + "ROLE_ARN"),
+		ExternalID:       os.Getenv(prefix + "EXTERNAL_ID"),
+		Endpoint:         os.Getenv(prefix + "ENDPOINT"),
+		ServiceEndpoints: map[string]string{},
+		CacheTTL:         os.Getenv(prefix + "CACHE_TTL"),
+		MetadataTimeout:  os.Getenv(prefix + "METADATA_TIMEOUT"),
+		HTTPTimeout:      os.Getenv(prefix + "HTTP_TIMEOUT"),
+	}
+
+	if v, ok := os.LookupEnv(prefix + "FALLBACK_REGION"); ok {
+		fc.FallbackRegion = &v
+	}
+	if v := os.Getenv(prefix + "MAX_RETRIES"); v != "" {
+		retries, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%sMAX_RETRIES: %v", prefix, err)
+		}
+		fc.MaxRetries = &retries
+	}
+	if v := os.Getenv(prefix + "PANIC"); v != "" {
+		panicOnErr, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%sPANIC: %v", prefix, err)
+		}
+		fc.Panic = panicOnErr
+	}
+
+	endpointPrefix := prefix + "ENDPOINT_"
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 && strings.HasPrefix(parts[0], endpointPrefix) && parts[1] != "" {
+			fc.ServiceEndpoints[strings.ToLower(strings.TrimPrefix(parts[0], endpointPrefix))] = parts[1]
+		}
+	}
+
+	a, err := fc.Config()
+	if err != nil {
+		return nil, fmt.Errorf("environment config: %v", err)
+	}
+
+	return a, nil
+}
+
+// envList splits a comma separated environment variable, ignoring empty entries
+func envList(name string) []string {
+	list := make([]string, 0)
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// envPrefix normalizes an environment variable prefix to end in an underscore
+func envPrefix(prefix string) string {
+	if prefix == "" {
+		prefix = defaultEnvPrefix
+	}
+	if !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	return prefix
+}
+
+// validateIdentifier checks an ElastiCache or RDS identifier against the naming rules
+func validateIdentifier(id string, max int) error {
+	if id == "" {
+		return errors.New("identifier is empty")
+	}
+	if len(id) > max {
+		return fmt.Errorf("identifier %q is longer than %d characters", id, max)
+	}
+	if !identifierPattern.MatchString(id) {
+		return fmt.Errorf("identifier %q must start with a letter and contain only letters, digits and single hyphens", id)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+)
+
+const (
+	defaultEventBus        = "default"
+	defaultEventSource     = "awsx"
+	defaultEventDetailType = "Topology Change"
+)
+
+// GetEventBridgeClient returns a client for use with Amazon EventBridge
+func (a *Config) GetEventBridgeClient() eventbridgeiface.EventBridgeAPI {
+	return a.Service.Events
+}
+
+// SetEventBridgeClient sets a client for use with Amazon EventBridge
+func (a *Config) SetEventBridgeClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Events = eventbridge.New(a.ClientConfig(eventbridge.EndpointsID))
+
+	return a
+}
+
+// WithEventBridgeClient sets the client used for Amazon EventBridge calls, such as a mock
+// from the awsxmock package
+func (a *Config) WithEventBridgeClient(client eventbridgeiface.EventBridgeAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Events = client
+
+	return a
+}
+
+// EventBridgeOptions configures how topology events are published to EventBridge
+type EventBridgeOptions struct {
+	BusName    string          // optional: name or ARN of the event bus, defaults to the default bus
+	Source     string          // optional: source of the events, defaults to awsx
+	DetailType string          // optional: detail-type of the events, defaults to "Topology Change"
+	OnError    func(err error) // optional: receives errors of events published by PublishToEventBridge
+}
+
+// TopologyEventDetail is the detail of the EventBridge events published for a
+// TopologyEvent, which rules can match on, e.g. {"detail": {"switchover": [true]}}
+type TopologyEventDetail struct {
+	Seq        uint64           `json:"seq"`
+	Cluster    string           `json:"cluster"`
+	Time       time.Time        `json:"time"`
+	Switchover bool             `json:"switchover"`
+	Error      string           `json:"error,omitempty"`
+	Redis      *RedisEndpoints  `json:"redis,omitempty"`
+	Aurora     *AuroraEndpoints `json:"aurora,omitempty"`
+}
+
+// PutTopologyEvent publishes the topology event to an EventBridge bus
+func (a *Config) PutTopologyEvent(ctx context.Context, ev TopologyEvent, opts *EventBridgeOptions) error {
+	if opts == nil {
+		opts = &EventBridgeOptions{}
+	}
+
+	if a.Service.Events == nil {
+		a.SetEventBridgeClient()
+	}
+
+	detail := TopologyEventDetail{
+		Seq:        ev.Seq,
+		Cluster:    ev.Cluster,
+		Time:       ev.Time,
+		Switchover: ev.Switchover,
+		Redis:      ev.Redis,
+		Aurora:     ev.Aurora,
+	}
+	if ev.Err != nil {
+		detail.Error = ev.Err.Error()
+	}
+	b, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+
+	entry := &eventbridge.PutEventsRequestEntry{
+		EventBusName: aws.String(stringOr(opts.BusName, defaultEventBus)),
+		Source:       aws.String(stringOr(opts.Source, defaultEventSource)),
+		DetailType:   aws.String(stringOr(opts.DetailType, defaultEventDetailType)),
+		Detail:       aws.String(string(b)),
+		Time:         aws.Time(ev.Time),
+	}
+	if ev.Aurora != nil && ev.Aurora.ARN != "" {
+		entry.Resources = aws.StringSlice([]string{ev.Aurora.ARN})
+	}
+
+	out, err := a.Service.Events.PutEventsWithContext(ctx, &eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{entry},
+	})
+	if err != nil {
+		return err
+	}
+	if aws.Int64Value(out.FailedEntryCount) > 0 && len(out.Entries) > 0 {
+		return errors.New("event rejected by EventBridge: " + aws.StringValue(out.Entries[0].ErrorCode) + ": " +
+			aws.StringValue(out.Entries[0].ErrorMessage))
+	}
+
+	return nil
+}
+
+// PublishToEventBridge subscribes to the watcher and publishes every topology event to an
+// EventBridge bus, so other systems such as cache warmers and dashboards can react to
+// changes. Publishing stops when the watcher is stopped.
+func (w *Watcher) PublishToEventBridge(opts *EventBridgeOptions) *Watcher {
+	if opts == nil {
+		opts = &EventBridgeOptions{}
+	}
+
+	sub := w.Subscribe(SubscribeOptions{Policy: DropOldest})
+	go func() {
+		for ev := range sub.C {
+			if err := w.config.PutTopologyEvent(context.Background(), ev, opts); err != nil && opts.OnError != nil {
+				opts.OnError(err)
+			}
+		}
+	}()
+
+	return w
+}
+
+// stringOr returns s, or def when s is empty
+func stringOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// Event categories of a ServiceEvent
+const (
+	EventFailover    = "failover"
+	EventMaintenance = "maintenance"
+	EventSnapshot    = "snapshot"
+	EventOther       = "other"
+)
+
+// ServiceEvent is an RDS or ElastiCache event normalized so operators and watchers can
+// correlate topology changes with AWS events
+type ServiceEvent struct {
+	Service    string    `json:"service" yaml:"service"`         // rds or elasticache
+	SourceType string    `json:"source_type" yaml:"source_type"` // e.g. db-cluster or replication-group
+	SourceID   string    `json:"source_id" yaml:"source_id"`
+	Time       time.Time `json:"time" yaml:"time"`
+	Category   string    `json:"category" yaml:"category"` // failover, maintenance, snapshot or other
+	Message    string    `json:"message" yaml:"message"`
+}
+
+// GetRecentEvents returns the events of a source since the given time, oldest first. The
+// service is chosen by the source type: RDS for db-instance, db-cluster and the other
+// db- types, ElastiCache for replication-group, cache-cluster and the other cache types.
+// An empty sourceID returns the events of every source of the type. AWS keeps events
+// for 14 days.
+func (a *Config) GetRecentEvents(sourceType, sourceID string, since time.Time) ([]*ServiceEvent, error) {
+	return a.recentEvents(context.Background(), sourceType, sourceID, since)
+}
+
+// recentEvents is GetRecentEvents with a context
+func (a *Config) recentEvents(ctx context.Context, sourceType, sourceID string, since time.Time) ([]*ServiceEvent, error) {
+	if sourceType == "" {
+		return nil, errors.New("no source type provided")
+	}
+
+	var events []*ServiceEvent
+	var err error
+	if isRDSSourceType(sourceType) {
+		events, err = a.rdsEvents(ctx, sourceType, sourceID, since)
+	} else {
+		events, err = a.ecEvents(ctx, sourceType, sourceID, since)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events, nil
+}
+
+// isRDSSourceType reports whether the source type is an RDS rather than ElastiCache type
+func isRDSSourceType(sourceType string) bool {
+	return strings.HasPrefix(sourceType, "db-") ||
+		sourceType == rds.SourceTypeBlueGreenDeployment ||
+		sourceType == rds.SourceTypeCustomEngineVersion
+}
+
+func (a *Config) rdsEvents(ctx context.Context, sourceType, sourceID string, since time.Time) ([]*ServiceEvent, error) {
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeEventsInput{
+		SourceType: aws.String(sourceType),
+		StartTime:  aws.Time(since),
+	}
+	if sourceID != "" {
+		input.SourceIdentifier = aws.String(sourceID)
+	}
+
+	events := make([]*ServiceEvent, 0)
+	err := a.Service.Rds.DescribeEventsPagesWithContext(ctx, input, func(page *rds.DescribeEventsOutput, lastPage bool) bool {
+		for _, e := range page.Events {
+			events = append(events, &ServiceEvent{
+				Service:    "rds",
+				SourceType: aws.StringValue(e.SourceType),
+				SourceID:   aws.StringValue(e.SourceIdentifier),
+				Time:       aws.TimeValue(e.Date),
+				Category:   eventCategory(aws.StringValue(e.Message), aws.StringValueSlice(e.EventCategories)),
+				Message:    aws.StringValue(e.Message),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (a *Config) ecEvents(ctx context.Context, sourceType, sourceID string, since time.Time) ([]*ServiceEvent, error) {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeEventsInput{
+		SourceType: aws.String(sourceType),
+		StartTime:  aws.Time(since),
+	}
+	if sourceID != "" {
+		input.SourceIdentifier = aws.String(sourceID)
+	}
+
+	events := make([]*ServiceEvent, 0)
+	err := a.Service.Ec.DescribeEventsPagesWithContext(ctx, input, func(page *elasticache.DescribeEventsOutput, lastPage bool) bool {
+		for _, e := range page.Events {
+			events = append(events, &ServiceEvent{
+				Service:    "elasticache",
+				SourceType: aws.StringValue(e.SourceType),
+				SourceID:   aws.StringValue(e.SourceIdentifier),
+				Time:       aws.TimeValue(e.Date),
+				Category:   eventCategory(aws.StringValue(e.Message), nil),
+				Message:    aws.StringValue(e.Message),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// eventCategory normalizes the RDS event categories, or the message for ElastiCache
+// events which have no categories
+func eventCategory(message string, categories []string) string {
+	for _, c := range categories {
+		switch c {
+		case "failover", "failure", "recovery":
+			return EventFailover
+		case "maintenance":
+			return EventMaintenance
+		case "backup", "restoration":
+			return EventSnapshot
+		}
+	}
+
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "failover") || strings.Contains(msg, "promot"):
+		return EventFailover
+	case strings.Contains(msg, "snapshot") || strings.Contains(msg, "backup"):
+		return EventSnapshot
+	case strings.Contains(msg, "maintenance") || strings.Contains(msg, "patch") ||
+		strings.Contains(msg, "upgrad") || strings.Contains(msg, "reboot"):
+		return EventMaintenance
+	}
+
+	return EventOther
+}
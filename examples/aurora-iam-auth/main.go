@@ -0,0 +1,57 @@
+//go:build example
+// +build example
+
+// Command aurora-iam-auth discovers the writer endpoint of an Aurora MySQL cluster and
+// connects to it with an IAM database authentication token signed by the awsx session.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+	"github.com/go-sql-driver/mysql"
+	"github.com/routebyintuition/awsx"
+)
+
+func main() {
+	cluster := flag.String("cluster", "", "Aurora DB cluster identifier")
+	user := flag.String("user", "", "database user with the AWSAuthenticationPlugin")
+	flag.Parse()
+
+	a := awsx.NewAWS().WithAllProviders()
+	a.SetSession()
+
+	endpoints, err := a.GetAuroraEndpoints(*cluster)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	token, err := rdsutils.BuildAuthToken(endpoints.WriterString(), a.GetRegion(), *user, a.Session.Config.Credentials)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = *user
+	cfg.Passwd = token
+	cfg.Net = "tcp"
+	cfg.Addr = endpoints.WriterString()
+	cfg.TLSConfig = "true"
+	cfg.AllowCleartextPasswords = true
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("connected to", endpoints.WriterString(), "running", version)
+}
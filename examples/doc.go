@@ -0,0 +1,12 @@
+// Package examples holds runnable programs showing how awsx is used, one per directory:
+//
+//	redis-connect            discover a Redis cluster and connect to it with go-redis
+//	aurora-iam-auth          connect to an Aurora MySQL cluster with an IAM auth token
+//	watch-reconnect          reconnect to Redis whenever a Watcher reports a failover
+//	multi-account-inventory  list Redis and Aurora clusters across accounts and regions
+//
+// The programs import drivers such as go-redis that the awsx module does not depend on,
+// so they are built only with the example tag, e.g.:
+//
+//	go run -tags example ./examples/redis-connect -cluster orders
+package examples
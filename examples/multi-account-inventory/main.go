@@ -0,0 +1,50 @@
+//go:build example
+// +build example
+
+// Command multi-account-inventory lists the Redis replication groups and Aurora clusters
+// of several accounts, reached by assuming a role in each, across several regions.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/routebyintuition/awsx"
+)
+
+func main() {
+	roles := flag.String("roles", "", "comma separated role ARNs, one per account")
+	regions := flag.String("regions", "us-east-1,us-west-2", "comma separated regions")
+	flag.Parse()
+
+	for _, role := range strings.Split(*roles, ",") {
+		a := awsx.NewAWS().WithAllProviders().WithRoleChain(role)
+		a.SetRegion(strings.Split(*regions, ",")[0])
+		a.SetSession()
+
+		for _, region := range strings.Split(*regions, ",") {
+			rc := a.ForRegion(region)
+
+			groups, err := rc.ListECReplicationGroups()
+			if err != nil {
+				log.Println(role, region, err)
+				continue
+			}
+			for _, rg := range groups {
+				fmt.Printf("%s\t%s\tredis\t%s\n", role, region, aws.StringValue(rg.ReplicationGroupId))
+			}
+
+			clusters, err := rc.ListRDSClusters(nil)
+			if err != nil {
+				log.Println(role, region, err)
+				continue
+			}
+			for _, c := range clusters {
+				fmt.Printf("%s\t%s\t%s\t%s\t%s\n", role, region, c.Engine, c.Cluster, c.Writer)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+//go:build example
+// +build example
+
+// Command redis-connect discovers the endpoints of an ElastiCache Redis cluster and
+// connects to it with go-redis, using a cluster client when cluster mode is enabled.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/routebyintuition/awsx"
+)
+
+func main() {
+	cluster := flag.String("cluster", "", "replication group or cache cluster ID")
+	region := flag.String("region", "", "AWS region, detected if empty")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	a := awsx.NewAWS().WithAllProviders()
+	if *region != "" {
+		a.SetRegion(*region)
+	}
+	a.SetSession()
+
+	endpoints, err := a.GetRedisPrimaryEndpoint(*cluster)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var client redis.UniversalClient
+	if endpoints.ClusterEnabled {
+		client = redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{endpoints.ClusterConfigString()}})
+	} else {
+		client = redis.NewClient(&redis.Options{Addr: endpoints.PrimaryString()})
+	}
+	defer client.Close()
+
+	if err := client.Set(ctx, "awsx:example", time.Now().String(), time.Minute).Err(); err != nil {
+		log.Fatal(err)
+	}
+	value, err := client.Get(ctx, "awsx:example").Result()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("connected to", endpoints, "read back", value)
+}
@@ -0,0 +1,73 @@
+//go:build example
+// +build example
+
+// Command watch-reconnect keeps a go-redis client pointed at the current primary of an
+// ElastiCache replication group, replacing it whenever the Watcher reports a topology
+// change such as a failover.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/routebyintuition/awsx"
+)
+
+func main() {
+	cluster := flag.String("cluster", "", "replication group ID")
+	interval := flag.Duration("interval", 15*time.Second, "discovery poll interval")
+	flag.Parse()
+
+	a := awsx.NewAWS().WithAllProviders()
+	a.SetSession()
+
+	shutdown := awsx.RegisterShutdown(context.Background())
+
+	w := a.WatchRedis(*cluster, *interval)
+	sub := w.Subscribe(awsx.SubscribeOptions{Buffer: 1, Policy: awsx.Coalesce})
+	shutdown.AddWatcher(w)
+	w.Start()
+
+	var mu sync.Mutex
+	var client *redis.Client
+
+	go func() {
+		for ev := range sub.C {
+			if ev.Err != nil {
+				log.Println("discovery failed:", ev.Err)
+				continue
+			}
+
+			mu.Lock()
+			if client != nil {
+				client.Close()
+			}
+			client = redis.NewClient(&redis.Options{Addr: ev.Redis.PrimaryString()})
+			mu.Unlock()
+
+			log.Println("connected to primary", ev.Redis.PrimaryString())
+		}
+	}()
+
+	for {
+		select {
+		case <-shutdown.Done():
+			return
+		case <-time.After(time.Second):
+		}
+
+		mu.Lock()
+		c := client
+		mu.Unlock()
+		if c == nil {
+			continue
+		}
+		if err := c.Incr(context.Background(), "awsx:example:counter").Err(); err != nil {
+			log.Println("write failed:", err)
+		}
+	}
+}
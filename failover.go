@@ -0,0 +1,89 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// failoverPollInterval is how often TestFailover checks events and the group status
+const failoverPollInterval = 10 * time.Second
+
+// FailoverProgressFunc is called with the message of every ElastiCache event of the
+// replication group seen while TestFailover waits
+type FailoverProgressFunc func(message string, elapsed time.Duration)
+
+// TestFailover starts an ElastiCache test failover of a node group, e.g. for game day
+// drills, and monitors the events of the replication group until the failover completes
+// and the group is available again, or ctx is done. The refreshed endpoints are returned.
+func (a *Config) TestFailover(ctx context.Context, replicationGroup, nodeGroupID string, progress ...FailoverProgressFunc) (*RedisEndpoints, error) {
+	if replicationGroup == "" || nodeGroupID == "" {
+		return nil, errors.New("must provide a replication group and node group id")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	start := a.clock().Now()
+	_, err := a.Service.Ec.TestFailoverWithContext(ctx, &elasticache.TestFailoverInput{
+		ReplicationGroupId: aws.String(replicationGroup),
+		NodeGroupId:        aws.String(nodeGroupID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.forget("redis:" + replicationGroup)
+
+	seen := map[string]bool{}
+	completed := false
+	for {
+		a.clock().Sleep(failoverPollInterval)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		events, err := a.recentEvents(ctx, elasticache.SourceTypeReplicationGroup, replicationGroup, start)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			msg := e.Message
+			if seen[msg] {
+				continue
+			}
+			seen[msg] = true
+			for _, p := range progress {
+				p(msg, a.since(start))
+			}
+			if isFailoverCompleted(msg) {
+				completed = true
+			}
+		}
+		if !completed {
+			continue
+		}
+
+		result, count, err := a.getECReplicationGroup(ctx, replicationGroup)
+		if err != nil {
+			return nil, err
+		}
+		if count == 1 && aws.StringValue(result.ReplicationGroups[0].Status) == "available" {
+			break
+		}
+	}
+	a.forget("redis:" + replicationGroup)
+
+	return a.GetRedisPrimaryEndpoint(replicationGroup)
+}
+
+// isFailoverCompleted reports whether an ElastiCache event message announces the end of
+// a failover, e.g. "Failover from primary node x to replica node y completed"
+func isFailoverCompleted(message string) bool {
+	msg := strings.ToLower(message)
+	return strings.Contains(msg, "failover") && strings.Contains(msg, "completed")
+}
@@ -0,0 +1,177 @@
+package awsx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// envReference matches ${VAR} and ${VAR:-default} references in config files
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// FileConfig is the format of files read by LoadConfig. Durations are strings such as
+// "30s" or "5m". Providers lists the credential providers in chain order: static, env,
+// file, web_identity and instance; all of them are used if it is empty.
+type FileConfig struct {
+	Region           string            `json:"region" yaml:"region" toml:"region"`
+	Regions          []string          `json:"regions" yaml:"regions" toml:"regions"`
+	FallbackRegion   *string           `json:"fallback_region" yaml:"fallback_region" toml:"fallback_region"`
+	Profile          string            `json:"profile" yaml:"profile" toml:"profile"`
+	CredFile         string            `json:"cred_file" yaml:"cred_file" toml:"cred_file"`
+	AccessKey        string            `json:"access_key" yaml:"access_key" toml:"access_key"`
+	SecretKey        string            `json:"secret_key" yaml:"secret_key" toml:"secret_key"`
+	Providers        []string          `json:"providers" yaml:"providers" toml:"providers"`
+	Role             string            `json:"role" yaml:"role" toml:"role"`
+	ExternalID       string            `json:"external_id" yaml:"external_id" toml:"external_id"`
+	Endpoint         string            `json:"endpoint" yaml:"endpoint" toml:"endpoint"`
+	ServiceEndpoints map[string]string `json:"service_endpoints" yaml:"service_endpoints" toml:"service_endpoints"`
+	CacheTTL         string            `json:"cache_ttl" yaml:"cache_ttl" toml:"cache_ttl"`
+	MaxRetries       *int              `json:"max_retries" yaml:"max_retries" toml:"max_retries"`
+	MetadataTimeout  string            `json:"metadata_timeout" yaml:"metadata_timeout" toml:"metadata_timeout"`
+	HTTPTimeout      string            `json:"http_timeout" yaml:"http_timeout" toml:"http_timeout"`
+	Panic            bool              `json:"panic" yaml:"panic" toml:"panic"`
+}
+
+// LoadConfig builds a Config from a YAML (.yaml, .yml), JSON (.json) or TOML (.toml)
+// file. References to environment variables in the file, written ${VAR} or
+// ${VAR:-default}, are replaced before it is parsed.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fc, err := ParseConfig(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return fc.Config()
+}
+
+// ParseConfig parses config file data in the format of the file extension ext, after
+// expanding environment variable references
+func ParseConfig(data []byte, ext string) (*FileConfig, error) {
+	data = []byte(expandEnv(string(data)))
+
+	fc := &FileConfig{}
+	var err error
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, fc)
+	case "json":
+		err = json.Unmarshal(data, fc)
+	case "toml":
+		err = toml.Unmarshal(data, fc)
+	default:
+		return nil, errors.New("unsupported config file format " + ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fc, nil
+}
+
+// Config builds the Config described by the file
+func (fc *FileConfig) Config() (*Config, error) {
+	a := NewAWS()
+	if fc.Panic {
+		a.EnablePanic()
+	}
+
+	a.Region = fc.Region
+	a.Regions = fc.Regions
+	a.FallbackRegion = fc.FallbackRegion
+	a.Profile = fc.Profile
+	a.CredFile = fc.CredFile
+	a.AccessKey = fc.AccessKey
+	a.SecretKey = fc.SecretKey
+	a.Role = fc.Role
+	a.ExternalID = fc.ExternalID
+	a.Endpoint = fc.Endpoint
+	for svc, endpoint := range fc.ServiceEndpoints {
+		a.SetServiceEndpoint(svc, endpoint)
+	}
+	if fc.MaxRetries != nil {
+		a.SetMaxRetries(*fc.MaxRetries)
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		set   func(time.Duration)
+	}{
+		{"cache_ttl", fc.CacheTTL, func(d time.Duration) { a.SetCacheTTL(d) }},
+		{"metadata_timeout", fc.MetadataTimeout, func(d time.Duration) { a.SetMetadataOptions(MetadataOptions{Timeout: d}) }},
+		{"http_timeout", fc.HTTPTimeout, func(d time.Duration) { a.SetHTTPClient(&http.Client{Timeout: d}) }},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", d.name, err)
+		}
+		d.set(v)
+	}
+
+	if err := a.withProviderNames(fc.Providers); err != nil {
+		return nil, err
+	}
+	if a.Role != "" {
+		a.WithRole()
+	}
+
+	return a, nil
+}
+
+// withProviderNames adds the named credential providers to the chain in order, or all
+// providers if names is empty
+func (a *Config) withProviderNames(names []string) error {
+	if len(names) == 0 {
+		a.WithAllProviders()
+		return nil
+	}
+
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case "static":
+			a.WithStatic()
+		case "env":
+			a.WithEnv()
+		case "file":
+			a.WithFile()
+		case "web_identity":
+			a.WithWebIdentity()
+		case "instance":
+			a.WithInstanceRole()
+		default:
+			return errors.New("unknown credential provider " + name)
+		}
+	}
+
+	return nil
+}
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references with environment variables
+func expandEnv(s string) string {
+	return envReference.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envReference.FindStringSubmatch(ref)
+		if v, ok := os.LookupEnv(m[1]); ok && v != "" {
+			return v
+		}
+		return m[3]
+	})
+}
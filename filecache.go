@@ -0,0 +1,292 @@
+package awsx
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+const (
+	// defaultCredentialCacheWindow is how long before expiry cached credentials are refreshed
+	defaultCredentialCacheWindow = 5 * time.Minute
+	credentialCacheLockTimeout   = 10 * time.Second
+	credentialCacheLockStale     = 30 * time.Second
+	credentialCacheLockPoll      = 50 * time.Millisecond
+)
+
+// cliCacheEntry is the credential cache file format of the AWS CLI (~/.aws/cli/cache)
+type cliCacheEntry struct {
+	ProviderType string              `json:"ProviderType,omitempty"`
+	ProviderName string              `json:"ProviderName,omitempty"` // awsx only: the ProviderName of the cached credentials
+	Credentials  cliCacheCredentials `json:"Credentials"`
+}
+
+type cliCacheCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"SessionToken"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// expirer is implemented by providers that know when their credentials expire, such as
+// the stscreds and SSO providers
+type expirer interface {
+	ExpiresAt() time.Time
+}
+
+// FileCacheProvider caches the credentials of a provider with an expiry, such as an
+// assumed role or SSO provider, in a file using the AWS CLI cache format so short lived
+// processes share credentials instead of calling STS on every run. Writers take a lock
+// file so concurrent processes don't retrieve credentials at the same time.
+type FileCacheProvider struct {
+	Path         string
+	ExpiryWindow time.Duration // refresh this long before expiry, defaults to 5 minutes
+	Clock        Clock
+
+	provider     credentials.Provider
+	providerType string
+	path         func() (string, error) // resolves Path on every Retrieve when set
+
+	mu      sync.Mutex
+	expires time.Time
+}
+
+// NewFileCacheProvider returns a FileCacheProvider caching the credentials of provider
+// in the file at path
+func NewFileCacheProvider(provider credentials.Provider, path string) *FileCacheProvider {
+	return &FileCacheProvider{
+		Path:         path,
+		ExpiryWindow: defaultCredentialCacheWindow,
+		Clock:        RealClock,
+		provider:     provider,
+	}
+}
+
+// CredentialCachePath returns the path of the cache file for the key parts in dir, named
+// by the SHA-1 of the parts like the AWS CLI cache. An empty dir uses ~/.aws/cli/cache.
+func CredentialCachePath(dir string, parts ...string) string {
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".aws", "cli", "cache")
+	}
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// SetCredentialCache caches the credentials of roles assumed by WithRole, WithRoleChain
+// and WithRoleHops in dir, or ~/.aws/cli/cache if dir is empty. It must be called before
+// those methods.
+func (a *Config) SetCredentialCache(dir string) *Config {
+	a.credentialCacheDir = &dir
+	return a
+}
+
+// cacheCredentials wraps the provider in a FileCacheProvider when SetCredentialCache is set
+func (a *Config) cacheCredentials(p credentials.Provider, providerType string, key ...string) credentials.Provider {
+	return a.cacheCredentialsKey(p, providerType, func() ([]string, error) { return key, nil })
+}
+
+// cacheCredentialsKey is cacheCredentials with key parts that are only known when the
+// credentials are retrieved, such as the identity of the base credentials
+func (a *Config) cacheCredentialsKey(p credentials.Provider, providerType string, key func() ([]string, error)) credentials.Provider {
+	if a.credentialCacheDir == nil {
+		return p
+	}
+	dir := *a.credentialCacheDir
+	fc := NewFileCacheProvider(p, "")
+	fc.Clock = a.clock()
+	fc.providerType = providerType
+	fc.path = func() (string, error) {
+		parts, err := key()
+		if err != nil {
+			return "", err
+		}
+		return CredentialCachePath(dir, parts...), nil
+	}
+	return fc
+}
+
+// Retrieve returns the cached credentials if they are not about to expire, otherwise it
+// retrieves new credentials from the provider and writes them to the cache
+func (p *FileCacheProvider) Retrieve() (credentials.Value, error) {
+	if p.path != nil {
+		path, err := p.path()
+		if err != nil {
+			return credentials.Value{}, err
+		}
+		p.Path = path
+	}
+
+	if entry, ok := p.read(); ok {
+		return p.use(entry), nil
+	}
+
+	unlock, err := p.lock()
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	defer unlock()
+
+	// another process may have refreshed the cache while we waited for the lock
+	if entry, ok := p.read(); ok {
+		return p.use(entry), nil
+	}
+
+	v, err := p.provider.Retrieve()
+	if err != nil {
+		return v, err
+	}
+
+	e, ok := p.provider.(expirer)
+	if !ok || e.ExpiresAt().IsZero() {
+		return v, nil
+	}
+
+	entry := &cliCacheEntry{
+		ProviderType: p.providerType,
+		ProviderName: v.ProviderName,
+		Credentials: cliCacheCredentials{
+			AccessKeyID:     v.AccessKeyID,
+			SecretAccessKey: v.SecretAccessKey,
+			SessionToken:    v.SessionToken,
+			Expiration:      e.ExpiresAt().UTC(),
+		},
+	}
+	if err := p.write(entry); err != nil {
+		return v, err
+	}
+
+	p.mu.Lock()
+	p.expires = entry.Credentials.Expiration
+	p.mu.Unlock()
+
+	return v, nil
+}
+
+// IsExpired reports whether the credentials are within the expiry window
+func (p *FileCacheProvider) IsExpired() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.valid(p.expires)
+}
+
+// ExpiresAt returns the expiry time of the credentials last retrieved
+func (p *FileCacheProvider) ExpiresAt() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.expires
+}
+
+func (p *FileCacheProvider) valid(expires time.Time) bool {
+	return !expires.IsZero() && p.Clock.Now().Add(p.ExpiryWindow).Before(expires)
+}
+
+func (p *FileCacheProvider) use(entry *cliCacheEntry) credentials.Value {
+	p.mu.Lock()
+	p.expires = entry.Credentials.Expiration
+	p.mu.Unlock()
+
+	name := entry.ProviderName
+	if name == "" {
+		name = "FileCacheProvider"
+	}
+
+	return credentials.Value{
+		AccessKeyID:     entry.Credentials.AccessKeyID,
+		SecretAccessKey: entry.Credentials.SecretAccessKey,
+		SessionToken:    entry.Credentials.SessionToken,
+		ProviderName:    name,
+	}
+}
+
+// read returns the cache entry if it exists and is not about to expire
+func (p *FileCacheProvider) read() (*cliCacheEntry, bool) {
+	data, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return nil, false
+	}
+
+	entry := &cliCacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, false
+	}
+	if entry.Credentials.AccessKeyID == "" || !p.valid(entry.Credentials.Expiration) {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// write atomically replaces the cache file, readable only by the owner
+func (p *FileCacheProvider) write(entry *cliCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p.Path, data)
+}
+
+// writeFileAtomic replaces the file at path with data through a temporary file in the
+// same directory, so readers never see a partial write. The directory is created
+// readable only by the owner if it does not exist.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// lock creates the lock file next to the cache file, waiting for other processes to
+// release it. Lock files older than credentialCacheLockStale are left by crashed
+// processes and are removed.
+func (p *FileCacheProvider) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(p.Path), 0700); err != nil {
+		return nil, err
+	}
+
+	path := p.Path + ".lock"
+	deadline := p.Clock.Now().Add(credentialCacheLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && p.Clock.Now().Sub(info.ModTime()) > credentialCacheLockStale {
+			os.Remove(path)
+			continue
+		}
+		if p.Clock.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for credential cache lock " + path)
+		}
+		p.Clock.Sleep(credentialCacheLockPoll)
+	}
+}
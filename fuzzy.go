@@ -0,0 +1,92 @@
+package awsx
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// CandidatesError is returned in fuzzy name mode when a cluster name has no exact match
+// and matches more than one (or no) replication group by prefix or wildcard
+type CandidatesError struct {
+	Name       string
+	Candidates []string
+}
+
+// Error lists the candidate replication groups
+func (e *CandidatesError) Error() string {
+	if len(e.Candidates) == 0 {
+		return "no replication groups match " + e.Name
+	}
+	return "no exact match for " + e.Name + ", candidates: " + strings.Join(e.Candidates, ", ")
+}
+
+// EnableFuzzyNames makes Redis discovery fall back to prefix or wildcard matching of
+// replication group IDs when a cluster name has no exact match. A single match is used
+// as the cluster, several matches return a *CandidatesError listing them.
+func (a *Config) EnableFuzzyNames() *Config {
+	a.fuzzyNames = true
+	return a
+}
+
+// DisableFuzzyNames restores exact matching of cluster names
+func (a *Config) DisableFuzzyNames() *Config {
+	a.fuzzyNames = false
+	return a
+}
+
+// FindECReplicationGroups returns the IDs of replication groups matching the pattern.
+// Patterns containing *, ? or [ are matched as wildcards (e.g. "orders-*"), any other
+// pattern is matched as a prefix.
+func (a *Config) FindECReplicationGroups(pattern string) ([]string, error) {
+	return a.findECReplicationGroups(a.context(), pattern)
+}
+
+// findECReplicationGroups is FindECReplicationGroups with the context of the call
+func (a *Config) findECReplicationGroups(ctx context.Context, pattern string) ([]string, error) {
+	wildcard := strings.ContainsAny(pattern, "*?[")
+
+	ids := make([]string, 0)
+	var matchErr error
+	err := a.eachECReplicationGroup(ctx, func(rg *elasticache.ReplicationGroup) bool {
+		id := aws.StringValue(rg.ReplicationGroupId)
+		if wildcard {
+			ok, err := path.Match(pattern, id)
+			if err != nil {
+				matchErr = err
+				return false
+			}
+			if ok {
+				ids = append(ids, id)
+			}
+		} else if strings.HasPrefix(id, pattern) {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if matchErr != nil {
+		return nil, matchErr
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// resolveFuzzyName returns the single replication group matching name in fuzzy mode
+func (a *Config) resolveFuzzyName(ctx context.Context, name string) (string, error) {
+	ids, err := a.findECReplicationGroups(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) != 1 {
+		return "", &CandidatesError{Name: name, Candidates: ids}
+	}
+	return ids[0], nil
+}
@@ -0,0 +1,111 @@
+package awsx
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// AuroraGlobalCluster is an Aurora Global Database with the endpoints of its member
+// cluster in each region
+type AuroraGlobalCluster struct {
+	ID            string                `json:"id" yaml:"id"`
+	ARN           string                `json:"arn" yaml:"arn"`
+	Engine        string                `json:"engine" yaml:"engine"`
+	EngineVersion string                `json:"engine_version" yaml:"engine_version"`
+	Status        string                `json:"status" yaml:"status"`
+	WriterRegion  string                `json:"writer_region" yaml:"writer_region"`
+	Members       []*AuroraGlobalMember `json:"members" yaml:"members"`
+}
+
+// AuroraGlobalMember is the member cluster of a global database in one region. Err is
+// set instead of Endpoints when the regional discovery failed.
+type AuroraGlobalMember struct {
+	Region    string           `json:"region" yaml:"region"`
+	Cluster   string           `json:"cluster" yaml:"cluster"`
+	ARN       string           `json:"arn" yaml:"arn"`
+	Writer    bool             `json:"writer" yaml:"writer"`
+	Endpoints *AuroraEndpoints `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Err       error            `json:"-" yaml:"-"`
+}
+
+// Writer returns the member in the writer region, or nil if there is none, e.g. during a
+// global failover
+func (g *AuroraGlobalCluster) Writer() *AuroraGlobalMember {
+	for _, m := range g.Members {
+		if m.Writer {
+			return m
+		}
+	}
+	return nil
+}
+
+// Region returns the member in the region, or nil if the global database has no member
+// cluster there
+func (g *AuroraGlobalCluster) Region(region string) *AuroraGlobalMember {
+	for _, m := range g.Members {
+		if m.Region == region {
+			return m
+		}
+	}
+	return nil
+}
+
+// GetAuroraGlobalCluster describes an Aurora Global Database, flags the writer region
+// and discovers the endpoints of every member cluster in its own region with ForRegion,
+// so cross-region applications can route writes correctly after a global failover
+func (a *Config) GetAuroraGlobalCluster(globalID string) (*AuroraGlobalCluster, error) {
+	if globalID == "" {
+		return nil, errors.New("no global cluster identifier provided")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	result, err := a.Service.Rds.DescribeGlobalClusters(&rds.DescribeGlobalClustersInput{
+		GlobalClusterIdentifier: aws.String(globalID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.GlobalClusters) == 0 {
+		return nil, errors.New("no global cluster matching " + globalID)
+	}
+
+	gc := result.GlobalClusters[0]
+	g := &AuroraGlobalCluster{
+		ID:            aws.StringValue(gc.GlobalClusterIdentifier),
+		ARN:           aws.StringValue(gc.GlobalClusterArn),
+		Engine:        aws.StringValue(gc.Engine),
+		EngineVersion: aws.StringValue(gc.EngineVersion),
+		Status:        aws.StringValue(gc.Status),
+		Members:       make([]*AuroraGlobalMember, 0, len(gc.GlobalClusterMembers)),
+	}
+
+	for _, gm := range gc.GlobalClusterMembers {
+		m := &AuroraGlobalMember{
+			ARN:    aws.StringValue(gm.DBClusterArn),
+			Writer: aws.BoolValue(gm.IsWriter),
+		}
+		resource, err := arn.Parse(m.ARN)
+		if err != nil {
+			m.Err = err
+			g.Members = append(g.Members, m)
+			continue
+		}
+		m.Region = resource.Region
+		m.Cluster = strings.TrimPrefix(resource.Resource, "cluster:")
+		if m.Writer {
+			g.WriterRegion = m.Region
+		}
+
+		m.Endpoints, m.Err = a.ForRegion(m.Region).GetAuroraEndpoints(m.Cluster)
+		g.Members = append(g.Members, m)
+	}
+
+	return g, nil
+}
@@ -0,0 +1,221 @@
+package awsx
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	grafanaPanelWidth  = 8
+	grafanaPanelHeight = 8
+	grafanaPanelsInRow = 24 / grafanaPanelWidth
+)
+
+// elastiCacheDashboardMetrics are the AWS/ElastiCache metrics graphed for every cache cluster
+var elastiCacheDashboardMetrics = []string{
+	"EngineCPUUtilization",
+	"DatabaseMemoryUsagePercentage",
+	"CurrConnections",
+	"CacheHitRate",
+	"Evictions",
+	"ReplicationLag",
+}
+
+// rdsDashboardMetrics are the AWS/RDS metrics graphed for every DB cluster
+var rdsDashboardMetrics = []string{
+	"CPUUtilization",
+	"DatabaseConnections",
+	"FreeableMemory",
+	"AuroraReplicaLag",
+	"Deadlocks",
+	"CommitLatency",
+}
+
+// DashboardOptions configures the dashboard generated by GrafanaDashboard
+type DashboardOptions struct {
+	Title      string // optional: defaults to "awsx <region>"
+	UID        string // optional: stable dashboard UID so re-imports replace the dashboard
+	Datasource string // optional: UID of the CloudWatch datasource, defaults to the default datasource
+	Refresh    string // optional: dashboard refresh interval, defaults to 1m
+}
+
+// GrafanaDashboard is the subset of the Grafana dashboard JSON model written by awsx
+type GrafanaDashboard struct {
+	UID           string         `json:"uid,omitempty"`
+	Title         string         `json:"title"`
+	Tags          []string       `json:"tags"`
+	Timezone      string         `json:"timezone"`
+	Refresh       string         `json:"refresh"`
+	SchemaVersion int            `json:"schemaVersion"`
+	Time          GrafanaRange   `json:"time"`
+	Panels        []GrafanaPanel `json:"panels"`
+}
+
+// GrafanaRange is the default time range of a dashboard
+type GrafanaRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GrafanaPanel is a row or graph panel of a GrafanaDashboard
+type GrafanaPanel struct {
+	ID         int                `json:"id"`
+	Type       string             `json:"type"`
+	Title      string             `json:"title"`
+	GridPos    GrafanaGridPos     `json:"gridPos"`
+	Datasource *GrafanaDatasource `json:"datasource,omitempty"`
+	Targets    []GrafanaCWTarget  `json:"targets,omitempty"`
+}
+
+// GrafanaGridPos is the position of a panel on the dashboard grid
+type GrafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// GrafanaDatasource references the datasource of a panel
+type GrafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid,omitempty"`
+}
+
+// GrafanaCWTarget is a CloudWatch metrics query of a panel
+type GrafanaCWTarget struct {
+	RefID      string              `json:"refId"`
+	Region     string              `json:"region"`
+	Namespace  string              `json:"namespace"`
+	MetricName string              `json:"metricName"`
+	Dimensions map[string][]string `json:"dimensions"`
+	Statistic  string              `json:"statistic"`
+	Period     string              `json:"period"`
+	MatchExact bool                `json:"matchExact"`
+	QueryMode  string              `json:"queryMode"`
+	Label      string              `json:"label,omitempty"`
+}
+
+// GrafanaDashboard sweeps the ElastiCache replication groups and RDS DB clusters of the
+// region and returns a dashboard with a row per cluster holding panels of its key
+// CloudWatch metrics, so that new clusters get a dashboard from the inventory alone
+func (a *Config) GrafanaDashboard(opts *DashboardOptions) (*GrafanaDashboard, error) {
+	if opts == nil {
+		opts = &DashboardOptions{}
+	}
+
+	groups, err := a.ListECReplicationGroups()
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := a.ListRDSDBClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	region := a.GetRegion()
+	d := &GrafanaDashboard{
+		UID:           opts.UID,
+		Title:         opts.Title,
+		Tags:          []string{"awsx"},
+		Timezone:      "browser",
+		Refresh:       opts.Refresh,
+		SchemaVersion: 36,
+		Time:          GrafanaRange{From: "now-6h", To: "now"},
+		Panels:        make([]GrafanaPanel, 0),
+	}
+	if d.Title == "" {
+		d.Title = "awsx " + region
+	}
+	if d.Refresh == "" {
+		d.Refresh = "1m"
+	}
+
+	b := &dashboardBuilder{dashboard: d, region: region, datasource: &GrafanaDatasource{Type: "cloudwatch", UID: opts.Datasource}}
+	for _, rg := range groups {
+		b.addRow("ElastiCache "+aws.StringValue(rg.ReplicationGroupId), "AWS/ElastiCache", "CacheClusterId",
+			aws.StringValueSlice(rg.MemberClusters), elastiCacheDashboardMetrics)
+	}
+	for _, c := range clusters {
+		b.addRow("RDS "+aws.StringValue(c.DBClusterIdentifier), "AWS/RDS", "DBClusterIdentifier",
+			[]string{aws.StringValue(c.DBClusterIdentifier)}, rdsDashboardMetrics)
+	}
+
+	return d, nil
+}
+
+// GrafanaDashboardJSON returns the dashboard of GrafanaDashboard as JSON ready to import
+// into Grafana or to post to its dashboard API
+func (a *Config) GrafanaDashboardJSON(opts *DashboardOptions) ([]byte, error) {
+	d, err := a.GrafanaDashboard(opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// dashboardBuilder lays out panels on the dashboard grid
+type dashboardBuilder struct {
+	dashboard  *GrafanaDashboard
+	region     string
+	datasource *GrafanaDatasource
+	id         int
+	y          int
+}
+
+// addRow adds a row titled title with a panel per metric, each graphing the metric for
+// every value of the dimension
+func (b *dashboardBuilder) addRow(title, namespace, dimension string, values []string, metrics []string) {
+	b.id++
+	b.dashboard.Panels = append(b.dashboard.Panels, GrafanaPanel{
+		ID:      b.id,
+		Type:    "row",
+		Title:   title,
+		GridPos: GrafanaGridPos{H: 1, W: 24, X: 0, Y: b.y},
+	})
+	b.y++
+
+	for i, metric := range metrics {
+		targets := make([]GrafanaCWTarget, 0, len(values))
+		for j, v := range values {
+			targets = append(targets, GrafanaCWTarget{
+				RefID:      refID(j),
+				Region:     b.region,
+				Namespace:  namespace,
+				MetricName: metric,
+				Dimensions: map[string][]string{dimension: {v}},
+				Statistic:  "Average",
+				Period:     "60",
+				MatchExact: true,
+				QueryMode:  "Metrics",
+				Label:      v,
+			})
+		}
+
+		b.id++
+		b.dashboard.Panels = append(b.dashboard.Panels, GrafanaPanel{
+			ID:    b.id,
+			Type:  "timeseries",
+			Title: metric,
+			GridPos: GrafanaGridPos{
+				H: grafanaPanelHeight,
+				W: grafanaPanelWidth,
+				X: (i % grafanaPanelsInRow) * grafanaPanelWidth,
+				Y: b.y + (i/grafanaPanelsInRow)*grafanaPanelHeight,
+			},
+			Datasource: b.datasource,
+			Targets:    targets,
+		})
+	}
+
+	b.y += ((len(metrics) + grafanaPanelsInRow - 1) / grafanaPanelsInRow) * grafanaPanelHeight
+}
+
+// refID returns the Grafana query reference ID for the i-th target: A, B, ..., Z, AA, AB
+func refID(i int) string {
+	id := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		id = string(rune('A'+(i-1)%26)) + id
+	}
+	return id
+}
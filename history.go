@@ -0,0 +1,286 @@
+package awsx
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TopologyChange is an entry of the TopologyHistory: the primary and members of a
+// cluster after discovery found them changed, or the error when discovery started failing
+type TopologyChange struct {
+	Seq             uint64    `json:"seq" yaml:"seq"`
+	Time            time.Time `json:"time" yaml:"time"`
+	Kind            string    `json:"kind" yaml:"kind"` // redis, aurora or cloudmap
+	Cluster         string    `json:"cluster" yaml:"cluster"`
+	Primary         string    `json:"primary,omitempty" yaml:"primary,omitempty"`                   // primary node or writer instance
+	PreviousPrimary string    `json:"previous_primary,omitempty" yaml:"previous_primary,omitempty"` // set when the primary changed
+	Members         []string  `json:"members,omitempty" yaml:"members,omitempty"`                   // replica nodes or reader instances
+	Added           []string  `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed         []string  `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Err             string    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// HistoryQuery selects changes from the TopologyHistory. Empty fields match everything.
+type HistoryQuery struct {
+	Kind    string
+	Cluster string
+	Since   time.Time
+	Until   time.Time
+}
+
+// topologyHistoryVersion is the version of the topology history file format
+const topologyHistoryVersion = 1
+
+// TopologyHistory is a bounded log of the topology changes seen by discovery, oldest
+// first, to reconstruct who was primary when after a failover. It is kept in memory,
+// and in a state file as well when set with SetTopologyHistoryStore.
+type TopologyHistory struct {
+	size   int
+	path   string // state file, empty when the history is in memory only
+	format StateFormat
+
+	mu      sync.Mutex
+	changes []TopologyChange
+	seq     uint64
+	last    map[string]*TopologyChange // latest change per kind and cluster
+}
+
+// SetTopologyHistory keeps the last size topology changes found by the discovery
+// functions and watchers, see TopologyHistory. A zero size disables the history.
+func (a *Config) SetTopologyHistory(size int) *Config {
+	if size <= 0 {
+		a.history = nil
+		return a
+	}
+	a.history = &TopologyHistory{size: size, last: map[string]*TopologyChange{}}
+	return a
+}
+
+// topologyHistoryFile is the file format of a persisted TopologyHistory
+type topologyHistoryFile struct {
+	Version int              `json:"version"`
+	Seq     uint64           `json:"seq"`
+	Changes []TopologyChange `json:"changes"`
+}
+
+// SetTopologyHistoryStore is SetTopologyHistory with the history persisted to a state
+// file at path, encoded with EncodeState in the format like the endpoint store, so it
+// survives restarts. The changes already in the file are loaded, and the file is
+// rewritten after every change.
+func (a *Config) SetTopologyHistoryStore(size int, path string, f StateFormat) *Config {
+	if size <= 0 || path == "" {
+		a.warn("No size or path specified in call to SetTopologyHistoryStore(size int, path string, f StateFormat)")
+		a.history = nil
+		return a
+	}
+	if f.Codec == nil {
+		f.Codec = JSONCodec{}
+	}
+	h := &TopologyHistory{size: size, path: path, format: f, last: map[string]*TopologyChange{}}
+
+	file := &topologyHistoryFile{}
+	if err := ReadStateFile(path, file); err == nil && file.Version == topologyHistoryVersion {
+		h.seq = file.Seq
+		h.changes = file.Changes
+		if len(h.changes) > size {
+			h.changes = h.changes[len(h.changes)-size:]
+		}
+		for i := range h.changes {
+			c := &h.changes[i]
+			h.last[c.Kind+":"+c.Cluster] = c
+		}
+	}
+
+	a.history = h
+	return a
+}
+
+// TopologyHistory returns the history of topology changes, or nil if SetTopologyHistory
+// was not called
+func (a *Config) TopologyHistory() *TopologyHistory {
+	return a.history
+}
+
+// Changes returns the changes matching the query, oldest first
+func (h *TopologyHistory) Changes(q HistoryQuery) []TopologyChange {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	changes := make([]TopologyChange, 0)
+	for _, c := range h.changes {
+		if q.Kind != "" && c.Kind != q.Kind || q.Cluster != "" && c.Cluster != q.Cluster {
+			continue
+		}
+		if !q.Since.IsZero() && c.Time.Before(q.Since) || !q.Until.IsZero() && c.Time.After(q.Until) {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// PrimaryAt returns the primary of the cluster at the given time according to the
+// history, or an empty string if the history doesn't reach back that far
+func (h *TopologyHistory) PrimaryAt(cluster string, t time.Time) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	primary := ""
+	for _, c := range h.changes {
+		if c.Time.After(t) {
+			break
+		}
+		if c.Cluster == cluster && c.Err == "" {
+			primary = c.Primary
+		}
+	}
+	return primary
+}
+
+// WriteJSON writes every change in the history to w as a JSON array
+func (h *TopologyHistory) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h.Changes(HistoryQuery{}))
+}
+
+// record adds a change to the history if the discovery result of the cluster differs
+// from the last one recorded, and writes a persisted history to its file. Only Redis and
+// Aurora endpoints are recorded.
+func (h *TopologyHistory) record(kind, cluster string, value interface{}, err error, now time.Time) error {
+	change := TopologyChange{Time: now, Kind: kind, Cluster: cluster}
+	if err != nil {
+		change.Err = err.Error()
+	} else {
+		switch v := value.(type) {
+		case *RedisEndpoints:
+			change.Primary, change.Members = redisTopology(v)
+		case *AuroraEndpoints:
+			change.Primary, change.Members = auroraTopology(v)
+		default:
+			return nil
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := kind + ":" + cluster
+	last, ok := h.last[key]
+	if ok && last.Err == change.Err && last.Primary == change.Primary && equalStrings(last.Members, change.Members) {
+		return nil
+	}
+	if ok && change.Err == "" {
+		if last.Primary != "" && last.Primary != change.Primary {
+			change.PreviousPrimary = last.Primary
+		}
+		if last.Err == "" {
+			change.Added, change.Removed = diffStrings(last.Members, change.Members)
+		}
+	}
+	if ok && change.Err != "" {
+		// keep the topology of the last success so a recovery is diffed against it
+		change.Primary, change.Members = last.Primary, last.Members
+	}
+
+	h.seq++
+	change.Seq = h.seq
+	h.changes = append(h.changes, change)
+	if len(h.changes) > h.size {
+		h.changes = h.changes[len(h.changes)-h.size:]
+	}
+	h.last[key] = &change
+
+	if h.path == "" {
+		return nil
+	}
+	file := &topologyHistoryFile{Version: topologyHistoryVersion, Seq: h.seq, Changes: h.changes}
+	return WriteStateFile(h.path, file, h.format)
+}
+
+// redisTopology returns the primary node and the replica nodes of the endpoints
+func redisTopology(res *RedisEndpoints) (string, []string) {
+	primary := ""
+	if res.ClusterEnabled && res.ClusterConfig != nil {
+		primary = res.ClusterConfig.String()
+	} else if res.Primary != nil {
+		primary = res.PrimaryString()
+	}
+
+	members := make([]string, 0, len(res.ReadEndpoints))
+	for _, e := range res.ReadEndpoints {
+		node := e.CacheClusterID
+		if node == "" {
+			node = e.String()
+		}
+		if e.Role == RolePrimary {
+			primary = node
+			continue
+		}
+		members = append(members, node)
+	}
+	sort.Strings(members)
+
+	return primary, members
+}
+
+// auroraTopology returns the writer instance and the reader instances of the endpoints
+func auroraTopology(aes *AuroraEndpoints) (string, []string) {
+	primary := ""
+	if aes.WriterInstance != nil {
+		primary = aes.WriterInstance.Instance
+		if primary == "" {
+			primary = aes.WriterInstance.String()
+		}
+	} else if aes.Writer != nil {
+		primary = aes.WriterString()
+	}
+
+	members := make([]string, 0, len(aes.ReadEndpoints))
+	for _, e := range aes.ReadEndpoints {
+		if e.Instance != "" {
+			members = append(members, e.Instance)
+		} else {
+			members = append(members, e.String())
+		}
+	}
+	sort.Strings(members)
+
+	return primary, members
+}
+
+// diffStrings returns the values of the sorted slice b missing from a, and of a missing from b
+func diffStrings(a, b []string) ([]string, []string) {
+	in := func(list []string, v string) bool {
+		i := sort.SearchStrings(list, v)
+		return i < len(list) && list[i] == v
+	}
+
+	var added, removed []string
+	for _, v := range b {
+		if !in(a, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range a {
+		if !in(b, v) {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package awsx
+
+import (
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// RequestHook is called for AWS requests made through the session. The request's
+// ClientInfo and Operation identify the call.
+type RequestHook func(r *request.Request)
+
+// requestHooks holds the hooks and handler customizations registered on a Config
+type requestHooks struct {
+	before    []RequestHook
+	after     []RequestHook
+	configure []func(h *request.Handlers)
+}
+
+// OnBeforeRequest registers a hook run for every attempt after the HTTP request is built
+// and before it is signed, so headers added by the hook are signed too. Setting r.Error
+// fails the request, which is useful for chaos testing.
+func (a *Config) OnBeforeRequest(fn RequestHook) *Config {
+	a.reqHooks().before = append(a.reqHooks().before, fn)
+	return a
+}
+
+// OnAfterRequest registers a hook run once per operation after all retries, with
+// r.Error set if the operation failed, e.g. for audit logging
+func (a *Config) OnAfterRequest(fn RequestHook) *Config {
+	a.reqHooks().after = append(a.reqHooks().after, fn)
+	return a
+}
+
+// ConfigureHandlers registers a func that can add, remove or replace SDK handlers of
+// the session directly, for changes the hooks don't cover such as signing tweaks
+func (a *Config) ConfigureHandlers(fn func(h *request.Handlers)) *Config {
+	a.reqHooks().configure = append(a.reqHooks().configure, fn)
+	return a
+}
+
+func (a *Config) reqHooks() *requestHooks {
+	if a.requestHooks == nil {
+		a.requestHooks = &requestHooks{}
+	}
+	return a.requestHooks
+}
+
+// installRequestHooks adds the registered hooks to a new session
+func (a *Config) installRequestHooks(sess *session.Session) {
+	if a.requestHooks == nil {
+		return
+	}
+
+	before := append([]RequestHook(nil), a.requestHooks.before...)
+	if len(before) > 0 {
+		sess.Handlers.Sign.PushFrontNamed(request.NamedHandler{Name: "awsx.hooks.before", Fn: func(r *request.Request) {
+			for _, fn := range before {
+				fn(r)
+			}
+		}})
+	}
+
+	after := append([]RequestHook(nil), a.requestHooks.after...)
+	if len(after) > 0 {
+		sess.Handlers.Complete.PushBackNamed(request.NamedHandler{Name: "awsx.hooks.after", Fn: func(r *request.Request) {
+			for _, fn := range after {
+				fn(r)
+			}
+		}})
+	}
+
+	for _, fn := range a.requestHooks.configure {
+		fn(&sess.Handlers)
+	}
+}
@@ -0,0 +1,22 @@
+package awsx
+
+// RedisResolver discovers the endpoints of ElastiCache Redis clusters. It is satisfied
+// by *Config and lets consumers depend on discovery without a concrete Config; see the
+// mocks package for test implementations.
+type RedisResolver interface {
+	GetRedisAllEndpoints(cluster string) (*RedisEndpoints, error)
+	GetRedisPrimaryEndpoint(cluster string) (*RedisEndpoints, error)
+	GetRedisClusterEndpoint(cluster string) (*RedisEndpoint, error)
+}
+
+// RedisProvisioner creates and exports ElastiCache Redis resources from snapshots.
+// It is satisfied by *Config.
+type RedisProvisioner interface {
+	SpinUpFromSnapshot(snapshot string, opts *RestoreOptions) (*EphemeralCache, error)
+	ExportRedisSnapshotToS3(snapshotName, bucket string, progress ...SnapshotProgressFunc) (string, error)
+}
+
+var (
+	_ RedisResolver    = (*Config)(nil)
+	_ RedisProvisioner = (*Config)(nil)
+)
@@ -0,0 +1,139 @@
+package awsx
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// keyspaceEventsParameter is the ElastiCache parameter controlling keyspace notifications
+const keyspaceEventsParameter = "notify-keyspace-events"
+
+// keyspaceAllAlias is what the "A" flag of notify-keyspace-events expands to
+const keyspaceAllAlias = "g$lshzxetd"
+
+// ConfigGetFunc returns the live value of a Redis configuration parameter, typically
+// by issuing CONFIG GET through the client library the application already uses
+type ConfigGetFunc func(parameter string) (string, error)
+
+// VerifyKeyspaceNotifications checks that the notify-keyspace-events parameter in the
+// parameter group used by the cluster includes every flag in requiredFlags (e.g. "Ex").
+// If a ConfigGetFunc is provided, the live value returned by it is checked as well since
+// CONFIG SET changes are not reflected in the parameter group.
+func (a *Config) VerifyKeyspaceNotifications(cluster, requiredFlags string, live ...ConfigGetFunc) error {
+	if cluster == "" {
+		return errors.New("no cluster name provided")
+	}
+	if requiredFlags == "" {
+		return errors.New("no keyspace notification flags provided")
+	}
+
+	group, err := a.GetECParameterGroupName(cluster)
+	if err != nil {
+		return err
+	}
+
+	value, err := a.GetECParameterValue(group, keyspaceEventsParameter)
+	if err != nil {
+		return err
+	}
+
+	if missing := missingKeyspaceFlags(value, requiredFlags); missing != "" {
+		return errors.New("parameter group " + group + " is missing notify-keyspace-events flags: " + missing)
+	}
+
+	for _, get := range live {
+		if get == nil {
+			continue
+		}
+		value, err := get(keyspaceEventsParameter)
+		if err != nil {
+			return err
+		}
+		if missing := missingKeyspaceFlags(value, requiredFlags); missing != "" {
+			return errors.New("live configuration is missing notify-keyspace-events flags: " + missing)
+		}
+	}
+
+	return nil
+}
+
+// GetECParameterGroupName returns the cache parameter group name used by the cluster.
+// For replication groups, the parameter group of the first member cluster is returned.
+func (a *Config) GetECParameterGroupName(cluster string) (string, error) {
+	if cluster == "" {
+		return "", errors.New("no cluster name provided")
+	}
+
+	cacheCluster := cluster
+	result, count, err := a.GetECReplicationGroupE(cluster)
+	if err != nil {
+		return "", err
+	}
+	if count == 1 && len(result.ReplicationGroups[0].MemberClusters) > 0 {
+		cacheCluster = *result.ReplicationGroups[0].MemberClusters[0]
+	}
+
+	list, err := a.GetECClusterDetails(cacheCluster)
+	if err != nil {
+		return "", err
+	}
+	if len(list.CacheClusters) == 0 || list.CacheClusters[0].CacheParameterGroup == nil {
+		return "", errors.New("no cache parameter group associated with this cluster name")
+	}
+
+	return aws.StringValue(list.CacheClusters[0].CacheParameterGroup.CacheParameterGroupName), nil
+}
+
+// GetECParameterValue returns the value of a single parameter from a cache parameter group
+func (a *Config) GetECParameterValue(group, name string) (string, error) {
+	if group == "" || name == "" {
+		return "", errors.New("must provide a parameter group and parameter name")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeCacheParametersInput{
+		CacheParameterGroupName: aws.String(group),
+	}
+
+	var value string
+	found := false
+	err := a.Service.Ec.DescribeCacheParametersPages(input, func(page *elasticache.DescribeCacheParametersOutput, lastPage bool) bool {
+		for _, p := range page.Parameters {
+			if aws.StringValue(p.ParameterName) == name {
+				value = aws.StringValue(p.ParameterValue)
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", errors.New("parameter " + name + " not found in parameter group " + group)
+	}
+
+	return value, nil
+}
+
+// missingKeyspaceFlags returns the flags in required that are not enabled by configured
+func missingKeyspaceFlags(configured, required string) string {
+	configured = strings.Replace(configured, "A", keyspaceAllAlias, -1)
+	required = strings.Replace(required, "A", keyspaceAllAlias, -1)
+
+	missing := ""
+	for _, f := range required {
+		if !strings.ContainsRune(configured, f) && !strings.ContainsRune(missing, f) {
+			missing += string(f)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,117 @@
+package awsx
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// KeyspacesPort is the TLS port of the Amazon Keyspaces endpoints
+	KeyspacesPort = "9142"
+
+	keyspacesService    = "cassandra"
+	keyspacesTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// KeyspacesInitialResponse is the response a SigV4 authenticator sends to start the
+// Keyspaces authentication exchange
+var KeyspacesInitialResponse = []byte("SigV4\000\000")
+
+// KeyspacesHost returns the regional Amazon Keyspaces host, e.g.
+// cassandra.us-east-1.amazonaws.com
+func (a *Config) KeyspacesHost() string {
+	return keyspacesService + "." + a.GetRegion() + "." + a.DNSSuffix()
+}
+
+// KeyspacesEndpoint returns host:port of the regional Amazon Keyspaces endpoint
+func (a *Config) KeyspacesEndpoint() string {
+	return a.KeyspacesHost() + ":" + KeyspacesPort
+}
+
+// KeyspacesTLSConfig returns the TLS config Keyspaces requires, verifying the endpoint
+// against the system roots, which include the Amazon Trust Services CAs Keyspaces uses
+func (a *Config) KeyspacesTLSConfig() *tls.Config {
+	return &tls.Config{ServerName: a.KeyspacesHost(), MinVersion: tls.VersionTLS12}
+}
+
+// KeyspacesChallengeResponse answers the SigV4 challenge of Keyspaces, which carries a
+// nonce, with a signature made with the credential chain of the Config. It implements
+// the exchange of the AWS SigV4 authentication plugins for Cassandra drivers, see the
+// awsxcql package for a gocql authenticator.
+func (a *Config) KeyspacesChallengeResponse(challenge []byte) ([]byte, error) {
+	nonce, err := keyspacesNonce(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Session == nil {
+		a.SetSession()
+	}
+	if a.Session == nil {
+		return nil, errors.New("no session to sign the keyspaces challenge with")
+	}
+	creds, err := a.Session.Config.Credentials.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := keyspacesSignature(a.GetRegion(), nonce, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, a.clock().Now())
+	return []byte(resp), nil
+}
+
+// keyspacesNonce extracts the nonce of a challenge of the form nonce=<nonce>[,...]
+func keyspacesNonce(challenge []byte) (string, error) {
+	s := string(challenge)
+	i := strings.Index(s, "nonce=")
+	if i < 0 {
+		return "", errors.New("no nonce in keyspaces challenge")
+	}
+	nonce := s[i+len("nonce="):]
+	if j := strings.IndexByte(nonce, ','); j >= 0 {
+		nonce = nonce[:j]
+	}
+	return nonce, nil
+}
+
+// keyspacesSignature signs the nonce as a presigned PUT /authenticate request to the
+// cassandra service
+func keyspacesSignature(region, nonce, accessKey, secretKey, sessionToken string, t time.Time) string {
+	t = t.UTC()
+	date := t.Format("20060102")
+	amzDate := t.Format(keyspacesTimeFormat)
+	scope := date + "/" + region + "/" + keyspacesService + "/aws4_request"
+
+	nonceHash := sha256.Sum256([]byte(nonce))
+	query := "X-Amz-Algorithm=AWS4-HMAC-SHA256" +
+		"&X-Amz-Credential=" + accessKey + "%2F" + url.QueryEscape(scope) +
+		"&X-Amz-Date=" + url.QueryEscape(amzDate) +
+		"&X-Amz-Expires=900"
+	canonical := "PUT\n/authenticate\n" + query + "\nhost:" + keyspacesService + "\n\nhost\n" + hex.EncodeToString(nonceHash[:])
+
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, keyspacesService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	resp := "signature=" + signature + ",access_key=" + accessKey + ",amzdate=" + amzDate
+	if sessionToken != "" {
+		resp += ",session_token=" + sessionToken
+	}
+	return resp
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,379 @@
+package awsx
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+)
+
+const (
+	// maxKinesisBatchRecords is the maximum number of records of a PutRecords call
+	maxKinesisBatchRecords = 500
+	// maxKinesisBatchBytes is the maximum payload of a PutRecords call
+	maxKinesisBatchBytes = 5 << 20
+	// maxKinesisRecordBytes is the maximum size of a record, including its partition key
+	maxKinesisRecordBytes = 1 << 20
+
+	defaultKinesisFlushInterval = time.Second
+	defaultKinesisMaxRetries    = 5
+	kinesisMinBackoff           = 100 * time.Millisecond
+	kinesisMaxBackoff           = 5 * time.Second
+)
+
+// kplMagic prefixes records aggregated in the Kinesis Producer Library format
+var kplMagic = []byte{0xF3, 0x89, 0x9A, 0xC2}
+
+// GetKinesisClient returns a client for use with Amazon Kinesis Data Streams
+func (a *Config) GetKinesisClient() kinesisiface.KinesisAPI {
+	return a.Service.Kinesis
+}
+
+// SetKinesisClient sets a client for use with Amazon Kinesis Data Streams
+func (a *Config) SetKinesisClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Kinesis = kinesis.New(a.ClientConfig(kinesis.EndpointsID))
+
+	return a
+}
+
+// WithKinesisClient sets the client used for Amazon Kinesis Data Streams calls, such as
+// a mock from the awsxmock package
+func (a *Config) WithKinesisClient(client kinesisiface.KinesisAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Kinesis = client
+
+	return a
+}
+
+// KinesisStream is a Kinesis data stream returned by GetKinesisStream
+type KinesisStream struct {
+	Name            string        `json:"name" yaml:"name"`
+	ARN             string        `json:"arn" yaml:"arn"`
+	Status          string        `json:"status" yaml:"status"`
+	Mode            string        `json:"mode" yaml:"mode"` // PROVISIONED or ON_DEMAND
+	ShardCount      int64         `json:"shard_count" yaml:"shard_count"`
+	ConsumerCount   int64         `json:"consumer_count" yaml:"consumer_count"`
+	RetentionPeriod time.Duration `json:"retention_period" yaml:"retention_period"`
+	EncryptionType  string        `json:"encryption_type" yaml:"encryption_type"`
+	KeyID           string        `json:"key_id,omitempty" yaml:"key_id,omitempty"`
+}
+
+// GetKinesisStream returns the ARN, open shard count and settings of the stream
+func (a *Config) GetKinesisStream(name string) (*KinesisStream, error) {
+	if name == "" {
+		return nil, errors.New("no stream name provided")
+	}
+
+	if a.Service.Kinesis == nil {
+		a.SetKinesisClient()
+	}
+
+	out, err := a.Service.Kinesis.DescribeStreamSummary(&kinesis.DescribeStreamSummaryInput{StreamName: aws.String(name)})
+	if err != nil {
+		return nil, err
+	}
+	d := out.StreamDescriptionSummary
+	if d == nil {
+		return nil, errors.New("no description returned for stream " + name)
+	}
+
+	stream := &KinesisStream{
+		Name:            aws.StringValue(d.StreamName),
+		ARN:             aws.StringValue(d.StreamARN),
+		Status:          aws.StringValue(d.StreamStatus),
+		Mode:            kinesis.StreamModeProvisioned,
+		ShardCount:      aws.Int64Value(d.OpenShardCount),
+		ConsumerCount:   aws.Int64Value(d.ConsumerCount),
+		RetentionPeriod: time.Duration(aws.Int64Value(d.RetentionPeriodHours)) * time.Hour,
+		EncryptionType:  aws.StringValue(d.EncryptionType),
+		KeyID:           aws.StringValue(d.KeyId),
+	}
+	if d.StreamModeDetails != nil {
+		stream.Mode = aws.StringValue(d.StreamModeDetails.StreamMode)
+	}
+
+	return stream, nil
+}
+
+// KinesisProducerOptions configures a KinesisProducer
+type KinesisProducerOptions struct {
+	FlushInterval time.Duration // optional: how often Start flushes buffered records, defaults to 1 second
+	MaxRetries    int           // optional: retries of records rejected by PutRecords, defaults to 5
+	// Aggregate packs records into Kinesis Producer Library aggregated records, which
+	// consumers must deaggregate, as the KCL does. Aggregated records are routed by the
+	// partition key of their first record.
+	Aggregate bool
+	OnError   func(err error) // optional: receives errors of background flushes
+}
+
+// KinesisProducer buffers records and sends them to a stream with batched PutRecords
+// calls, retrying records rejected due to throttling with exponential backoff
+type KinesisProducer struct {
+	config *Config
+	stream string
+	opts   KinesisProducerOptions
+
+	mu      sync.Mutex
+	pending []*kinesis.PutRecordsRequestEntry
+	agg     *kplAggregator
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewKinesisProducer returns a producer for the stream. Records are sent by Flush, or
+// periodically after Start.
+func (a *Config) NewKinesisProducer(stream string, opts *KinesisProducerOptions) *KinesisProducer {
+	p := &KinesisProducer{config: a, stream: stream}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.FlushInterval <= 0 {
+		p.opts.FlushInterval = defaultKinesisFlushInterval
+	}
+	if p.opts.MaxRetries <= 0 {
+		p.opts.MaxRetries = defaultKinesisMaxRetries
+	}
+	return p
+}
+
+// Put buffers a record. Full batches are sent right away.
+func (p *KinesisProducer) Put(ctx context.Context, partitionKey string, data []byte) error {
+	if partitionKey == "" {
+		return errors.New("no partition key provided")
+	}
+	if len(partitionKey)+len(data) > maxKinesisRecordBytes {
+		return errors.New("record of " + strconv.Itoa(len(data)) + " bytes exceeds the Kinesis record size limit")
+	}
+
+	p.mu.Lock()
+	if p.opts.Aggregate {
+		if p.agg != nil && !p.agg.fits(partitionKey, data) {
+			p.pending = append(p.pending, p.agg.entry())
+			p.agg = nil
+		}
+		if p.agg == nil {
+			p.agg = &kplAggregator{}
+		}
+		p.agg.add(partitionKey, data)
+	} else {
+		p.pending = append(p.pending, &kinesis.PutRecordsRequestEntry{PartitionKey: aws.String(partitionKey), Data: data})
+	}
+	full := len(p.pending) >= maxKinesisBatchRecords
+	p.mu.Unlock()
+
+	if full {
+		return p.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush sends every buffered record, returning an error if records were still rejected
+// after MaxRetries. Records not sent when an error is returned are buffered again, ahead
+// of records put since, and are sent by the next Flush.
+func (p *KinesisProducer) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	if p.agg != nil {
+		p.pending = append(p.pending, p.agg.entry())
+		p.agg = nil
+	}
+	pending := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+
+	for len(pending) > 0 {
+		n, size := 0, 0
+		for n < len(pending) && n < maxKinesisBatchRecords {
+			size += len(pending[n].Data) + len(aws.StringValue(pending[n].PartitionKey))
+			if size > maxKinesisBatchBytes && n > 0 {
+				break
+			}
+			n++
+		}
+		if unsent, err := p.putRecords(ctx, pending[:n]); err != nil {
+			p.requeue(append(unsent, pending[n:]...))
+			return err
+		}
+		pending = pending[n:]
+	}
+
+	return nil
+}
+
+// requeue buffers records that could not be sent ahead of the records put since
+func (p *KinesisProducer) requeue(records []*kinesis.PutRecordsRequestEntry) {
+	p.mu.Lock()
+	p.pending = append(records, p.pending...)
+	p.mu.Unlock()
+}
+
+// putRecords sends a batch, resending rejected records with exponential backoff. On error
+// it returns the records that were not accepted by the stream.
+func (p *KinesisProducer) putRecords(ctx context.Context, records []*kinesis.PutRecordsRequestEntry) ([]*kinesis.PutRecordsRequestEntry, error) {
+	a := p.config
+	if a.Service.Kinesis == nil {
+		a.SetKinesisClient()
+	}
+
+	backoff := kinesisMinBackoff
+	for attempt := 0; ; attempt++ {
+		out, err := a.Service.Kinesis.PutRecordsWithContext(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(p.stream),
+			Records:    records,
+		})
+		if err != nil {
+			return records, err
+		}
+		if aws.Int64Value(out.FailedRecordCount) == 0 {
+			return nil, nil
+		}
+
+		failed := make([]*kinesis.PutRecordsRequestEntry, 0, aws.Int64Value(out.FailedRecordCount))
+		var lastErr string
+		for i, r := range out.Records {
+			if r.ErrorCode != nil && i < len(records) {
+				failed = append(failed, records[i])
+				lastErr = aws.StringValue(r.ErrorCode) + ": " + aws.StringValue(r.ErrorMessage)
+			}
+		}
+		if attempt >= p.opts.MaxRetries {
+			return failed, errors.New(strconv.Itoa(len(failed)) + " records rejected by stream " + p.stream + ", last error " + lastErr)
+		}
+
+		records = failed
+		if !a.wait(backoff, ctx.Done()) {
+			return records, ctx.Err()
+		}
+		if backoff *= 2; backoff > kinesisMaxBackoff {
+			backoff = kinesisMaxBackoff
+		}
+	}
+}
+
+// Start flushes buffered records every FlushInterval in a background goroutine
+func (p *KinesisProducer) Start() *KinesisProducer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return p
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.loop(p.stop, p.done)
+
+	return p
+}
+
+// Close stops the background flushes and sends the remaining records
+func (p *KinesisProducer) Close(ctx context.Context) error {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	return p.Flush(ctx)
+}
+
+func (p *KinesisProducer) loop(stop, done chan struct{}) {
+	defer close(done)
+
+	for p.config.wait(p.opts.FlushInterval, stop) {
+		if err := p.Flush(context.Background()); err != nil && p.opts.OnError != nil {
+			p.opts.OnError(err)
+		}
+	}
+}
+
+// kplAggregator builds a record in the Kinesis Producer Library aggregation format: the
+// magic bytes, an AggregatedRecord protobuf message and its MD5 digest
+type kplAggregator struct {
+	keys    []string
+	keyIdx  map[string]int
+	keySize int    // encoded size of the partition_key_table entries
+	records []byte // encoded records entries of the AggregatedRecord message
+}
+
+// fits reports whether a record can be added without exceeding the record size limit,
+// allowing for the field headers of the new record
+func (g *kplAggregator) fits(partitionKey string, data []byte) bool {
+	size := g.keySize + len(g.records) + protoFieldSize(len(data)) + 2*binary.MaxVarintLen64
+	if _, ok := g.keyIdx[partitionKey]; !ok {
+		size += protoFieldSize(len(partitionKey))
+	}
+	return len(kplMagic)+size+md5.Size+len(g.keys[0]) <= maxKinesisRecordBytes
+}
+
+func (g *kplAggregator) add(partitionKey string, data []byte) {
+	if g.keyIdx == nil {
+		g.keyIdx = map[string]int{}
+	}
+	idx, ok := g.keyIdx[partitionKey]
+	if !ok {
+		idx = len(g.keys)
+		g.keys = append(g.keys, partitionKey)
+		g.keyIdx[partitionKey] = idx
+		g.keySize += protoFieldSize(len(partitionKey))
+	}
+
+	// Record { partition_key_index = 1; data = 3; }
+	record := protoVarintField(nil, 1, uint64(idx))
+	record = protoBytesField(record, 3, data)
+	g.records = protoBytesField(g.records, 3, record)
+}
+
+// entry returns the aggregated record, routed by the first partition key
+func (g *kplAggregator) entry() *kinesis.PutRecordsRequestEntry {
+	// AggregatedRecord { repeated partition_key_table = 1; repeated records = 3; }
+	msg := make([]byte, 0, g.keySize+len(g.records))
+	for _, k := range g.keys {
+		msg = protoBytesField(msg, 1, []byte(k))
+	}
+	msg = append(msg, g.records...)
+	sum := md5.Sum(msg)
+
+	data := make([]byte, 0, len(kplMagic)+len(msg)+len(sum))
+	data = append(data, kplMagic...)
+	data = append(data, msg...)
+	data = append(data, sum[:]...)
+
+	return &kinesis.PutRecordsRequestEntry{PartitionKey: aws.String(g.keys[0]), Data: data}
+}
+
+func protoVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func protoVarintField(b []byte, field int, v uint64) []byte {
+	b = protoVarint(b, uint64(field)<<3)
+	return protoVarint(b, v)
+}
+
+func protoBytesField(b []byte, field int, v []byte) []byte {
+	b = protoVarint(b, uint64(field)<<3|2)
+	b = protoVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// protoFieldSize returns the encoded size of a length delimited field of n bytes with a
+// single byte tag
+func protoFieldSize(n int) int {
+	return 1 + len(protoVarint(nil, uint64(n))) + n
+}
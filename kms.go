@@ -0,0 +1,201 @@
+package awsx
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// envelopeVersion is the first byte of the envelope format of EnvelopeEncrypt:
+// version, encrypted data key length (uint16), encrypted data key, GCM nonce, ciphertext
+const envelopeVersion byte = 1
+
+// GetKMSClient returns a client for use with AWS KMS
+func (a *Config) GetKMSClient() kmsiface.KMSAPI {
+	return a.Service.Kms
+}
+
+// SetKMSClient sets a client for use with AWS KMS
+func (a *Config) SetKMSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Kms = kms.New(a.ClientConfig(kms.EndpointsID))
+
+	return a
+}
+
+// WithKMSClient sets the client used for AWS KMS calls, such as a mock from the awsxmock
+// package
+func (a *Config) WithKMSClient(client kmsiface.KMSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Kms = client
+
+	return a
+}
+
+// KMSKeyID returns the key ID to send to KMS for key: key IDs, ARNs and names starting
+// with alias/ are returned as is, other names are taken as alias names
+func KMSKeyID(key string) string {
+	if strings.HasPrefix(key, "alias/") || strings.HasPrefix(key, "arn:") || strings.HasPrefix(key, "mrk-") {
+		return key
+	}
+	if len(key) == 36 && strings.Count(key, "-") == 4 {
+		return key
+	}
+	return "alias/" + key
+}
+
+// Encrypt encrypts up to 4 KB of plaintext with the KMS key. The same encryption context
+// must be passed to Decrypt.
+func (a *Config) Encrypt(ctx context.Context, key string, plaintext []byte, encryptionContext map[string]string) ([]byte, error) {
+	if a.Service.Kms == nil {
+		a.SetKMSClient()
+	}
+
+	out, err := a.Service.Kms.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:             aws.String(KMSKeyID(key)),
+		Plaintext:         plaintext,
+		EncryptionContext: kmsContext(encryptionContext),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.CiphertextBlob, nil
+}
+
+// Decrypt decrypts a ciphertext of Encrypt, or an encrypted data key of GenerateDataKey
+func (a *Config) Decrypt(ctx context.Context, ciphertext []byte, encryptionContext map[string]string) ([]byte, error) {
+	if a.Service.Kms == nil {
+		a.SetKMSClient()
+	}
+
+	out, err := a.Service.Kms.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob:    ciphertext,
+		EncryptionContext: kmsContext(encryptionContext),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Plaintext, nil
+}
+
+// DataKey is a 256-bit data key returned by GenerateDataKey. Plaintext must only be kept
+// in memory, Ciphertext is stored next to the data it encrypts.
+type DataKey struct {
+	KeyID      string // ARN of the KMS key that encrypted the data key
+	Plaintext  []byte
+	Ciphertext []byte
+}
+
+// GenerateDataKey returns a new 256-bit data key encrypted with the KMS key
+func (a *Config) GenerateDataKey(ctx context.Context, key string, encryptionContext map[string]string) (*DataKey, error) {
+	if a.Service.Kms == nil {
+		a.SetKMSClient()
+	}
+
+	out, err := a.Service.Kms.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:             aws.String(KMSKeyID(key)),
+		KeySpec:           aws.String(kms.DataKeySpecAes256),
+		EncryptionContext: kmsContext(encryptionContext),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataKey{KeyID: aws.StringValue(out.KeyId), Plaintext: out.Plaintext, Ciphertext: out.CiphertextBlob}, nil
+}
+
+// EnvelopeEncrypt encrypts plaintext of any size with AES-256-GCM under a new data key
+// from GenerateDataKey and returns the encrypted data key together with the ciphertext,
+// e.g. to store cached discovery results or connection secrets at rest
+func (a *Config) EnvelopeEncrypt(ctx context.Context, key string, plaintext []byte, encryptionContext map[string]string) ([]byte, error) {
+	dk, err := a.GenerateDataKey(ctx, key, encryptionContext)
+	if err != nil {
+		return nil, err
+	}
+	if len(dk.Ciphertext) > 0xFFFF {
+		return nil, errors.New("encrypted data key too large")
+	}
+
+	gcm, err := newGCM(dk.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 3, 3+len(dk.Ciphertext)+len(nonce)+len(plaintext)+gcm.Overhead())
+	out[0] = envelopeVersion
+	binary.BigEndian.PutUint16(out[1:3], uint16(len(dk.Ciphertext)))
+	out = append(out, dk.Ciphertext...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, out[:3+len(dk.Ciphertext)]), nil
+}
+
+// EnvelopeDecrypt decrypts data of EnvelopeEncrypt, decrypting its data key with KMS
+func (a *Config) EnvelopeDecrypt(ctx context.Context, data []byte, encryptionContext map[string]string) ([]byte, error) {
+	if len(data) < 3 || data[0] != envelopeVersion {
+		return nil, errors.New("not an awsx envelope")
+	}
+	keyLen := int(binary.BigEndian.Uint16(data[1:3]))
+	if len(data) < 3+keyLen {
+		return nil, errors.New("truncated envelope")
+	}
+	header, rest := data[:3+keyLen], data[3+keyLen:]
+
+	plainKey, err := a.Decrypt(ctx, header[3:], encryptionContext)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, errors.New("truncated envelope")
+	}
+
+	return gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+}
+
+// EnvelopeDecryptSecret is EnvelopeDecrypt returning a Secret, so that decrypted
+// connection secrets are redacted when logged
+func (a *Config) EnvelopeDecryptSecret(ctx context.Context, data []byte, encryptionContext map[string]string) (Secret, error) {
+	plaintext, err := a.EnvelopeDecrypt(ctx, data, encryptionContext)
+	if err != nil {
+		return "", err
+	}
+	return Secret(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// kmsContext converts an encryption context to its SDK form, nil when empty
+func kmsContext(encryptionContext map[string]string) map[string]*string {
+	if len(encryptionContext) == 0 {
+		return nil
+	}
+	return aws.StringMap(encryptionContext)
+}
@@ -0,0 +1,553 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// RedisGroupOptions describes a replication group created by CreateRedisReplicationGroup
+type RedisGroupOptions struct {
+	ReplicationGroupID string
+	NodeType           string            // e.g. cache.t4g.small
+	Description        string            // optional: defaults to "awsx <id>"
+	Engine             string            // optional: redis (default) or valkey
+	EngineVersion      string            // optional: defaults to the latest version
+	Replicas           int               // optional: read replicas, per shard when cluster mode is enabled
+	Shards             int               // optional: number of shards, enables cluster mode when set
+	Port               int               // optional: defaults to 6379
+	ParameterGroup     string            // optional: cache parameter group name
+	SubnetGroup        string            // optional: cache subnet group to launch into
+	SecurityGroupIDs   []string          // optional: VPC security groups for the group
+	UserGroupIDs       []string          // optional: RBAC user groups to attach
+	AuthToken          Secret            // optional: requires TransitEncryption
+	TransitEncryption  bool              // optional: enable in-transit encryption
+	AtRestEncryption   bool              // optional: enable at-rest encryption
+	MultiAZ            bool              // optional: enable Multi-AZ with automatic failover, requires replicas
+	SnapshotName       string            // optional: seed the group from a snapshot
+	Tags               map[string]string // optional: tags for the group
+}
+
+// CreateRedisReplicationGroup creates a replication group from opts, blocks until it is
+// available or ctx is done, and returns its endpoints
+func (a *Config) CreateRedisReplicationGroup(ctx context.Context, opts *RedisGroupOptions) (*RedisEndpoints, error) {
+	if opts == nil || opts.ReplicationGroupID == "" {
+		return nil, errors.New("must provide a replication group id")
+	}
+	if opts.NodeType == "" && opts.SnapshotName == "" {
+		return nil, errors.New("must provide a node type")
+	}
+	if opts.AuthToken != "" && !opts.TransitEncryption {
+		return nil, errors.New("an auth token requires transit encryption")
+	}
+	if opts.MultiAZ && opts.Replicas < 1 {
+		return nil, errors.New("multi-az requires at least one replica")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.CreateReplicationGroupInput{
+		ReplicationGroupId:          aws.String(opts.ReplicationGroupID),
+		ReplicationGroupDescription: aws.String(opts.Description),
+		Engine:                      aws.String("redis"),
+	}
+	if opts.Description == "" {
+		input.ReplicationGroupDescription = aws.String("awsx " + opts.ReplicationGroupID)
+	}
+	if opts.Engine != "" {
+		input.Engine = aws.String(opts.Engine)
+	}
+	if opts.NodeType != "" {
+		input.CacheNodeType = aws.String(opts.NodeType)
+	}
+	if opts.EngineVersion != "" {
+		input.EngineVersion = aws.String(opts.EngineVersion)
+	}
+	if opts.Shards > 0 {
+		// cluster mode requires automatic failover
+		input.NumNodeGroups = aws.Int64(int64(opts.Shards))
+		input.ReplicasPerNodeGroup = aws.Int64(int64(opts.Replicas))
+		input.AutomaticFailoverEnabled = aws.Bool(true)
+	} else {
+		input.NumCacheClusters = aws.Int64(int64(opts.Replicas + 1))
+		input.AutomaticFailoverEnabled = aws.Bool(opts.MultiAZ)
+	}
+	if opts.MultiAZ {
+		input.MultiAZEnabled = aws.Bool(true)
+	}
+	if opts.Port > 0 {
+		input.Port = aws.Int64(int64(opts.Port))
+	}
+	if opts.ParameterGroup != "" {
+		input.CacheParameterGroupName = aws.String(opts.ParameterGroup)
+	}
+	if opts.SubnetGroup != "" {
+		input.CacheSubnetGroupName = aws.String(opts.SubnetGroup)
+	}
+	if len(opts.SecurityGroupIDs) > 0 {
+		input.SecurityGroupIds = aws.StringSlice(opts.SecurityGroupIDs)
+	}
+	if len(opts.UserGroupIDs) > 0 {
+		input.UserGroupIds = aws.StringSlice(opts.UserGroupIDs)
+	}
+	if opts.AuthToken != "" {
+		input.AuthToken = aws.String(opts.AuthToken.UnsafeRaw())
+	}
+	if opts.TransitEncryption {
+		input.TransitEncryptionEnabled = aws.Bool(true)
+	}
+	if opts.AtRestEncryption {
+		input.AtRestEncryptionEnabled = aws.Bool(true)
+	}
+	if opts.SnapshotName != "" {
+		input.SnapshotName = aws.String(opts.SnapshotName)
+	}
+	for k, v := range opts.Tags {
+		input.Tags = append(input.Tags, &elasticache.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	if _, err := a.Service.Ec.CreateReplicationGroupWithContext(ctx, input); err != nil {
+		return nil, err
+	}
+
+	if err := a.waitForRedisReplicationGroup(ctx, opts.ReplicationGroupID); err != nil {
+		return nil, err
+	}
+
+	return a.GetRedisPrimaryEndpoint(opts.ReplicationGroupID)
+}
+
+// ScaleRedisReplicas changes the number of read replicas of a replication group, per
+// shard when cluster mode is enabled, applying the change immediately and blocking until
+// the group is available again or ctx is done. Every shard is brought to the count, so
+// shards above and below it are scaled in turn.
+func (a *Config) ScaleRedisReplicas(ctx context.Context, replicationGroup string, replicas int) (*RedisEndpoints, error) {
+	if replicationGroup == "" {
+		return nil, errors.New("no replication group provided")
+	}
+	if replicas < 0 {
+		return nil, errors.New("replica count cannot be negative")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	result, count, err := a.getECReplicationGroup(ctx, replicationGroup)
+	if err != nil {
+		return nil, err
+	}
+	if count != 1 {
+		return nil, errors.New("no replication group matching " + replicationGroup)
+	}
+	rg := result.ReplicationGroups[0]
+	if len(rg.NodeGroups) == 0 {
+		return nil, errors.New("replication group " + replicationGroup + " has no node groups")
+	}
+
+	// shards of a cluster mode group can differ after a partial failure, so each one is
+	// compared and only the ones off the target count are changed
+	var grow, shrink []*elasticache.ConfigureShard
+	for _, ng := range rg.NodeGroups {
+		shard := &elasticache.ConfigureShard{NodeGroupId: ng.NodeGroupId, NewReplicaCount: aws.Int64(int64(replicas))}
+		switch current := len(ng.NodeGroupMembers) - 1; {
+		case replicas > current:
+			grow = append(grow, shard)
+		case replicas < current:
+			shrink = append(shrink, shard)
+		}
+	}
+	clusterMode := aws.BoolValue(rg.ClusterEnabled)
+
+	if len(grow) > 0 {
+		input := &elasticache.IncreaseReplicaCountInput{
+			ReplicationGroupId: aws.String(replicationGroup),
+			ApplyImmediately:   aws.Bool(true),
+		}
+		if clusterMode {
+			input.ReplicaConfiguration = grow
+		} else {
+			input.NewReplicaCount = aws.Int64(int64(replicas))
+		}
+		if _, err := a.Service.Ec.IncreaseReplicaCountWithContext(ctx, input); err != nil {
+			return nil, err
+		}
+		if err := a.waitForRedisReplicationGroup(ctx, replicationGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(shrink) > 0 {
+		input := &elasticache.DecreaseReplicaCountInput{
+			ReplicationGroupId: aws.String(replicationGroup),
+			ApplyImmediately:   aws.Bool(true),
+		}
+		if clusterMode {
+			input.ReplicaConfiguration = shrink
+		} else {
+			input.NewReplicaCount = aws.Int64(int64(replicas))
+		}
+		if _, err := a.Service.Ec.DecreaseReplicaCountWithContext(ctx, input); err != nil {
+			return nil, err
+		}
+		if err := a.waitForRedisReplicationGroup(ctx, replicationGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.GetRedisPrimaryEndpoint(replicationGroup)
+}
+
+// DeleteRedisReplicationGroup deletes a replication group, taking a final snapshot when
+// finalSnapshot is not empty, and blocks until the deletion completes or ctx is done
+func (a *Config) DeleteRedisReplicationGroup(ctx context.Context, replicationGroup, finalSnapshot string) error {
+	if replicationGroup == "" {
+		return errors.New("no replication group provided")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DeleteReplicationGroupInput{
+		ReplicationGroupId:   aws.String(replicationGroup),
+		RetainPrimaryCluster: aws.Bool(false),
+	}
+	if finalSnapshot != "" {
+		input.FinalSnapshotIdentifier = aws.String(finalSnapshot)
+	}
+
+	if _, err := a.Service.Ec.DeleteReplicationGroupWithContext(ctx, input); err != nil {
+		return err
+	}
+	a.forget("redis:" + replicationGroup)
+
+	return a.Service.Ec.WaitUntilReplicationGroupDeletedWithContext(ctx, &elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroup),
+	})
+}
+
+// waitForRedisReplicationGroup blocks until the replication group is available and drops
+// its cached endpoints
+func (a *Config) waitForRedisReplicationGroup(ctx context.Context, replicationGroup string) error {
+	err := a.Service.Ec.WaitUntilReplicationGroupAvailableWithContext(ctx, &elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(replicationGroup),
+	})
+	a.forget("redis:" + replicationGroup)
+	return err
+}
+
+const (
+	defaultAuroraEngine        = "aurora-postgresql"
+	defaultAuroraInstanceClass = "db.r6g.large"
+	rdsPollInterval            = 15 * time.Second
+)
+
+// AuroraClusterOptions describes a DB cluster created by CreateAuroraCluster
+type AuroraClusterOptions struct {
+	ClusterID          string
+	Engine             string            // optional: aurora-postgresql (default) or aurora-mysql
+	EngineVersion      string            // optional: defaults to the engine default
+	InstanceClass      string            // optional: defaults to db.r6g.large, db.serverless for Serverless v2
+	Readers            int               // optional: reader instances created along with the writer
+	MasterUsername     string            // optional: defaults to awsx
+	MasterPassword     Secret            // optional: when empty the password is managed in Secrets Manager
+	DatabaseName       string            // optional: initial database
+	Port               int               // optional: defaults to the engine port
+	ParameterGroup     string            // optional: DB cluster parameter group name
+	SubnetGroup        string            // optional: DB subnet group to launch into
+	SecurityGroupIDs   []string          // optional: VPC security groups for the cluster
+	KMSKeyID           string            // optional: key for storage encryption, which is always enabled
+	IAMAuth            bool              // optional: enable IAM database authentication
+	DeletionProtection bool              // optional: enable deletion protection
+	Tags               map[string]string // optional: tags for the cluster and its instances
+}
+
+// CreateAuroraCluster creates a DB cluster from opts with a writer instance and
+// opts.Readers reader instances, named <cluster>-1, <cluster>-2 and so on, blocks until
+// every instance is available or ctx is done, and returns the endpoints
+func (a *Config) CreateAuroraCluster(ctx context.Context, opts *AuroraClusterOptions) (*AuroraEndpoints, error) {
+	if opts == nil || opts.ClusterID == "" {
+		return nil, errors.New("must provide a cluster id")
+	}
+	if opts.Readers < 0 {
+		return nil, errors.New("reader count cannot be negative")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	engine := opts.Engine
+	if engine == "" {
+		engine = defaultAuroraEngine
+	}
+	instanceClass := opts.InstanceClass
+	if instanceClass == "" {
+		instanceClass = defaultAuroraInstanceClass
+	}
+	username := opts.MasterUsername
+	if username == "" {
+		username = "awsx"
+	}
+
+	tags := make([]*rds.Tag, 0, len(opts.Tags))
+	for k, v := range opts.Tags {
+		tags = append(tags, &rds.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	input := &rds.CreateDBClusterInput{
+		DBClusterIdentifier:             aws.String(opts.ClusterID),
+		Engine:                          aws.String(engine),
+		MasterUsername:                  aws.String(username),
+		StorageEncrypted:                aws.Bool(true),
+		EnableIAMDatabaseAuthentication: aws.Bool(opts.IAMAuth),
+		DeletionProtection:              aws.Bool(opts.DeletionProtection),
+		Tags:                            tags,
+	}
+	if opts.MasterPassword != "" {
+		input.MasterUserPassword = aws.String(opts.MasterPassword.UnsafeRaw())
+	} else {
+		input.ManageMasterUserPassword = aws.Bool(true)
+	}
+	if opts.EngineVersion != "" {
+		input.EngineVersion = aws.String(opts.EngineVersion)
+	}
+	if opts.DatabaseName != "" {
+		input.DatabaseName = aws.String(opts.DatabaseName)
+	}
+	if opts.Port > 0 {
+		input.Port = aws.Int64(int64(opts.Port))
+	}
+	if opts.ParameterGroup != "" {
+		input.DBClusterParameterGroupName = aws.String(opts.ParameterGroup)
+	}
+	if opts.SubnetGroup != "" {
+		input.DBSubnetGroupName = aws.String(opts.SubnetGroup)
+	}
+	if len(opts.SecurityGroupIDs) > 0 {
+		input.VpcSecurityGroupIds = aws.StringSlice(opts.SecurityGroupIDs)
+	}
+	if opts.KMSKeyID != "" {
+		input.KmsKeyId = aws.String(opts.KMSKeyID)
+	}
+	if instanceClass == "db.serverless" {
+		input.ServerlessV2ScalingConfiguration = &rds.ServerlessV2ScalingConfiguration{
+			MinCapacity: aws.Float64(0.5),
+			MaxCapacity: aws.Float64(16),
+		}
+	}
+
+	if _, err := a.Service.Rds.CreateDBClusterWithContext(ctx, input); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i <= opts.Readers; i++ {
+		_, err := a.Service.Rds.CreateDBInstanceWithContext(ctx, &rds.CreateDBInstanceInput{
+			DBClusterIdentifier:  aws.String(opts.ClusterID),
+			DBInstanceIdentifier: aws.String(opts.ClusterID + "-" + strconv.Itoa(i+1)),
+			DBInstanceClass:      aws.String(instanceClass),
+			Engine:               aws.String(engine),
+			Tags:                 tags,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.waitForAuroraInstances(ctx, opts.ClusterID); err != nil {
+		return nil, err
+	}
+
+	return a.GetAuroraEndpoints(opts.ClusterID)
+}
+
+// AddAuroraReader adds a reader instance to a DB cluster, with the instance class of the
+// writer when instanceClass is empty, and blocks until it is available or ctx is done
+func (a *Config) AddAuroraReader(ctx context.Context, cluster, instanceID, instanceClass string) (*AuroraEndpoints, error) {
+	if cluster == "" || instanceID == "" {
+		return nil, errors.New("must provide a cluster and instance id")
+	}
+
+	result, err := a.GetRDSClusterDetails(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.DBClusters) == 0 {
+		return nil, errors.New("no db cluster associated with this cluster name")
+	}
+	c := result.DBClusters[0]
+
+	if instanceClass == "" {
+		instances, err := a.GetRDSClusterInstances(cluster)
+		if err != nil {
+			return nil, err
+		}
+		writers := map[string]bool{}
+		for _, m := range c.DBClusterMembers {
+			writers[aws.StringValue(m.DBInstanceIdentifier)] = aws.BoolValue(m.IsClusterWriter)
+		}
+		for _, i := range instances {
+			if writers[aws.StringValue(i.DBInstanceIdentifier)] {
+				instanceClass = aws.StringValue(i.DBInstanceClass)
+			}
+		}
+		if instanceClass == "" {
+			instanceClass = defaultAuroraInstanceClass
+		}
+	}
+
+	_, err = a.Service.Rds.CreateDBInstanceWithContext(ctx, &rds.CreateDBInstanceInput{
+		DBClusterIdentifier:  aws.String(cluster),
+		DBInstanceIdentifier: aws.String(instanceID),
+		DBInstanceClass:      aws.String(instanceClass),
+		Engine:               c.Engine,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.waitForAuroraInstances(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	return a.GetAuroraEndpoints(cluster)
+}
+
+// FailoverAuroraCluster fails the DB cluster over to the target reader instance, or to a
+// reader chosen by RDS when target is empty, and blocks until a new writer is promoted
+// and the cluster is available or ctx is done. When target is already the writer the
+// current endpoints are returned without a failover.
+func (a *Config) FailoverAuroraCluster(ctx context.Context, cluster, target string) (*AuroraEndpoints, error) {
+	if cluster == "" {
+		return nil, errors.New("no cluster name provided")
+	}
+
+	before, err := a.getAuroraEndpoints(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	oldWriter := ""
+	if before.WriterInstance != nil {
+		oldWriter = before.WriterInstance.Instance
+	}
+	if target != "" && target == oldWriter {
+		return before, nil
+	}
+
+	input := &rds.FailoverDBClusterInput{DBClusterIdentifier: aws.String(cluster)}
+	if target != "" {
+		input.TargetDBInstanceIdentifier = aws.String(target)
+	}
+	if _, err := a.Service.Rds.FailoverDBClusterWithContext(ctx, input); err != nil {
+		return nil, err
+	}
+
+	err = a.pollRDS(ctx, func() (bool, error) {
+		result, err := a.GetRDSClusterDetails(cluster)
+		if err != nil {
+			return false, err
+		}
+		if len(result.DBClusters) == 0 || aws.StringValue(result.DBClusters[0].Status) != "available" {
+			return false, nil
+		}
+		for _, m := range result.DBClusters[0].DBClusterMembers {
+			if !aws.BoolValue(m.IsClusterWriter) {
+				continue
+			}
+			id := aws.StringValue(m.DBInstanceIdentifier)
+			return id != oldWriter && (target == "" || id == target), nil
+		}
+		return false, nil
+	})
+	a.forget("aurora:" + cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.GetAuroraEndpoints(cluster)
+}
+
+// DeleteAuroraCluster deletes every instance of the DB cluster and then the cluster,
+// taking a final snapshot when finalSnapshot is not empty, and blocks until the cluster
+// is deleted or ctx is done
+func (a *Config) DeleteAuroraCluster(ctx context.Context, cluster, finalSnapshot string) error {
+	if cluster == "" {
+		return errors.New("no cluster name provided")
+	}
+
+	instances, err := a.GetRDSClusterInstances(cluster)
+	if err != nil {
+		return err
+	}
+	for _, i := range instances {
+		_, err := a.Service.Rds.DeleteDBInstanceWithContext(ctx, &rds.DeleteDBInstanceInput{
+			DBInstanceIdentifier: i.DBInstanceIdentifier,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, i := range instances {
+		err := a.Service.Rds.WaitUntilDBInstanceDeletedWithContext(ctx, &rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: i.DBInstanceIdentifier,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	input := &rds.DeleteDBClusterInput{
+		DBClusterIdentifier: aws.String(cluster),
+		SkipFinalSnapshot:   aws.Bool(finalSnapshot == ""),
+	}
+	if finalSnapshot != "" {
+		input.FinalDBSnapshotIdentifier = aws.String(finalSnapshot)
+	}
+	if _, err := a.Service.Rds.DeleteDBClusterWithContext(ctx, input); err != nil {
+		return err
+	}
+	a.forget("aurora:" + cluster)
+
+	return a.pollRDS(ctx, func() (bool, error) {
+		_, err := a.GetRDSClusterDetails(cluster)
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBClusterNotFoundFault {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// waitForAuroraInstances blocks until every instance of the DB cluster is available and
+// drops the cached endpoints of the cluster
+func (a *Config) waitForAuroraInstances(ctx context.Context, cluster string) error {
+	err := a.Service.Rds.WaitUntilDBInstanceAvailableWithContext(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []*rds.Filter{
+			{Name: aws.String("db-cluster-id"), Values: aws.StringSlice([]string{cluster})},
+		},
+	})
+	a.forget("aurora:" + cluster)
+	return err
+}
+
+// pollRDS calls done every rdsPollInterval until it returns true or an error, or ctx is done
+func (a *Config) pollRDS(ctx context.Context, done func() (bool, error)) error {
+	for {
+		ok, err := done()
+		if err != nil || ok {
+			return err
+		}
+
+		if !a.wait(rdsPollInterval, ctx.Done()) {
+			return ctx.Err()
+		}
+	}
+}
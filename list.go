@@ -0,0 +1,162 @@
+package awsx
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// listPageSize is the MaxRecords requested per page by the List and Each functions
+const listPageSize = 100
+
+// EachECReplicationGroup calls fn for every ElastiCache replication group in the region,
+// iterating all pages of DescribeReplicationGroups. Iteration stops when fn returns false.
+func (a *Config) EachECReplicationGroup(fn func(*elasticache.ReplicationGroup) bool) error {
+	return a.eachECReplicationGroup(a.context(), fn)
+}
+
+// eachECReplicationGroup is EachECReplicationGroup with the context of the call
+func (a *Config) eachECReplicationGroup(ctx context.Context, fn func(*elasticache.ReplicationGroup) bool) error {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeReplicationGroupsInput{
+		MaxRecords: aws.Int64(listPageSize),
+	}
+
+	return a.Service.Ec.DescribeReplicationGroupsPagesWithContext(ctx, input, func(page *elasticache.DescribeReplicationGroupsOutput, lastPage bool) bool {
+		for _, rg := range page.ReplicationGroups {
+			if !fn(rg) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ListECReplicationGroups returns every ElastiCache replication group in the region
+func (a *Config) ListECReplicationGroups() ([]*elasticache.ReplicationGroup, error) {
+	list := make([]*elasticache.ReplicationGroup, 0)
+	err := a.EachECReplicationGroup(func(rg *elasticache.ReplicationGroup) bool {
+		list = append(list, rg)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// EachECCacheCluster calls fn for every ElastiCache cache cluster in the region, including
+// node information, iterating all pages of DescribeCacheClusters. Iteration stops when fn
+// returns false.
+func (a *Config) EachECCacheCluster(fn func(*elasticache.CacheCluster) bool) error {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeCacheClustersInput{
+		MaxRecords:        aws.Int64(listPageSize),
+		ShowCacheNodeInfo: aws.Bool(true),
+	}
+
+	return a.Service.Ec.DescribeCacheClustersPages(input, func(page *elasticache.DescribeCacheClustersOutput, lastPage bool) bool {
+		for _, cc := range page.CacheClusters {
+			if !fn(cc) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ListECCacheClusters returns every ElastiCache cache cluster in the region
+func (a *Config) ListECCacheClusters() ([]*elasticache.CacheCluster, error) {
+	list := make([]*elasticache.CacheCluster, 0)
+	err := a.EachECCacheCluster(func(cc *elasticache.CacheCluster) bool {
+		list = append(list, cc)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// EachRDSDBCluster calls fn for every RDS DB cluster in the region, iterating all pages
+// of DescribeDBClusters. Iteration stops when fn returns false.
+func (a *Config) EachRDSDBCluster(fn func(*rds.DBCluster) bool) error {
+	return a.eachRDSDBCluster(&rds.DescribeDBClustersInput{}, fn)
+}
+
+// eachRDSDBCluster iterates all pages of DescribeDBClusters for the input
+func (a *Config) eachRDSDBCluster(input *rds.DescribeDBClustersInput, fn func(*rds.DBCluster) bool) error {
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input.MaxRecords = aws.Int64(listPageSize)
+
+	return a.Service.Rds.DescribeDBClustersPages(input, func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+		for _, c := range page.DBClusters {
+			if !fn(c) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ListRDSDBClusters returns every RDS DB cluster in the region
+func (a *Config) ListRDSDBClusters() ([]*rds.DBCluster, error) {
+	list := make([]*rds.DBCluster, 0)
+	err := a.EachRDSDBCluster(func(c *rds.DBCluster) bool {
+		list = append(list, c)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// EachRDSDBInstance calls fn for every RDS DB instance in the region, iterating all pages
+// of DescribeDBInstances. Iteration stops when fn returns false.
+func (a *Config) EachRDSDBInstance(fn func(*rds.DBInstance) bool) error {
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeDBInstancesInput{
+		MaxRecords: aws.Int64(listPageSize),
+	}
+
+	return a.Service.Rds.DescribeDBInstancesPages(input, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		for _, i := range page.DBInstances {
+			if !fn(i) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ListRDSDBInstances returns every RDS DB instance in the region
+func (a *Config) ListRDSDBInstances() ([]*rds.DBInstance, error) {
+	list := make([]*rds.DBInstance, 0)
+	err := a.EachRDSDBInstance(func(i *rds.DBInstance) bool {
+		list = append(list, i)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
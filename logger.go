@@ -0,0 +1,28 @@
+package awsx
+
+import (
+	"log"
+	"os"
+)
+
+// stderrLogger receives the warnings of a Config without a Logger. Warnings never go to
+// standard output, where they would corrupt the output of programs such as the awsx
+// command.
+var stderrLogger = log.New(os.Stderr, "", 0)
+
+// SetLogger sets the logger receiving the warnings of the Config, such as a stale result
+// being served or a missing builder argument. Passing nil restores the default of
+// writing them to standard error.
+func (a *Config) SetLogger(l *log.Logger) *Config {
+	a.Logger = l
+	return a
+}
+
+// warn writes a warning to the Logger of the Config
+func (a *Config) warn(v ...interface{}) {
+	l := a.Logger
+	if l == nil {
+		l = stderrLogger
+	}
+	l.Println(v...)
+}
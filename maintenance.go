@@ -0,0 +1,242 @@
+package awsx
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+const minutesPerWeek = 7 * 24 * 60
+
+var weekdays = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+// MaintenanceInfo is the preferred maintenance window and the pending modifications of an
+// RDS or ElastiCache resource
+type MaintenanceInfo struct {
+	Service      string            `json:"service" yaml:"service"`             // rds or elasticache
+	ResourceType string            `json:"resource_type" yaml:"resource_type"` // e.g. db-cluster or cache-cluster
+	ResourceID   string            `json:"resource_id" yaml:"resource_id"`
+	Window       string            `json:"window,omitempty" yaml:"window,omitempty"` // ddd:hh24:mi-ddd:hh24:mi in UTC
+	Pending      map[string]string `json:"pending,omitempty" yaml:"pending,omitempty"`
+}
+
+// InMaintenanceWindow reports whether now is within the preferred maintenance window
+func (m *MaintenanceInfo) InMaintenanceWindow(now time.Time) bool {
+	w, err := ParseMaintenanceWindow(m.Window)
+	if err != nil {
+		return false
+	}
+	return w.Contains(now)
+}
+
+// MaintenanceReport holds the maintenance information of a cluster and its members
+type MaintenanceReport []*MaintenanceInfo
+
+// InMaintenanceWindow reports whether now is within the maintenance window of any
+// resource, so deploy tooling can avoid restarting services during AWS maintenance
+func (r MaintenanceReport) InMaintenanceWindow(now time.Time) bool {
+	for _, m := range r {
+		if m.InMaintenanceWindow(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPending reports whether any resource has pending modifications
+func (r MaintenanceReport) HasPending() bool {
+	for _, m := range r {
+		if len(m.Pending) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceWindow is a weekly window in UTC
+type MaintenanceWindow struct {
+	Start int // minutes since Sunday 00:00 UTC
+	End   int // minutes since Sunday 00:00 UTC, before Start when the window wraps the week
+}
+
+// ParseMaintenanceWindow parses a window in the ddd:hh24:mi-ddd:hh24:mi format used by
+// RDS and ElastiCache, e.g. sun:05:00-sun:06:00
+func ParseMaintenanceWindow(window string) (*MaintenanceWindow, error) {
+	parts := strings.Split(strings.ToLower(window), "-")
+	if len(parts) != 2 {
+		return nil, errors.New("invalid maintenance window " + window)
+	}
+
+	start, err := weekMinute(parts[0])
+	if err != nil {
+		return nil, errors.New("invalid maintenance window " + window)
+	}
+	end, err := weekMinute(parts[1])
+	if err != nil {
+		return nil, errors.New("invalid maintenance window " + window)
+	}
+
+	return &MaintenanceWindow{Start: start, End: end}, nil
+}
+
+// weekMinute parses ddd:hh24:mi into minutes since Sunday 00:00
+func weekMinute(s string) (int, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 3 {
+		return 0, errors.New("invalid time " + s)
+	}
+	day, ok := weekdays[fields[0]]
+	if !ok {
+		return 0, errors.New("invalid day " + fields[0])
+	}
+	hour, err := strconv.Atoi(fields[1])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, errors.New("invalid hour " + fields[1])
+	}
+	minute, err := strconv.Atoi(fields[2])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, errors.New("invalid minute " + fields[2])
+	}
+	return day*24*60 + hour*60 + minute, nil
+}
+
+// Contains reports whether t is within the window
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	m := int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute()
+	if w.Start <= w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
+}
+
+// Next returns the start of the next window after t, or t when t is within the window
+func (w *MaintenanceWindow) Next(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	t = t.UTC().Truncate(time.Minute)
+	m := int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute()
+	wait := (w.Start - m + minutesPerWeek) % minutesPerWeek
+	return t.Add(time.Duration(wait) * time.Minute)
+}
+
+// GetRedisMaintenance returns the maintenance window and pending modifications of a
+// replication group and each of its member cache clusters, or of a single cache cluster
+func (a *Config) GetRedisMaintenance(cluster string) (MaintenanceReport, error) {
+	if cluster == "" {
+		return nil, errors.New("no cluster name provided")
+	}
+
+	report := make(MaintenanceReport, 0)
+	members := []string{cluster}
+	result, count, err := a.GetECReplicationGroupE(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if count == 1 {
+		rg := result.ReplicationGroups[0]
+		report = append(report, &MaintenanceInfo{
+			Service:      "elasticache",
+			ResourceType: elasticache.SourceTypeReplicationGroup,
+			ResourceID:   cluster,
+			Pending:      pendingValues(rg.PendingModifiedValues),
+		})
+		members = aws.StringValueSlice(rg.MemberClusters)
+	}
+
+	for _, id := range members {
+		list, err := a.GetECClusterDetails(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, cc := range list.CacheClusters {
+			report = append(report, &MaintenanceInfo{
+				Service:      "elasticache",
+				ResourceType: elasticache.SourceTypeCacheCluster,
+				ResourceID:   aws.StringValue(cc.CacheClusterId),
+				Window:       aws.StringValue(cc.PreferredMaintenanceWindow),
+				Pending:      pendingValues(cc.PendingModifiedValues),
+			})
+		}
+	}
+
+	// the window is set per cache cluster, the group shares that of its members
+	if len(report) > 1 && report[0].ResourceType == elasticache.SourceTypeReplicationGroup {
+		report[0].Window = report[1].Window
+	}
+
+	return report, nil
+}
+
+// GetAuroraMaintenance returns the maintenance window and pending modifications of a DB
+// cluster and each of its instances
+func (a *Config) GetAuroraMaintenance(cluster string) (MaintenanceReport, error) {
+	result, err := a.GetRDSClusterDetails(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.DBClusters) == 0 {
+		return nil, errors.New("no db cluster associated with this cluster name")
+	}
+	c := result.DBClusters[0]
+
+	report := MaintenanceReport{{
+		Service:      "rds",
+		ResourceType: rds.SourceTypeDbCluster,
+		ResourceID:   aws.StringValue(c.DBClusterIdentifier),
+		Window:       aws.StringValue(c.PreferredMaintenanceWindow),
+		Pending:      pendingValues(c.PendingModifiedValues),
+	}}
+
+	instances, err := a.GetRDSClusterInstances(cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range instances {
+		report = append(report, &MaintenanceInfo{
+			Service:      "rds",
+			ResourceType: rds.SourceTypeDbInstance,
+			ResourceID:   aws.StringValue(i.DBInstanceIdentifier),
+			Window:       aws.StringValue(i.PreferredMaintenanceWindow),
+			Pending:      pendingValues(i.PendingModifiedValues),
+		})
+	}
+
+	return report, nil
+}
+
+// pendingValues flattens the set fields of an SDK PendingModifiedValues struct into a map
+func pendingValues(v interface{}) map[string]string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	pending := map[string]string{}
+	for k, f := range fields {
+		if f == nil {
+			continue
+		}
+		if s, ok := f.(string); ok {
+			pending[k] = s
+			continue
+		}
+		b, _ := json.Marshal(f)
+		pending[k] = string(b)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	return pending
+}
@@ -0,0 +1,187 @@
+package awsx
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// DefaultFallbackRegion is the region used when none is configured or detected,
+// unless the fallback is changed with SetFallbackRegion
+const DefaultFallbackRegion = "us-east-1"
+
+// regionMu guards the region detection state of a Config
+var regionMu sync.Mutex
+
+// metadataTimeout is the default timeout for calls to the EC2 and ECS metadata endpoints
+const metadataTimeout = 3 * time.Second
+
+// MetadataOptions configures the EC2 instance metadata client. The client fetches an
+// IMDSv2 session token first; where the PUT hop limit prevents the token response from
+// reaching a container, it falls back to IMDSv1 unless DisableV1Fallback is set.
+type MetadataOptions struct {
+	Timeout           time.Duration // per request timeout, defaults to 3 seconds
+	Retries           int           // retries per request, defaults to 0 so unreachable IMDS fails fast
+	DisableV1Fallback bool          // require IMDSv2 and never fall back to IMDSv1
+}
+
+// SetMetadataOptions sets the options of the EC2 instance metadata client. It must be
+// called before WithInstanceRole or WithAllProviders to affect the credential chain.
+func (a *Config) SetMetadataOptions(opts MetadataOptions) *Config {
+	a.MetadataOptions = &opts
+	a.metadataClient = nil
+	return a
+}
+
+// metadataOptions returns the metadata options with defaults applied
+func (a *Config) metadataOptions() MetadataOptions {
+	opts := MetadataOptions{}
+	if a.MetadataOptions != nil {
+		opts = *a.MetadataOptions
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = metadataTimeout
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	return opts
+}
+
+// MetadataClient returns the EC2 instance metadata client used for instance role
+// credentials and region detection, which can also be used to fetch other metadata
+func (a *Config) MetadataClient() (*ec2metadata.EC2Metadata, error) {
+	if a.metadataClient != nil {
+		return a.metadataClient, nil
+	}
+
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := a.metadataOptions()
+	a.metadataClient = ec2metadata.New(sess, &aws.Config{
+		HTTPClient:                        &http.Client{Timeout: opts.Timeout},
+		MaxRetries:                        aws.Int(opts.Retries),
+		EC2MetadataDisableTimeoutOverride: aws.Bool(true),
+		EC2MetadataEnableFallback:         aws.Bool(!opts.DisableV1Fallback),
+	})
+
+	return a.metadataClient, nil
+}
+
+// InstanceIdentity returns the instance identity document of the EC2 instance
+func (a *Config) InstanceIdentity() (ec2metadata.EC2InstanceIdentityDocument, error) {
+	client, err := a.MetadataClient()
+	if err != nil {
+		return ec2metadata.EC2InstanceIdentityDocument{}, err
+	}
+	return client.GetInstanceIdentityDocument()
+}
+
+// AvailabilityZone returns the availability zone of the ECS task or EC2 instance, e.g.
+// for use with PreferAZ
+func (a *Config) AvailabilityZone() (string, error) {
+	if task, err := GetECSTaskMetadata(); err == nil && task.AvailabilityZone != "" {
+		return task.AvailabilityZone, nil
+	}
+
+	client, err := a.MetadataClient()
+	if err != nil {
+		return "", err
+	}
+	return client.GetMetadata("placement/availability-zone")
+}
+
+// SetFallbackRegion sets the region used when no region is configured, set in the
+// environment, or detected from instance or task metadata. An empty fallback makes
+// GetSession fail instead of guessing a region.
+func (a *Config) SetFallbackRegion(region string) *Config {
+	a.FallbackRegion = &region
+	return a
+}
+
+// DisableRegionDetection skips querying the ECS and EC2 metadata endpoints for the
+// region, going straight to the fallback region
+func (a *Config) DisableRegionDetection() *Config {
+	regionMu.Lock()
+	a.noRegionDetection = true
+	regionMu.Unlock()
+	return a
+}
+
+// ResolveRegion returns the region service calls are made against, checking in order:
+// Config.Region, AWS_REGION, AWS_DEFAULT_REGION, the ECS task metadata endpoint, the
+// EC2 instance metadata service, and finally the fallback region. The detected region
+// is remembered so metadata is only queried once, also by concurrent callers, and the
+// fallback is only warned about once.
+func (a *Config) ResolveRegion() (string, error) {
+	if a.Region != "" {
+		return a.Region, nil
+	}
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if val, ok := os.LookupEnv(env); ok && val != "" {
+			return val, nil
+		}
+	}
+
+	regionMu.Lock()
+	defer regionMu.Unlock()
+
+	if a.detectedRegion != "" {
+		return a.detectedRegion, nil
+	}
+
+	if !a.noRegionDetection {
+		if region, err := ECSRegion(); err == nil {
+			a.detectedRegion = region
+			return region, nil
+		}
+		if region, err := a.EC2Region(); err == nil {
+			a.detectedRegion = region
+			return region, nil
+		}
+		// neither metadata endpoint is reachable, don't probe them again
+		a.noRegionDetection = true
+	}
+
+	fallback := DefaultFallbackRegion
+	if a.FallbackRegion != nil {
+		fallback = *a.FallbackRegion
+	}
+	if fallback == "" {
+		return "", errors.New("no region configured or detected and no fallback region set")
+	}
+
+	if !a.fallbackWarned {
+		a.warn("No region configured or detected, falling back to " + fallback)
+		a.fallbackWarned = true
+	}
+	return fallback, nil
+}
+
+// EC2Region returns the region of the EC2 instance from the instance metadata service
+func (a *Config) EC2Region() (string, error) {
+	client, err := a.MetadataClient()
+	if err != nil {
+		return "", err
+	}
+	return client.Region()
+}
+
+// ECSRegion returns the region of the ECS task from the task metadata endpoint, taken
+// from the region of the task ARN
+func ECSRegion() (string, error) {
+	task, err := GetECSTaskMetadata()
+	if err != nil {
+		return "", err
+	}
+	return task.Region()
+}
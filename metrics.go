@@ -0,0 +1,64 @@
+package awsx
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// Metrics receives measurements of the AWS calls made by awsx and of the discovery
+// cache. See the awsxprom package for a Prometheus implementation.
+type Metrics interface {
+	// ObserveCall is called once per API operation after all retries
+	ObserveCall(service, operation string, latency time.Duration, retries int, err error)
+	// ObserveThrottle is called for every attempt of an operation that was throttled
+	ObserveThrottle(service, operation string)
+	// ObserveCache is called for every discovery lookup when caching is enabled, with
+	// the kind of result cached such as "redis" or "aurora"
+	ObserveCache(kind string, hit bool)
+}
+
+// SetMetrics sets the Metrics receiving measurements of AWS calls and cache lookups.
+// It must be called before SetSession.
+func (a *Config) SetMetrics(m Metrics) *Config {
+	a.Metrics = m
+	return a
+}
+
+// installHandlers adds the awsx request handlers to a new session
+func (a *Config) installHandlers(sess *session.Session) {
+	a.installTracing(sess)
+	a.installRequestHooks(sess)
+
+	if a.Metrics != nil {
+		m := a.Metrics
+		sess.Handlers.Retry.PushBackNamed(request.NamedHandler{
+			Name: "awsx.metrics.throttle",
+			Fn: func(r *request.Request) {
+				if r.Error != nil && request.IsErrorThrottle(r.Error) {
+					m.ObserveThrottle(r.ClientInfo.ServiceName, r.Operation.Name)
+				}
+			},
+		})
+		sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+			Name: "awsx.metrics.call",
+			Fn: func(r *request.Request) {
+				m.ObserveCall(r.ClientInfo.ServiceName, r.Operation.Name, time.Since(r.Time), r.RetryCount, r.Error)
+			},
+		})
+	}
+}
+
+// observeCache reports a cache lookup for key to the Metrics
+func (a *Config) observeCache(key string, hit bool) {
+	if a.Metrics == nil {
+		return
+	}
+	kind := key
+	if i := strings.Index(key, ":"); i >= 0 {
+		kind = key[:i]
+	}
+	a.Metrics.ObserveCache(kind, hit)
+}
@@ -0,0 +1,7 @@
+package mocks
+
+// Call is a single recorded call to a mock
+type Call struct {
+	Method string
+	Args   []interface{}
+}
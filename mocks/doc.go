@@ -0,0 +1,12 @@
+// Package mocks provides mock implementations of the interfaces exported by awsx so
+// consumers can test code that depends on awsx discovery without mocking the AWS SDK.
+//
+// Each mock records its calls and delegates to an optional func field. Methods whose
+// func is not set return zero values:
+//
+//	r := &mocks.RedisResolver{
+//		GetRedisPrimaryEndpointFunc: func(cluster string) (*awsx.RedisEndpoints, error) {
+//			return &awsx.RedisEndpoints{Primary: &awsx.RedisEndpoint{Host: "localhost", Port: 6379}}, nil
+//		},
+//	}
+package mocks
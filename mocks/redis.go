@@ -0,0 +1,101 @@
+package mocks
+
+import (
+	"sync"
+
+	"github.com/routebyintuition/awsx"
+)
+
+// RedisResolver is a mock of awsx.RedisResolver
+type RedisResolver struct {
+	GetRedisAllEndpointsFunc    func(cluster string) (*awsx.RedisEndpoints, error)
+	GetRedisPrimaryEndpointFunc func(cluster string) (*awsx.RedisEndpoints, error)
+	GetRedisClusterEndpointFunc func(cluster string) (*awsx.RedisEndpoint, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ awsx.RedisResolver = (*RedisResolver)(nil)
+
+// GetRedisAllEndpoints calls GetRedisAllEndpointsFunc
+func (m *RedisResolver) GetRedisAllEndpoints(cluster string) (*awsx.RedisEndpoints, error) {
+	m.record("GetRedisAllEndpoints", cluster)
+	if m.GetRedisAllEndpointsFunc == nil {
+		return nil, nil
+	}
+	return m.GetRedisAllEndpointsFunc(cluster)
+}
+
+// GetRedisPrimaryEndpoint calls GetRedisPrimaryEndpointFunc
+func (m *RedisResolver) GetRedisPrimaryEndpoint(cluster string) (*awsx.RedisEndpoints, error) {
+	m.record("GetRedisPrimaryEndpoint", cluster)
+	if m.GetRedisPrimaryEndpointFunc == nil {
+		return nil, nil
+	}
+	return m.GetRedisPrimaryEndpointFunc(cluster)
+}
+
+// GetRedisClusterEndpoint calls GetRedisClusterEndpointFunc
+func (m *RedisResolver) GetRedisClusterEndpoint(cluster string) (*awsx.RedisEndpoint, error) {
+	m.record("GetRedisClusterEndpoint", cluster)
+	if m.GetRedisClusterEndpointFunc == nil {
+		return nil, nil
+	}
+	return m.GetRedisClusterEndpointFunc(cluster)
+}
+
+// Calls returns the calls made to the mock, in order
+func (m *RedisResolver) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *RedisResolver) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// RedisProvisioner is a mock of awsx.RedisProvisioner
+type RedisProvisioner struct {
+	SpinUpFromSnapshotFunc      func(snapshot string, opts *awsx.RestoreOptions) (*awsx.EphemeralCache, error)
+	ExportRedisSnapshotToS3Func func(snapshotName, bucket string, progress ...awsx.SnapshotProgressFunc) (string, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ awsx.RedisProvisioner = (*RedisProvisioner)(nil)
+
+// SpinUpFromSnapshot calls SpinUpFromSnapshotFunc
+func (m *RedisProvisioner) SpinUpFromSnapshot(snapshot string, opts *awsx.RestoreOptions) (*awsx.EphemeralCache, error) {
+	m.record("SpinUpFromSnapshot", snapshot, opts)
+	if m.SpinUpFromSnapshotFunc == nil {
+		return nil, nil
+	}
+	return m.SpinUpFromSnapshotFunc(snapshot, opts)
+}
+
+// ExportRedisSnapshotToS3 calls ExportRedisSnapshotToS3Func
+func (m *RedisProvisioner) ExportRedisSnapshotToS3(snapshotName, bucket string, progress ...awsx.SnapshotProgressFunc) (string, error) {
+	m.record("ExportRedisSnapshotToS3", snapshotName, bucket)
+	if m.ExportRedisSnapshotToS3Func == nil {
+		return "", nil
+	}
+	return m.ExportRedisSnapshotToS3Func(snapshotName, bucket, progress...)
+}
+
+// Calls returns the calls made to the mock, in order
+func (m *RedisProvisioner) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *RedisProvisioner) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
@@ -0,0 +1,60 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// IPNetwork returns the network ("ip4" or "ip6") the endpoints resolve to, based on the
+// IP discovery setting of the cluster
+func (res *RedisEndpoints) IPNetwork() string {
+	if strings.EqualFold(res.IPDiscovery, "ipv6") || strings.EqualFold(res.NetworkType, "ipv6") {
+		return "ip6"
+	}
+	return "ip4"
+}
+
+// IPNetwork returns the network to resolve the endpoints with. Dual-stack clusters
+// resolve to both, so "ip" is returned for them.
+func (aes *AuroraEndpoints) IPNetwork() string {
+	if strings.EqualFold(aes.NetworkType, "DUAL") {
+		return "ip"
+	}
+	return "ip4"
+}
+
+// ResolveHost looks up the addresses of an endpoint host on the network: "ip4" for A
+// records, "ip6" for AAAA records, or "ip" for both. The returned addresses can be
+// joined with a port by net.JoinHostPort, which brackets IPv6 literals.
+func ResolveHost(ctx context.Context, host, network string) ([]string, error) {
+	if host == "" {
+		return nil, errors.New("no host provided")
+	}
+	if network == "" {
+		network = "ip"
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+
+	return addrs, nil
+}
+
+// Resolve looks up the addresses of the endpoint on the network, see ResolveHost
+func (re *RedisEndpoint) Resolve(ctx context.Context, network string) ([]string, error) {
+	return ResolveHost(ctx, re.Host, network)
+}
+
+// Resolve looks up the addresses of the endpoint on the network, see ResolveHost
+func (ae *AuroraEndpoint) Resolve(ctx context.Context, network string) ([]string, error) {
+	return ResolveHost(ctx, ae.Host, network)
+}
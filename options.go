@@ -0,0 +1,223 @@
+package awsx
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Option configures a Config built by New
+type Option func(o *options) error
+
+// options collects the settings of New that must be applied in a fixed order
+type options struct {
+	config    *Config
+	providers []string
+	hops      []RoleHop
+}
+
+// New builds a Config from functional options, as an alternative to the fluent builder.
+// The credential chain is built after every option has been applied, defaulting to all
+// providers, followed by any roles to assume, and the result is checked with Validate so
+// misconfiguration is reported at construction time:
+//
+//	a, err := awsx.New(awsx.WithRegion("us-west-2"), awsx.WithCacheTTL(time.Minute))
+func New(opts ...Option) (*Config, error) {
+	o := &options{config: NewAWS()}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	a := o.config
+	if err := a.withProviderNames(o.providers); err != nil {
+		return nil, err
+	}
+	if len(o.hops) > 0 {
+		if err := a.roleHops(o.hops...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// WithRegion sets the region
+func WithRegion(region string) Option {
+	return func(o *options) error {
+		if region == "" {
+			return errors.New("empty region")
+		}
+		o.config.Region = region
+		return nil
+	}
+}
+
+// WithProfile sets the shared credentials file profile
+func WithProfile(profile string) Option {
+	return func(o *options) error {
+		if profile == "" {
+			return errors.New("empty profile")
+		}
+		o.config.Profile = profile
+		return nil
+	}
+}
+
+// WithCredentialsFile sets the shared credentials file
+func WithCredentialsFile(path string) Option {
+	return func(o *options) error {
+		o.config.CredFile = path
+		return nil
+	}
+}
+
+// WithStaticCredentials sets an access key, secret key and optional session token
+func WithStaticCredentials(accessKey, secretKey, sessionToken string) Option {
+	return func(o *options) error {
+		if accessKey == "" || secretKey == "" {
+			return errors.New("access key and secret key must both be set")
+		}
+		o.config.AccessKey = accessKey
+		o.config.SecretKey = secretKey
+		o.config.SessionToken = sessionToken
+		return nil
+	}
+}
+
+// WithProviders sets the credential providers in chain order: static, env, file,
+// web_identity and instance. All providers are used if this option is not given.
+func WithProviders(names ...string) Option {
+	return func(o *options) error {
+		o.providers = append(o.providers, names...)
+		return nil
+	}
+}
+
+// WithAssumeRole assumes the roles in turn with the credentials of the chain
+func WithAssumeRole(hops ...RoleHop) Option {
+	return func(o *options) error {
+		if len(hops) == 0 {
+			return errors.New("no roles to assume")
+		}
+		for i, hop := range hops {
+			if hop.ARN == "" {
+				return errors.New("no role arn provided for role hop " + strconv.Itoa(len(o.hops)+i+1))
+			}
+		}
+		o.hops = append(o.hops, hops...)
+		return nil
+	}
+}
+
+// WithEndpoint sets the endpoint for all services
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) error {
+		o.config.Endpoint = endpoint
+		return nil
+	}
+}
+
+// WithServiceEndpoint sets the endpoint for one service by endpoint ID
+func WithServiceEndpoint(service, endpoint string) Option {
+	return func(o *options) error {
+		if service == "" || endpoint == "" {
+			return errors.New("service and endpoint must both be set")
+		}
+		o.config.SetServiceEndpoint(service, endpoint)
+		return nil
+	}
+}
+
+// WithCacheTTL enables caching of discovery results
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) error {
+		o.config.SetCacheTTL(ttl)
+		return nil
+	}
+}
+
+// WithEndpointStore persists endpoint discovery results to a file, see SetEndpointStore
+func WithEndpointStore(path string, maxAge time.Duration) Option {
+	return func(o *options) error {
+		if path == "" || maxAge <= 0 {
+			return errors.New("endpoint store path and max age must both be set")
+		}
+		o.config.SetEndpointStore(path, maxAge)
+		return nil
+	}
+}
+
+// WithStalePolicy sets what discovery returns when AWS calls fail, see SetStalePolicy
+func WithStalePolicy(policy StalePolicy) Option {
+	return func(o *options) error {
+		o.config.SetStalePolicy(policy)
+		return nil
+	}
+}
+
+// WithTopologyHistory keeps the last size topology changes, see SetTopologyHistory
+func WithTopologyHistory(size int) Option {
+	return func(o *options) error {
+		if size <= 0 {
+			return errors.New("topology history size must be positive")
+		}
+		o.config.SetTopologyHistory(size)
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the service clients
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) error {
+		o.config.SetRetryPolicy(policy)
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for service calls
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) error {
+		o.config.SetHTTPClient(client)
+		return nil
+	}
+}
+
+// WithMetadataOptions sets the options of the EC2 instance metadata client
+func WithMetadataOptions(opts MetadataOptions) Option {
+	return func(o *options) error {
+		o.config.SetMetadataOptions(opts)
+		return nil
+	}
+}
+
+// WithClock sets the Clock used by pollers, waiters and backoff
+func WithClock(clock Clock) Option {
+	return func(o *options) error {
+		o.config.SetClock(clock)
+		return nil
+	}
+}
+
+// WithLogger sets the logger receiving warnings, see SetLogger
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) error {
+		o.config.SetLogger(l)
+		return nil
+	}
+}
+
+// WithPanic makes errors panic the application, see EnablePanic
+func WithPanic() Option {
+	return func(o *options) error {
+		o.config.EnablePanic()
+		return nil
+	}
+}
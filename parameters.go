@@ -0,0 +1,198 @@
+package awsx
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// Parameter is a single parameter of an RDS or ElastiCache parameter group
+type Parameter struct {
+	Name          string `json:"name" yaml:"name"`
+	Value         string `json:"value,omitempty" yaml:"value,omitempty"` // empty when the engine default is used
+	Source        string `json:"source,omitempty" yaml:"source,omitempty"`
+	ApplyType     string `json:"apply_type,omitempty" yaml:"apply_type,omitempty"` // static or dynamic
+	DataType      string `json:"data_type,omitempty" yaml:"data_type,omitempty"`
+	AllowedValues string `json:"allowed_values,omitempty" yaml:"allowed_values,omitempty"`
+	Modifiable    bool   `json:"modifiable" yaml:"modifiable"`
+}
+
+// Parameters are the parameters of a parameter group keyed by name
+type Parameters map[string]*Parameter
+
+// Value returns the value of the parameter name and whether the group has the parameter
+func (p Parameters) Value(name string) (string, bool) {
+	param, ok := p[name]
+	if !ok {
+		return "", false
+	}
+	return param.Value, true
+}
+
+// Require returns an error listing every parameter whose value differs from expected,
+// so that services can refuse to start against a misconfigured cluster:
+//
+//	params, _ := a.GetECParameters("my-params")
+//	err := params.Require(map[string]string{"cluster-enabled": "yes"})
+func (p Parameters) Require(expected map[string]string) error {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	wrong := make([]string, 0)
+	for _, name := range names {
+		value, ok := p.Value(name)
+		if !ok {
+			wrong = append(wrong, name+" not found")
+		} else if value != expected[name] {
+			wrong = append(wrong, name+" is "+strconv.Quote(value)+", expected "+strconv.Quote(expected[name]))
+		}
+	}
+	if len(wrong) == 0 {
+		return nil
+	}
+
+	return errors.New("unexpected parameters: " + strings.Join(wrong, "; "))
+}
+
+// ParameterFilter limits the parameters returned by GetRDSParameters
+type ParameterFilter struct {
+	Source string   // optional: user, system or engine-default
+	Names  []string // optional: only return these parameters
+}
+
+// GetRDSParameters returns the parameters of the DB parameter group, iterating all pages
+// of DescribeDBParameters. A nil filter returns every parameter.
+func (a *Config) GetRDSParameters(groupName string, filter *ParameterFilter) (Parameters, error) {
+	if groupName == "" {
+		return nil, errors.New("no parameter group name provided")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeDBParametersInput{
+		DBParameterGroupName: aws.String(groupName),
+		MaxRecords:           aws.Int64(listPageSize),
+	}
+	if filter != nil && filter.Source != "" {
+		input.Source = aws.String(filter.Source)
+	}
+
+	params := Parameters{}
+	err := a.Service.Rds.DescribeDBParametersPages(input, func(page *rds.DescribeDBParametersOutput, lastPage bool) bool {
+		for _, p := range page.Parameters {
+			params.addRDS(p, filter)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// GetRDSClusterParameters returns the parameters of the DB cluster parameter group of an
+// Aurora cluster, iterating all pages of DescribeDBClusterParameters. A nil filter returns
+// every parameter.
+func (a *Config) GetRDSClusterParameters(groupName string, filter *ParameterFilter) (Parameters, error) {
+	if groupName == "" {
+		return nil, errors.New("no parameter group name provided")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeDBClusterParametersInput{
+		DBClusterParameterGroupName: aws.String(groupName),
+		MaxRecords:                  aws.Int64(listPageSize),
+	}
+	if filter != nil && filter.Source != "" {
+		input.Source = aws.String(filter.Source)
+	}
+
+	params := Parameters{}
+	err := a.Service.Rds.DescribeDBClusterParametersPages(input, func(page *rds.DescribeDBClusterParametersOutput, lastPage bool) bool {
+		for _, p := range page.Parameters {
+			params.addRDS(p, filter)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// GetECParameters returns every parameter of the cache parameter group, iterating all
+// pages of DescribeCacheParameters. See GetECParameterGroupName for the group of a cluster.
+func (a *Config) GetECParameters(groupName string) (Parameters, error) {
+	if groupName == "" {
+		return nil, errors.New("no parameter group name provided")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeCacheParametersInput{
+		CacheParameterGroupName: aws.String(groupName),
+		MaxRecords:              aws.Int64(listPageSize),
+	}
+
+	params := Parameters{}
+	err := a.Service.Ec.DescribeCacheParametersPages(input, func(page *elasticache.DescribeCacheParametersOutput, lastPage bool) bool {
+		for _, p := range page.Parameters {
+			name := aws.StringValue(p.ParameterName)
+			params[name] = &Parameter{
+				Name:          name,
+				Value:         aws.StringValue(p.ParameterValue),
+				Source:        aws.StringValue(p.Source),
+				ApplyType:     aws.StringValue(p.ChangeType),
+				DataType:      aws.StringValue(p.DataType),
+				AllowedValues: aws.StringValue(p.AllowedValues),
+				Modifiable:    aws.BoolValue(p.IsModifiable),
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// addRDS adds an RDS parameter if it passes the name filter
+func (p Parameters) addRDS(param *rds.Parameter, filter *ParameterFilter) {
+	name := aws.StringValue(param.ParameterName)
+	if filter != nil && len(filter.Names) > 0 {
+		found := false
+		for _, n := range filter.Names {
+			found = found || n == name
+		}
+		if !found {
+			return
+		}
+	}
+	p[name] = &Parameter{
+		Name:          name,
+		Value:         aws.StringValue(param.ParameterValue),
+		Source:        aws.StringValue(param.Source),
+		ApplyType:     aws.StringValue(param.ApplyType),
+		DataType:      aws.StringValue(param.DataType),
+		AllowedValues: aws.StringValue(param.AllowedValues),
+		Modifiable:    aws.BoolValue(param.IsModifiable),
+	}
+}
@@ -0,0 +1,72 @@
+package awsx
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// partitionPrefixes maps region prefixes to partitions, for regions newer than the
+// endpoints model of the SDK. Longer prefixes come first.
+var partitionPrefixes = []struct {
+	prefix, partition, dnsSuffix string
+}{
+	{"us-gov-", endpoints.AwsUsGovPartitionID, "amazonaws.com"},
+	{"cn-", endpoints.AwsCnPartitionID, "amazonaws.com.cn"},
+	{"us-isob-", endpoints.AwsIsoBPartitionID, "sc2s.sgov.gov"},
+	{"us-iso-", endpoints.AwsIsoPartitionID, "c2s.ic.gov"},
+}
+
+// PartitionForRegion returns the partition of the region: aws, aws-us-gov, aws-cn,
+// aws-iso or aws-iso-b. Unknown regions are taken to be in the aws partition.
+func PartitionForRegion(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.ID()
+	}
+	for _, p := range partitionPrefixes {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+	return endpoints.AwsPartitionID
+}
+
+// DNSSuffixForRegion returns the DNS suffix of the service endpoints of the region, e.g.
+// amazonaws.com, or amazonaws.com.cn in the China regions
+func DNSSuffixForRegion(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.DNSSuffix()
+	}
+	for _, p := range partitionPrefixes {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.dnsSuffix
+		}
+	}
+	return "amazonaws.com"
+}
+
+// DNSSuffix returns the DNS suffix of the service endpoints of the region of the Config
+func (a *Config) DNSSuffix() string {
+	return DNSSuffixForRegion(a.GetRegion())
+}
+
+// ServiceAvailable reports whether the service, by endpoint ID (e.g. elasticache.EndpointsID),
+// is available in the region of the Config according to the endpoints model of the SDK,
+// e.g. to skip discovery of services missing from GovCloud or China regions. Regions the
+// SDK does not know are assumed to have every service.
+func (a *Config) ServiceAvailable(service string) bool {
+	region := a.GetRegion()
+	p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if !ok {
+		return true
+	}
+	if _, known := p.Regions()[region]; !known {
+		return true
+	}
+	regions, ok := endpoints.RegionsForService(endpoints.DefaultPartitions(), p.ID(), service)
+	if !ok {
+		return false
+	}
+	_, ok = regions[region]
+	return ok
+}
@@ -0,0 +1,185 @@
+package awsx
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pi"
+	"github.com/aws/aws-sdk-go/service/pi/piiface"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+const (
+	// dbLoadMetric is the Performance Insights average active sessions metric
+	dbLoadMetric = "db.load.avg"
+	// topSQLLimit is the number of statements returned by GetRDSTopSQL
+	topSQLLimit = 10
+)
+
+// TopSQL is a statement contributing to the database load of an instance
+type TopSQL struct {
+	ID        string  `json:"id" yaml:"id"`
+	Statement string  `json:"statement" yaml:"statement"` // the tokenized statement, with literals replaced
+	Load      float64 `json:"load" yaml:"load"`           // average active sessions over the window
+}
+
+// GetPIClient returns a client for use with Performance Insights
+func (a *Config) GetPIClient() piiface.PIAPI {
+	return a.Service.Pi
+}
+
+// SetPIClient sets a client for use with Performance Insights
+func (a *Config) SetPIClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Pi = pi.New(a.ClientConfig(pi.EndpointsID))
+
+	return a
+}
+
+// WithPIClient sets the client used for Performance Insights calls, such as a mock from
+// the awsxmock package
+func (a *Config) WithPIClient(client piiface.PIAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Pi = client
+
+	return a
+}
+
+// GetRDSTopSQL returns the statements with the highest database load on the DB instance
+// over the last window, highest first, as reported by Performance Insights. Use the
+// WriterInstance of GetAuroraEndpoints to see what is hammering the writer.
+func (a *Config) GetRDSTopSQL(instanceID string, window time.Duration) ([]*TopSQL, error) {
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+	resourceID, err := a.piResourceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Service.Pi == nil {
+		a.SetPIClient()
+	}
+
+	end := a.clock().Now()
+	result, err := a.Service.Pi.DescribeDimensionKeys(&pi.DescribeDimensionKeysInput{
+		ServiceType:     aws.String(pi.ServiceTypeRds),
+		Identifier:      aws.String(resourceID),
+		Metric:          aws.String(dbLoadMetric),
+		StartTime:       aws.Time(end.Add(-window)),
+		EndTime:         aws.Time(end),
+		PeriodInSeconds: aws.Int64(piPeriod(window)),
+		GroupBy: &pi.DimensionGroup{
+			Group: aws.String("db.sql_tokenized"),
+			Limit: aws.Int64(topSQLLimit),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	top := make([]*TopSQL, 0, len(result.Keys))
+	for _, k := range result.Keys {
+		top = append(top, &TopSQL{
+			ID:        aws.StringValue(k.Dimensions["db.sql_tokenized.id"]),
+			Statement: aws.StringValue(k.Dimensions["db.sql_tokenized.statement"]),
+			Load:      aws.Float64Value(k.Total),
+		})
+	}
+	return top, nil
+}
+
+// GetRDSDBLoad returns the database load of the DB instance over the last window, in
+// average active sessions, as reported by Performance Insights
+func (a *Config) GetRDSDBLoad(instanceID string, window time.Duration) (*MetricSeries, error) {
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+	resourceID, err := a.piResourceID(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Service.Pi == nil {
+		a.SetPIClient()
+	}
+
+	period := piPeriod(window)
+	series := &MetricSeries{Metric: dbLoadMetric, ID: instanceID, Period: time.Duration(period) * time.Second, Points: make([]MetricPoint, 0)}
+
+	end := a.clock().Now()
+	input := &pi.GetResourceMetricsInput{
+		ServiceType:     aws.String(pi.ServiceTypeRds),
+		Identifier:      aws.String(resourceID),
+		MetricQueries:   []*pi.MetricQuery{{Metric: aws.String(dbLoadMetric)}},
+		StartTime:       aws.Time(end.Add(-window)),
+		EndTime:         aws.Time(end),
+		PeriodInSeconds: aws.Int64(period),
+	}
+	for {
+		result, err := a.Service.Pi.GetResourceMetrics(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range result.MetricList {
+			for _, p := range m.DataPoints {
+				series.Points = append(series.Points, MetricPoint{Time: aws.TimeValue(p.Timestamp), Value: aws.Float64Value(p.Value)})
+			}
+		}
+
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	return series, nil
+}
+
+// piResourceID returns the DbiResourceId Performance Insights identifies the DB instance
+// by, or an error if Performance Insights is not enabled on it
+func (a *Config) piResourceID(instanceID string) (string, error) {
+	if instanceID == "" {
+		return "", errors.New("no DB instance identifier provided")
+	}
+
+	id, err := a.cached("pi-resource:"+instanceID, func() (interface{}, error) {
+		if a.Service.Rds == nil {
+			a.SetRDSClient()
+		}
+
+		result, err := a.Service.Rds.DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(instanceID)})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.DBInstances) == 0 {
+			return nil, errors.New("no DB instance found for " + instanceID)
+		}
+		if !aws.BoolValue(result.DBInstances[0].PerformanceInsightsEnabled) {
+			return nil, errors.New("Performance Insights is not enabled on " + instanceID)
+		}
+		return aws.StringValue(result.DBInstances[0].DbiResourceId), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id.(string), nil
+}
+
+// piPeriod returns the Performance Insights datapoint period in seconds for a window,
+// keeping the number of datapoints reasonable
+func piPeriod(window time.Duration) int64 {
+	switch {
+	case window <= 24*time.Hour:
+		return 60
+	case window <= 7*24*time.Hour:
+		return 300
+	case window <= 90*24*time.Hour:
+		return 3600
+	}
+	return 86400
+}
@@ -0,0 +1,45 @@
+package awsx
+
+import (
+	"time"
+)
+
+// PresetWebServiceEKS returns a Config for long running services on EKS. Credentials come
+// from the environment, then the IRSA web identity token, then the node instance role. The
+// credential chain is validated when the session is set and the region must be set or
+// detected rather than falling back, so misconfigured pods fail at startup. Discovery
+// results are cached for 30 seconds.
+func PresetWebServiceEKS() *Config {
+	a := NewAWS()
+	a.SetMetadataOptions(MetadataOptions{Timeout: time.Second})
+	a.SetFallbackRegion("")
+	a.SetRetryPolicy(DefaultRetryPolicy())
+	a.SetCacheTTL(30 * time.Second)
+	a.ValidateCredentials()
+
+	a.WithEnv().WithWebIdentity()
+	return a.WithInstanceRole()
+}
+
+// PresetBatchOnEC2 returns a Config for batch jobs on EC2 using the instance role. Jobs
+// tolerate slower metadata and API responses with more retries, and cache discovery for
+// 5 minutes since topology rarely changes within a run.
+func PresetBatchOnEC2() *Config {
+	a := NewAWS()
+	a.SetMetadataOptions(MetadataOptions{Timeout: 5 * time.Second, Retries: 3})
+	a.SetMaxRetries(10)
+	a.SetCacheTTL(5 * time.Minute)
+
+	return a.WithInstanceRole()
+}
+
+// PresetLocalDev returns a Config for development machines using environment and shared
+// file credentials. Metadata region detection is skipped to avoid timeouts off EC2, and
+// caching is disabled so changes to clusters are seen immediately.
+func PresetLocalDev() *Config {
+	a := NewAWS()
+	a.DisableRegionDetection()
+	a.SetCacheTTL(0)
+
+	return a.WithEnv().WithFile()
+}
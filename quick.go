@@ -0,0 +1,68 @@
+package awsx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// quickCacheTTL is the discovery cache duration used by QuickConfig
+const quickCacheTTL = time.Minute
+
+// quickConfig is the QuickConfig shared by QuickRedis and QuickAurora so their results
+// are cached across calls
+var quickConfig struct {
+	once   sync.Once
+	config *Config
+}
+
+// QuickConfig returns a Config with the default credential chain of environment,
+// shared credentials file and instance role credentials, caching enabled, and an
+// initialized session. It is meant for small tools that don't need the full builder.
+func QuickConfig() *Config {
+	a := NewAWS().WithEnv().WithFile()
+	a.WithInstanceRole()
+	a.SetCacheTTL(quickCacheTTL)
+	a.SetSession()
+
+	return a
+}
+
+// quick returns the QuickConfig shared by the Quick functions, building it on first use
+func quick(ctx context.Context) (*Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	quickConfig.once.Do(func() {
+		quickConfig.config = QuickConfig()
+	})
+	return quickConfig.config.WithContext(ctx), nil
+}
+
+// QuickRedis discovers the endpoints of an ElastiCache Redis cluster with a QuickConfig
+// shared by every call. The AWS calls are cancelled when ctx is done.
+func QuickRedis(ctx context.Context, cluster string) (*RedisEndpoints, error) {
+	a, err := quick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.GetRedisPrimaryEndpoint(cluster)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return res, err
+}
+
+// QuickAurora discovers the endpoints of an Aurora DB cluster with a QuickConfig shared
+// by every call. The AWS calls are cancelled when ctx is done.
+func QuickAurora(ctx context.Context, cluster string) (*AuroraEndpoints, error) {
+	a, err := quick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aes, err := a.GetAuroraEndpoints(cluster)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return aes, err
+}
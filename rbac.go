@@ -0,0 +1,151 @@
+package awsx
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// ECUser is an ElastiCache RBAC user
+type ECUser struct {
+	ID                 string   `json:"id" yaml:"id"`
+	Name               string   `json:"name" yaml:"name"` // the username sent with AUTH
+	ARN                string   `json:"arn" yaml:"arn"`
+	Status             string   `json:"status" yaml:"status"`
+	Engine             string   `json:"engine" yaml:"engine"`
+	AccessString       string   `json:"access_string" yaml:"access_string"`
+	AuthenticationType string   `json:"authentication_type" yaml:"authentication_type"` // password, no-password or iam
+	UserGroups         []string `json:"user_groups" yaml:"user_groups"`
+}
+
+// ECUserGroup is an ElastiCache RBAC user group with the users it contains and the
+// replication groups it is attached to
+type ECUserGroup struct {
+	ID                string   `json:"id" yaml:"id"`
+	ARN               string   `json:"arn" yaml:"arn"`
+	Status            string   `json:"status" yaml:"status"`
+	Engine            string   `json:"engine" yaml:"engine"`
+	UserIDs           []string `json:"user_ids" yaml:"user_ids"`
+	ReplicationGroups []string `json:"replication_groups" yaml:"replication_groups"`
+}
+
+// ListECUsers returns every ElastiCache RBAC user in the region
+func (a *Config) ListECUsers() ([]*ECUser, error) {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	list := make([]*ECUser, 0)
+	input := &elasticache.DescribeUsersInput{MaxRecords: aws.Int64(listPageSize)}
+	err := a.Service.Ec.DescribeUsersPages(input, func(page *elasticache.DescribeUsersOutput, lastPage bool) bool {
+		for _, u := range page.Users {
+			user := &ECUser{
+				ID:           aws.StringValue(u.UserId),
+				Name:         aws.StringValue(u.UserName),
+				ARN:          aws.StringValue(u.ARN),
+				Status:       aws.StringValue(u.Status),
+				Engine:       aws.StringValue(u.Engine),
+				AccessString: aws.StringValue(u.AccessString),
+				UserGroups:   aws.StringValueSlice(u.UserGroupIds),
+			}
+			if u.Authentication != nil {
+				user.AuthenticationType = aws.StringValue(u.Authentication.Type)
+			}
+			list = append(list, user)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DescribeECUserGroups returns the ElastiCache RBAC user groups with the given IDs, or
+// every user group in the region when no IDs are provided
+func (a *Config) DescribeECUserGroups(ids ...string) ([]*ECUserGroup, error) {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	want := map[string]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	list := make([]*ECUserGroup, 0)
+	input := &elasticache.DescribeUserGroupsInput{MaxRecords: aws.Int64(listPageSize)}
+	if len(ids) == 1 {
+		input.UserGroupId = aws.String(ids[0])
+	}
+	err := a.Service.Ec.DescribeUserGroupsPages(input, func(page *elasticache.DescribeUserGroupsOutput, lastPage bool) bool {
+		for _, g := range page.UserGroups {
+			if len(want) > 0 && !want[aws.StringValue(g.UserGroupId)] {
+				continue
+			}
+			list = append(list, &ECUserGroup{
+				ID:                aws.StringValue(g.UserGroupId),
+				ARN:               aws.StringValue(g.ARN),
+				Status:            aws.StringValue(g.Status),
+				Engine:            aws.StringValue(g.Engine),
+				UserIDs:           aws.StringValueSlice(g.UserIds),
+				ReplicationGroups: aws.StringValueSlice(g.ReplicationGroups),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// VerifyECUser checks that the RBAC user, by user name or ID, is active and is a member
+// of a user group attached to the replication group, so a service can fail fast before
+// authenticating with a user the cluster does not know
+func (a *Config) VerifyECUser(user, replicationGroup string) error {
+	if user == "" || replicationGroup == "" {
+		return errors.New("must provide a user and replication group")
+	}
+
+	users, err := a.ListECUsers()
+	if err != nil {
+		return err
+	}
+
+	var u *ECUser
+	for _, candidate := range users {
+		if candidate.Name == user || candidate.ID == user {
+			u = candidate
+			break
+		}
+	}
+	if u == nil {
+		return errors.New("no elasticache user found matching " + user)
+	}
+	if u.Status != "active" {
+		return errors.New("elasticache user " + user + " is " + u.Status)
+	}
+	if len(u.UserGroups) == 0 {
+		return errors.New("elasticache user " + user + " is not a member of any user group")
+	}
+
+	groups, err := a.DescribeECUserGroups(u.UserGroups...)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		for _, rg := range g.ReplicationGroups {
+			if rg == replicationGroup {
+				return nil
+			}
+		}
+	}
+
+	return errors.New("elasticache user " + user + " is not attached to " + replicationGroup +
+		" through user groups " + strings.Join(u.UserGroups, ", "))
+}
@@ -0,0 +1,385 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// GetRDSClient returns a client for use with AWS RDS
+func (a *Config) GetRDSClient() rdsiface.RDSAPI {
+	return a.Service.Rds
+}
+
+// SetRDSClient sets a client for use with AWS RDS
+func (a *Config) SetRDSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Rds = rds.New(a.ClientConfig(rds.EndpointsID))
+
+	return a
+}
+
+// WithRDSClient sets the client used for AWS RDS calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithRDSClient(client rdsiface.RDSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Rds = client
+
+	return a
+}
+
+// AuroraEndpoints provides the writer and reader endpoints of an Aurora DB cluster along
+// with the endpoint of each reader instance
+type AuroraEndpoints struct {
+	Cluster        string            `json:"cluster" yaml:"cluster"`
+	Engine         string            `json:"engine" yaml:"engine"`
+	Writer         *AuroraEndpoint   `json:"writer,omitempty" yaml:"writer,omitempty"`
+	Reader         *AuroraEndpoint   `json:"reader,omitempty" yaml:"reader,omitempty"`
+	WriterInstance *AuroraEndpoint   `json:"writer_instance,omitempty" yaml:"writer_instance,omitempty"`
+	ReadEndpoints  []*AuroraEndpoint `json:"read_endpoints" yaml:"read_endpoints"`
+	ReadReplicas   bool              `json:"read_replicas" yaml:"read_replicas"`
+	NetworkType    string            `json:"network_type,omitempty" yaml:"network_type,omitempty"` // IPV4 or DUAL
+	// BlueGreen is set by GetBlueGreenEndpoints to the deployment the endpoints were
+	// resolved through
+	BlueGreen *BlueGreenDeployment `json:"blue_green,omitempty" yaml:"blue_green,omitempty"`
+
+	ARN          string                `json:"arn,omitempty" yaml:"arn,omitempty"`
+	ServerlessV2 *ServerlessV2Capacity `json:"serverless_v2,omitempty" yaml:"serverless_v2,omitempty"` // nil unless Serverless v2 scaling is configured
+	DataAPI      bool                  `json:"data_api" yaml:"data_api"`                               // the RDS Data API (HTTP endpoint) is enabled
+
+	// Staleness is the age of an earlier result served after discovery failed, zero for
+	// a fresh result, see StalePolicy
+	Staleness time.Duration `json:"staleness,omitempty" yaml:"staleness,omitempty"`
+}
+
+// ServerlessV2Capacity is the Aurora capacity unit (ACU) range of a Serverless v2 cluster
+type ServerlessV2Capacity struct {
+	MinACU float64 `json:"min_acu" yaml:"min_acu"`
+	MaxACU float64 `json:"max_acu" yaml:"max_acu"`
+}
+
+// AuroraEndpoint provides the structure of each endpoint entry
+type AuroraEndpoint struct {
+	Host     string `json:"host" yaml:"host"`
+	Port     int    `json:"port" yaml:"port"`
+	Instance string `json:"instance,omitempty" yaml:"instance,omitempty"`
+	// ReplicaLag is the latest CloudWatch replica lag of a reader instance, nil when
+	// unknown; see GetAuroraEndpointsWithLag
+	ReplicaLag *time.Duration `json:"replica_lag,omitempty" yaml:"replica_lag,omitempty"`
+	// CACertificate is the CA the instance certificate is signed by, e.g. rds-ca-rsa2048-g1
+	CACertificate string `json:"ca_certificate,omitempty" yaml:"ca_certificate,omitempty"`
+}
+
+// String provides the string representation of the host and port
+func (ae *AuroraEndpoint) String() string {
+	return net.JoinHostPort(ae.Host, ae.PortString())
+}
+
+// PortString returns the port as a string, the form it had before Port was an int
+func (ae *AuroraEndpoint) PortString() string {
+	return strconv.Itoa(ae.Port)
+}
+
+// WriterString provides the host and port of the cluster writer endpoint
+func (aes *AuroraEndpoints) WriterString() string {
+	return aes.Writer.String()
+}
+
+// ReaderString provides the host and port of the cluster reader endpoint
+func (aes *AuroraEndpoints) ReaderString() string {
+	return aes.Reader.String()
+}
+
+// Readers returns the host and port of each reader instance in the cluster
+func (aes *AuroraEndpoints) Readers() []string {
+	str := make([]string, 0, len(aes.ReadEndpoints))
+	for _, v := range aes.ReadEndpoints {
+		str = append(str, v.String())
+	}
+	return str
+}
+
+// String provides the string representation of all endpoints in JSON format
+func (aes *AuroraEndpoints) String() string {
+	jsonByte, _ := json.Marshal(aes)
+	return string(jsonByte)
+}
+
+// GetRDSClusterDetails provides the describe call for the identified DB cluster
+func (a *Config) GetRDSClusterDetails(cluster string) (*rds.DescribeDBClustersOutput, error) {
+	return a.getRDSClusterDetails(a.context(), cluster)
+}
+
+// getRDSClusterDetails is GetRDSClusterDetails with the context of the call
+func (a *Config) getRDSClusterDetails(ctx context.Context, cluster string) (*rds.DescribeDBClustersOutput, error) {
+	if cluster == "" {
+		if a.panicOnErr {
+			panic("panicOnErr enabled, must provide a cluster string to (a *Config) GetRDSClusterDetails(cluster string)")
+		}
+		return nil, errors.New("did not provide a cluster name for the RDS describe call")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(cluster),
+	}
+
+	result, err := a.Service.Rds.DescribeDBClustersWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetRDSClusterInstances returns the DB instances that are members of the DB cluster
+func (a *Config) GetRDSClusterInstances(cluster string) ([]*rds.DBInstance, error) {
+	return a.getRDSClusterInstances(a.context(), cluster)
+}
+
+// getRDSClusterInstances is GetRDSClusterInstances with the context of the call
+func (a *Config) getRDSClusterInstances(ctx context.Context, cluster string) ([]*rds.DBInstance, error) {
+	if cluster == "" {
+		return nil, errors.New("no cluster name provided")
+	}
+
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeDBInstancesInput{
+		Filters: []*rds.Filter{
+			{Name: aws.String("db-cluster-id"), Values: aws.StringSlice([]string{cluster})},
+		},
+	}
+
+	instances := make([]*rds.DBInstance, 0)
+	err := a.Service.Rds.DescribeDBInstancesPagesWithContext(ctx, input, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		instances = append(instances, page.DBInstances...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// GetAuroraEndpoints returns the writer and reader endpoints of an Aurora DB cluster
+// along with the instance endpoint of the writer and each reader
+func (a *Config) GetAuroraEndpoints(cluster string) (*AuroraEndpoints, error) {
+	aes, err := a.traced("aurora", cluster, func(ctx context.Context) (interface{}, error) {
+		return a.cached("aurora:"+cluster, func() (interface{}, error) {
+			return a.getAuroraEndpoints(ctx, cluster)
+		})
+	})
+	if aes == nil {
+		return nil, err
+	}
+
+	return aes.(*AuroraEndpoints), err
+}
+
+// getAuroraEndpoints performs the uncached discovery for GetAuroraEndpoints
+func (a *Config) getAuroraEndpoints(ctx context.Context, cluster string) (*AuroraEndpoints, error) {
+	if cluster == "" {
+		return nil, errors.New("no cluster name provided")
+	}
+
+	result, err := a.getRDSClusterDetails(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.DBClusters) == 0 {
+		return nil, errors.New("no db cluster associated with this cluster name")
+	}
+
+	instances, err := a.getRDSClusterInstances(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return auroraEndpointsFromCluster(cluster, result.DBClusters[0], instances), nil
+}
+
+// auroraEndpointsFromCluster builds the AuroraEndpoints of a described DB cluster and its
+// instances
+func auroraEndpointsFromCluster(cluster string, c *rds.DBCluster, instances []*rds.DBInstance) *AuroraEndpoints {
+	port := int(aws.Int64Value(c.Port))
+	aes := &AuroraEndpoints{
+		Cluster:       cluster,
+		Engine:        aws.StringValue(c.Engine),
+		Writer:        &AuroraEndpoint{Host: aws.StringValue(c.Endpoint), Port: port},
+		Reader:        &AuroraEndpoint{Host: aws.StringValue(c.ReaderEndpoint), Port: port},
+		ReadEndpoints: make([]*AuroraEndpoint, 0),
+		NetworkType:   aws.StringValue(c.NetworkType),
+		ARN:           aws.StringValue(c.DBClusterArn),
+		DataAPI:       aws.BoolValue(c.HttpEndpointEnabled),
+	}
+	if sv2 := c.ServerlessV2ScalingConfiguration; sv2 != nil {
+		aes.ServerlessV2 = &ServerlessV2Capacity{
+			MinACU: aws.Float64Value(sv2.MinCapacity),
+			MaxACU: aws.Float64Value(sv2.MaxCapacity),
+		}
+	}
+
+	writers := map[string]bool{}
+	for _, m := range c.DBClusterMembers {
+		writers[aws.StringValue(m.DBInstanceIdentifier)] = aws.BoolValue(m.IsClusterWriter)
+	}
+
+	for _, i := range instances {
+		if i.Endpoint == nil {
+			continue
+		}
+		id := aws.StringValue(i.DBInstanceIdentifier)
+		entry := &AuroraEndpoint{
+			Host:          aws.StringValue(i.Endpoint.Address),
+			Port:          int(aws.Int64Value(i.Endpoint.Port)),
+			Instance:      id,
+			CACertificate: aws.StringValue(i.CACertificateIdentifier),
+		}
+		if writers[id] {
+			aes.WriterInstance = entry
+		} else {
+			aes.ReadEndpoints = append(aes.ReadEndpoints, entry)
+		}
+	}
+	aes.ReadReplicas = len(aes.ReadEndpoints) > 0
+
+	return aes
+}
+
+// GetAuroraEndpointsWithLag returns the endpoints of GetAuroraEndpoints with the latest
+// replica lag of each reader instance from CloudWatch, so routing layers can drop laggy
+// replicas with ReadersWithin. The cached endpoints are not modified.
+func (a *Config) GetAuroraEndpointsWithLag(cluster string) (*AuroraEndpoints, error) {
+	cached, err := a.GetAuroraEndpoints(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	aes := *cached
+	aes.ReadEndpoints = make([]*AuroraEndpoint, 0, len(cached.ReadEndpoints))
+	ids := make([]string, 0, len(cached.ReadEndpoints))
+	for _, v := range cached.ReadEndpoints {
+		entry := *v
+		aes.ReadEndpoints = append(aes.ReadEndpoints, &entry)
+		ids = append(ids, v.Instance)
+	}
+
+	lag, err := a.GetRDSReplicaLag(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range aes.ReadEndpoints {
+		if d, ok := lag[v.Instance]; ok {
+			v.ReplicaLag = &d
+		}
+	}
+
+	return &aes, nil
+}
+
+// ReadersWithin returns the host and port of each reader instance with a known replica
+// lag of at most maxLag
+func (aes *AuroraEndpoints) ReadersWithin(maxLag time.Duration) []string {
+	str := make([]string, 0, len(aes.ReadEndpoints))
+	for _, v := range aes.ReadEndpoints {
+		if v.ReplicaLag != nil && *v.ReplicaLag <= maxLag {
+			str = append(str, v.String())
+		}
+	}
+	return str
+}
+
+// RDSClusterFilter selects DB clusters in ListRDSClusters. Empty fields match all clusters.
+type RDSClusterFilter struct {
+	Engines []string          // e.g. aurora-mysql, aurora-postgresql
+	Tags    map[string]string // every tag must be present, an empty value matches any value
+	Status  string            // e.g. available
+}
+
+// RDSClusterSummary describes a DB cluster matched by ListRDSClusters
+type RDSClusterSummary struct {
+	Cluster       string            `json:"cluster" yaml:"cluster"`
+	ARN           string            `json:"arn" yaml:"arn"`
+	Engine        string            `json:"engine" yaml:"engine"`
+	EngineVersion string            `json:"engine_version" yaml:"engine_version"`
+	Status        string            `json:"status" yaml:"status"`
+	Tags          map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Writer        *AuroraEndpoint   `json:"writer,omitempty" yaml:"writer,omitempty"`
+	Reader        *AuroraEndpoint   `json:"reader,omitempty" yaml:"reader,omitempty"`
+	Members       int               `json:"members" yaml:"members"`
+}
+
+// ListRDSClusters returns a summary with the cluster endpoints of every DB cluster in the
+// region matching the filter. A nil filter returns all DB clusters.
+func (a *Config) ListRDSClusters(filter *RDSClusterFilter) ([]*RDSClusterSummary, error) {
+	if filter == nil {
+		filter = &RDSClusterFilter{}
+	}
+
+	input := &rds.DescribeDBClustersInput{}
+	if len(filter.Engines) > 0 {
+		input.Filters = []*rds.Filter{
+			{Name: aws.String("engine"), Values: aws.StringSlice(filter.Engines)},
+		}
+	}
+
+	list := make([]*RDSClusterSummary, 0)
+	err := a.eachRDSDBCluster(input, func(c *rds.DBCluster) bool {
+		if filter.Status != "" && aws.StringValue(c.Status) != filter.Status {
+			return true
+		}
+
+		tags := rdsTags(c.TagList)
+		for k, v := range filter.Tags {
+			if tv, ok := tags[k]; !ok || (v != "" && tv != v) {
+				return true
+			}
+		}
+
+		port := int(aws.Int64Value(c.Port))
+		list = append(list, &RDSClusterSummary{
+			Cluster:       aws.StringValue(c.DBClusterIdentifier),
+			ARN:           aws.StringValue(c.DBClusterArn),
+			Engine:        aws.StringValue(c.Engine),
+			EngineVersion: aws.StringValue(c.EngineVersion),
+			Status:        aws.StringValue(c.Status),
+			Tags:          tags,
+			Writer:        &AuroraEndpoint{Host: aws.StringValue(c.Endpoint), Port: port},
+			Reader:        &AuroraEndpoint{Host: aws.StringValue(c.ReaderEndpoint), Port: port},
+			Members:       len(c.DBClusterMembers),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// rdsTags converts an RDS tag list to a map
+func rdsTags(list []*rds.Tag) map[string]string {
+	tags := make(map[string]string, len(list))
+	for _, t := range list {
+		tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return tags
+}
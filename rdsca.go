@@ -0,0 +1,151 @@
+package awsx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// rdsTrustStore serves the CA bundles of RDS, Aurora and DocumentDB in the aws partition.
+// GovCloud and China regions have their own trust stores, see RDSCABundleURL.
+const rdsTrustStore = "https://truststore.pki.rds.amazonaws.com/"
+
+// rdsCABundles caches downloaded bundles by URL, they only change when AWS rotates CAs
+var rdsCABundles = struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}{entries: map[string][]byte{}}
+
+// RDSCABundleURL returns the URL of the CA bundle for the region, or of the global bundle
+// holding the CAs of every commercial region when region is empty or "global". GovCloud
+// and China regions are served from the trust store of their partition.
+func RDSCABundleURL(region string) string {
+	if region == "" {
+		region = "global"
+	}
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "https://truststore.pki." + region + ".rds.amazonaws.com/" + region + "/" + region + "-bundle.pem"
+	case strings.HasPrefix(region, "cn-"):
+		return "https://rds-truststore.s3.cn-north-1.amazonaws.com.cn/" + region + "/" + region + "-bundle.pem"
+	}
+	return rdsTrustStore + region + "/" + region + "-bundle.pem"
+}
+
+// GetRDSCABundle downloads the PEM encoded CA bundle of the region of the Config with the
+// HTTP client of the Config. The bundle is kept in memory so it is only downloaded once
+// per process.
+func (a *Config) GetRDSCABundle(ctx context.Context) ([]byte, error) {
+	url := RDSCABundleURL(a.GetRegion())
+
+	rdsCABundles.mu.Lock()
+	bundle, ok := rdsCABundles.entries[url]
+	rdsCABundles.mu.Unlock()
+	if ok {
+		return bundle, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := a.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("downloading " + url + ": " + resp.Status)
+	}
+
+	bundle, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(string(bundle), "BEGIN CERTIFICATE") {
+		return nil, errors.New("no certificates found in " + url)
+	}
+
+	rdsCABundles.mu.Lock()
+	rdsCABundles.entries[url] = bundle
+	rdsCABundles.mu.Unlock()
+
+	return bundle, nil
+}
+
+// RDSCertPool returns a pool of the CAs of the CA bundle of the region, see GetRDSCABundle
+func (a *Config) RDSCertPool(ctx context.Context) (*x509.CertPool, error) {
+	bundle, err := a.GetRDSCABundle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, errors.New("failed to parse the RDS CA bundle of " + a.GetRegion())
+	}
+	return pool, nil
+}
+
+// RDSTLSConfig returns a TLS config verifying RDS, Aurora and DocumentDB server
+// certificates against the CA bundle of the region, usable with Check, the Postgres
+// drivers or RegisterRDSTLSConfig
+func (a *Config) RDSTLSConfig(ctx context.Context) (*tls.Config, error) {
+	pool, err := a.RDSCertPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// RegisterRDSTLSConfig registers the TLS config of RDSTLSConfig under name with a driver,
+// without awsx depending on the driver. For the mysql driver:
+//
+//	a.RegisterRDSTLSConfig(ctx, "rds", mysql.RegisterTLSConfig)
+//	db, err := a.OpenDB(ctx, "my-cluster", awsx.DBTLS("rds"))
+func (a *Config) RegisterRDSTLSConfig(ctx context.Context, name string, register func(name string, config *tls.Config) error) error {
+	cfg, err := a.RDSTLSConfig(ctx)
+	if err != nil {
+		return err
+	}
+	return register(name, cfg)
+}
+
+// GetRDSCertificateAuthorities returns the CA identifier, e.g. rds-ca-rsa2048-g1, of every
+// instance of the Aurora cluster keyed by instance identifier, so that instances still on
+// an expiring CA can be found before rotating the bundle
+func (a *Config) GetRDSCertificateAuthorities(cluster string) (map[string]string, error) {
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeDBInstancesInput{
+		Filters: []*rds.Filter{{Name: aws.String("db-cluster-id"), Values: aws.StringSlice([]string{cluster})}},
+	}
+	cas := map[string]string{}
+	err := a.Service.Rds.DescribeDBInstancesPages(input, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		for _, i := range page.DBInstances {
+			cas[aws.StringValue(i.DBInstanceIdentifier)] = aws.StringValue(i.CACertificateIdentifier)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(cas) == 0 {
+		return nil, errors.New("no instances found for cluster " + cluster)
+	}
+
+	return cas, nil
+}
@@ -0,0 +1,182 @@
+package awsx
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReaderOption orders or filters the read endpoints returned by Readers
+type ReaderOption func(readers []*RedisEndpoint) []*RedisEndpoint
+
+// PreferAZ orders the readers in the availability zone first, keeping the discovery order
+// otherwise, so applications can prefer same-AZ replicas and avoid cross-AZ data
+// transfer charges. The zone of the running instance is returned by
+// Config.AvailabilityZone.
+func PreferAZ(az string) ReaderOption {
+	return func(readers []*RedisEndpoint) []*RedisEndpoint {
+		if az == "" {
+			return readers
+		}
+		sort.SliceStable(readers, func(i, j int) bool {
+			return readers[i].AvailabilityZone == az && readers[j].AvailabilityZone != az
+		})
+		return readers
+	}
+}
+
+// ReaderStrategy picks the reader for the next connection from the read endpoints of a
+// ReaderSelector. Next is called with at least one reader and may be called concurrently.
+type ReaderStrategy interface {
+	Next(readers []*RedisEndpoint) *RedisEndpoint
+}
+
+// ReaderStrategyFunc adapts a func to a ReaderStrategy
+type ReaderStrategyFunc func(readers []*RedisEndpoint) *RedisEndpoint
+
+// Next calls f
+func (f ReaderStrategyFunc) Next(readers []*RedisEndpoint) *RedisEndpoint { return f(readers) }
+
+// ReaderSelector hands out read endpoints one at a time using a ReaderStrategy, so that
+// callers get a single NextReader instead of managing the slice of readers themselves
+type ReaderSelector struct {
+	strategy ReaderStrategy
+	opts     []ReaderOption
+
+	mu        sync.RWMutex
+	endpoints *RedisEndpoints
+	readers   []*RedisEndpoint
+}
+
+// ReaderSelector returns a ReaderSelector over the read endpoints, ordered and filtered
+// by opts before the strategy is applied. The strategy defaults to RoundRobin.
+func (res *RedisEndpoints) ReaderSelector(strategy ReaderStrategy, opts ...ReaderOption) *ReaderSelector {
+	if strategy == nil {
+		strategy = RoundRobin()
+	}
+	s := &ReaderSelector{strategy: strategy, opts: opts}
+	s.Update(res)
+	return s
+}
+
+// Update replaces the endpoints the selector picks from, e.g. with the endpoints of a
+// Watcher event
+func (s *ReaderSelector) Update(res *RedisEndpoints) {
+	readers := append([]*RedisEndpoint(nil), res.ReadEndpoints...)
+	for _, opt := range s.opts {
+		readers = opt(readers)
+	}
+
+	s.mu.Lock()
+	s.endpoints = res
+	s.readers = readers
+	s.mu.Unlock()
+}
+
+// NextReader returns the host:port of the reader for the next connection. The primary
+// endpoint is returned when the cluster has no read replicas.
+func (s *ReaderSelector) NextReader() string {
+	s.mu.RLock()
+	res, readers := s.endpoints, s.readers
+	s.mu.RUnlock()
+
+	if len(readers) == 0 {
+		if res == nil || res.Primary == nil || res.Primary.Host == "" {
+			return ""
+		}
+		return res.PrimaryString()
+	}
+	if re := s.strategy.Next(readers); re != nil {
+		return re.String()
+	}
+	return readers[0].String()
+}
+
+// RoundRobin returns a strategy cycling through the readers in order
+func RoundRobin() ReaderStrategy {
+	var n uint64
+	return ReaderStrategyFunc(func(readers []*RedisEndpoint) *RedisEndpoint {
+		i := atomic.AddUint64(&n, 1) - 1
+		return readers[i%uint64(len(readers))]
+	})
+}
+
+// Random returns a strategy picking a reader at random
+func Random() ReaderStrategy {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return ReaderStrategyFunc(func(readers []*RedisEndpoint) *RedisEndpoint {
+		mu.Lock()
+		defer mu.Unlock()
+		return readers[r.Intn(len(readers))]
+	})
+}
+
+// LeastLag returns a strategy picking the reader with the lowest CloudWatch
+// ReplicationLag, refreshing the lag at most once per refresh interval (defaults to one
+// minute). Readers are cycled in order while no lag is known, and a failed lookup keeps
+// the previous values.
+func (a *Config) LeastLag(refresh time.Duration) ReaderStrategy {
+	if refresh <= 0 {
+		refresh = time.Minute
+	}
+	return &leastLag{config: a, refresh: refresh, fallback: RoundRobin()}
+}
+
+type leastLag struct {
+	config   *Config
+	refresh  time.Duration
+	fallback ReaderStrategy
+
+	mu      sync.Mutex
+	lag     map[string]time.Duration
+	fetched time.Time
+}
+
+func (l *leastLag) Next(readers []*RedisEndpoint) *RedisEndpoint {
+	lag := l.current(readers)
+
+	var best *RedisEndpoint
+	for _, re := range readers {
+		d, ok := lag[re.CacheClusterID]
+		if !ok {
+			continue
+		}
+		if best == nil || d < lag[best.CacheClusterID] {
+			best = re
+		}
+	}
+	if best == nil {
+		return l.fallback.Next(readers)
+	}
+	return best
+}
+
+// current returns the replication lag of the readers, looking it up when it is older
+// than the refresh interval
+func (l *leastLag) current(readers []*RedisEndpoint) map[string]time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.lag != nil && l.config.since(l.fetched) < l.refresh {
+		return l.lag
+	}
+	l.fetched = l.config.clock().Now()
+
+	ids := make([]string, 0, len(readers))
+	for _, re := range readers {
+		if re.CacheClusterID != "" {
+			ids = append(ids, re.CacheClusterID)
+		}
+	}
+	lag, err := l.config.GetRedisReplicationLag(ids)
+	if err != nil {
+		l.config.warn("Unable to look up replication lag: " + err.Error())
+		return l.lag
+	}
+	l.lag = lag
+
+	return l.lag
+}
@@ -0,0 +1,109 @@
+package awsx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RedactedValue is the replacement used by RedactAll
+const RedactedValue = "[REDACTED]"
+
+// Redact is applied to every sensitive value before it is formatted, logged or
+// marshaled. It defaults to RedactAll and can be replaced, e.g. with RedactPartial,
+// when the last characters of keys are useful for debugging.
+var Redact = RedactAll
+
+// RedactAll replaces the whole value with RedactedValue
+func RedactAll(value string) string {
+	if value == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
+// RedactPartial keeps the last four characters of values long enough that doing so
+// does not reveal most of the value, e.g. "****************WXYZ" for an access key ID
+func RedactPartial(value string) string {
+	if len(value) < 16 {
+		return RedactAll(value)
+	}
+	return strings.Repeat("*", len(value)-4) + value[len(value)-4:]
+}
+
+// Secret is a sensitive string such as an auth token or secret key. Its String(),
+// fmt and JSON representations are redacted; use UnsafeRaw() to get the real value.
+type Secret string
+
+// String returns the redacted value
+func (s Secret) String() string {
+	return Redact(string(s))
+}
+
+// GoString returns the redacted value for %#v
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", Redact(string(s)))
+}
+
+// MarshalJSON marshals the redacted value
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redact(string(s)))
+}
+
+// MarshalText marshals the redacted value
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redact(string(s))), nil
+}
+
+// UnsafeRaw returns the unredacted value. It should only be passed directly to the
+// client library that needs it and never logged.
+func (s Secret) UnsafeRaw() string {
+	return string(s)
+}
+
+// configView is the printable form of a Config with sensitive values redacted
+type configView struct {
+	Region           string            `json:"region,omitempty"`
+	Role             string            `json:"role,omitempty"`
+	ExternalID       string            `json:"external_id,omitempty"`
+	AccessKey        string            `json:"access_key,omitempty"`
+	SecretKey        string            `json:"secret_key,omitempty"`
+	SessionToken     string            `json:"session_token,omitempty"`
+	Endpoint         string            `json:"endpoint,omitempty"`
+	ServiceEndpoints map[string]string `json:"service_endpoints,omitempty"`
+	CredFile         string            `json:"cred_file,omitempty"`
+	Profile          string            `json:"profile,omitempty"`
+	Providers        int               `json:"providers"`
+}
+
+func (a *Config) view() configView {
+	return configView{
+		Region:           a.Region,
+		Role:             a.Role,
+		ExternalID:       Redact(a.ExternalID),
+		AccessKey:        Redact(a.AccessKey),
+		SecretKey:        Redact(a.SecretKey),
+		SessionToken:     Redact(a.SessionToken),
+		Endpoint:         a.Endpoint,
+		ServiceEndpoints: a.ServiceEndpoints,
+		CredFile:         a.CredFile,
+		Profile:          a.Profile,
+		Providers:        len(a.Providers),
+	}
+}
+
+// String provides the JSON representation of the Config with credentials redacted
+func (a *Config) String() string {
+	jsonByte, _ := json.Marshal(a.view())
+	return string(jsonByte)
+}
+
+// GoString provides the %#v representation of the Config with credentials redacted
+func (a *Config) GoString() string {
+	return "awsx.Config" + a.String()
+}
+
+// MarshalJSON marshals the Config with credentials redacted
+func (a *Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.view())
+}
@@ -1,46 +1,105 @@
 package awsx
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
 )
 
 // RedisEndpoints provides an identifier for a primary endpoint
 // and a slice of read endpoints
 type RedisEndpoints struct {
 	// The primary endpoint string
-	Primary          *RedisEndpoint
-	ClusterConfig    *RedisEndpoint
-	ReadEndpoints    []*RedisEndpoint
-	ReplicationGroup bool
-	ReadReplicas     bool
-	ClusterEnabled   bool
+	Primary          *RedisEndpoint   `json:"primary,omitempty" yaml:"primary,omitempty"`
+	ClusterConfig    *RedisEndpoint   `json:"cluster_config,omitempty" yaml:"cluster_config,omitempty"`
+	ReadEndpoints    []*RedisEndpoint `json:"read_endpoints" yaml:"read_endpoints"`
+	ReplicationGroup bool             `json:"replication_group" yaml:"replication_group"`
+	ReadReplicas     bool             `json:"read_replicas" yaml:"read_replicas"`
+	ClusterEnabled   bool             `json:"cluster_enabled" yaml:"cluster_enabled"`
+	NetworkType      string           `json:"network_type,omitempty" yaml:"network_type,omitempty"` // ipv4, ipv6 or dual_stack
+	IPDiscovery      string           `json:"ip_discovery,omitempty" yaml:"ip_discovery,omitempty"` // ipv4 or ipv6, the protocol the endpoints resolve to
+
+	// Engine metadata so clients can make protocol decisions, such as RESP3 or ACL
+	// support, without describing the cluster again
+	Engine                   string `json:"engine,omitempty" yaml:"engine,omitempty"`
+	EngineVersion            string `json:"engine_version,omitempty" yaml:"engine_version,omitempty"`
+	ParameterGroup           string `json:"parameter_group,omitempty" yaml:"parameter_group,omitempty"`
+	NodeType                 string `json:"node_type,omitempty" yaml:"node_type,omitempty"`
+	AuthTokenEnabled         bool   `json:"auth_token_enabled" yaml:"auth_token_enabled"`
+	AtRestEncryptionEnabled  bool   `json:"at_rest_encryption_enabled" yaml:"at_rest_encryption_enabled"`
+	TransitEncryptionEnabled bool   `json:"transit_encryption_enabled" yaml:"transit_encryption_enabled"`
+
+	// Shards holds the slot ranges and nodes of each node group when cluster mode is
+	// enabled, see GetRedisSlotMap for the node endpoints
+	Shards []*RedisShard `json:"shards,omitempty" yaml:"shards,omitempty"`
+
+	// Staleness is the age of an earlier result served after discovery failed, zero for
+	// a fresh result, see StalePolicy
+	Staleness time.Duration `json:"staleness,omitempty" yaml:"staleness,omitempty"`
 }
 
+// EndpointRole is what an endpoint connects to: the primary, a replica, or the
+// configuration endpoint of a cluster mode enabled replication group
+type EndpointRole string
+
+// Roles of an endpoint
+const (
+	RolePrimary EndpointRole = "primary"
+	RoleReplica EndpointRole = "replica"
+	RoleConfig  EndpointRole = "config"
+)
+
 // RedisEndpoint provides the structure of each endpoint entry
 type RedisEndpoint struct {
-	Host  string
-	Port  string
-	Slots string
+	Host  string `json:"host" yaml:"host"`
+	Port  int    `json:"port" yaml:"port"`
+	TLS   bool   `json:"tls" yaml:"tls"` // in-transit encryption is enabled, connect with TLS
+	Slots string `json:"slots,omitempty" yaml:"slots,omitempty"`
+	// AvailabilityZone is the customer availability zone of the node, empty for
+	// configuration and primary endpoints that are not tied to a single node
+	AvailabilityZone string `json:"availability_zone,omitempty" yaml:"availability_zone,omitempty"`
+	// CacheClusterID identifies the node of a read endpoint, e.g. for CloudWatch metrics
+	CacheClusterID string `json:"cache_cluster_id,omitempty" yaml:"cache_cluster_id,omitempty"`
+	// Role is primary or replica for nodes whose role ElastiCache reports, config for the
+	// configuration endpoint, and primary for the primary endpoint
+	Role EndpointRole `json:"role,omitempty" yaml:"role,omitempty"`
+	// NodeType is the instance type of the node, e.g. cache.r6g.large, see Weight
+	NodeType string `json:"node_type,omitempty" yaml:"node_type,omitempty"`
+	// Load holds the latest CloudWatch utilization of the node, set by AddRedisNodeLoad
+	Load *NodeLoad `json:"load,omitempty" yaml:"load,omitempty"`
 }
 
 // PrimaryString provides the string representation of the host and port for use
 // in libraries like redigo and go-redis of the primary endpoint
 func (res *RedisEndpoints) PrimaryString() string {
-	return res.Primary.Host + ":" + res.Primary.Port
+	return res.Primary.String()
 }
 
 // Readers returns a string slice of each read associated with the redis cluster
-// These are each endpoints that can be used for read connections
-func (res *RedisEndpoints) Readers() []string {
-	str := make([]string, len(res.ReadEndpoints))
-	for _, v := range res.ReadEndpoints {
-		buff := v.Host + ":" + v.Port
-		str = append(str, buff)
+// These are each endpoints that can be used for read connections. Options such as
+// PreferAZ change the order of the readers.
+func (res *RedisEndpoints) Readers(opts ...ReaderOption) []string {
+	readers := res.ReadEndpoints
+	if len(opts) > 0 {
+		readers = append([]*RedisEndpoint(nil), readers...)
+		for _, opt := range opts {
+			readers = opt(readers)
+		}
+	}
+
+	str := make([]string, 0, len(readers))
+	for _, v := range readers {
+		str = append(str, v.String())
 	}
 	return str
 }
@@ -49,7 +108,7 @@ func (res *RedisEndpoints) Readers() []string {
 // if it is in use. Otherwise, an empty string
 func (res *RedisEndpoints) ClusterConfigString() string {
 	if res.ClusterEnabled {
-		return res.ClusterConfig.Host + ":" + res.ClusterConfig.Port
+		return res.ClusterConfig.String()
 	}
 
 	return ""
@@ -58,7 +117,22 @@ func (res *RedisEndpoints) ClusterConfigString() string {
 // String provides the string representation of the host and port for use
 // in libraries like redigo and go-redis
 func (re *RedisEndpoint) String() string {
-	return re.Host + ":" + re.Port
+	return net.JoinHostPort(re.Host, re.PortString())
+}
+
+// PortString returns the port as a string, the form it had before Port was an int
+func (re *RedisEndpoint) PortString() string {
+	return strconv.Itoa(re.Port)
+}
+
+// ParsePort parses a port in the string form, e.g. from an older endpoint store file or
+// configuration, for use as the Port of an endpoint
+func ParsePort(port string) (int, error) {
+	p, err := strconv.Atoi(port)
+	if err != nil || p < 0 || p > 65535 {
+		return 0, errors.New("invalid port " + port)
+	}
+	return p, nil
 }
 
 // String provides the string representation of all endpoints in JSON format
@@ -67,9 +141,22 @@ func (res *RedisEndpoints) String() string {
 	return string(jsonByte)
 }
 
-// GetECReplicationGroup gathers information about the elasticache replication groups
+// GetECReplicationGroup gathers information about the elasticache replication groups.
+// A failed call also has a count of 0, use GetECReplicationGroupE to tell it apart from
+// a replication group that does not exist.
 func (a *Config) GetECReplicationGroup(cluster string) (*elasticache.DescribeReplicationGroupsOutput, int) {
+	result, count, _ := a.getECReplicationGroup(a.context(), cluster)
+	return result, count
+}
+
+// GetECReplicationGroupE is GetECReplicationGroup returning the error of a failed call.
+// A replication group that does not exist has a count of 0 and no error.
+func (a *Config) GetECReplicationGroupE(cluster string) (*elasticache.DescribeReplicationGroupsOutput, int, error) {
+	return a.getECReplicationGroup(a.context(), cluster)
+}
 
+// getECReplicationGroup is GetECReplicationGroupE with the context of the call
+func (a *Config) getECReplicationGroup(ctx context.Context, cluster string) (*elasticache.DescribeReplicationGroupsOutput, int, error) {
 	if a.Service.Ec == nil {
 		a.SetECClient()
 	}
@@ -78,17 +165,20 @@ func (a *Config) GetECReplicationGroup(cluster string) (*elasticache.DescribeRep
 		ReplicationGroupId: aws.String(cluster),
 	}
 
-	result, err := a.Service.Ec.DescribeReplicationGroups(input)
+	result, err := a.Service.Ec.DescribeReplicationGroupsWithContext(ctx, input)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elasticache.ErrCodeReplicationGroupNotFoundFault {
+		return nil, 0, nil
+	}
 	if err != nil {
-		return nil, 0
+		return nil, 0, err
 	}
 
 	count := len(result.ReplicationGroups)
 	if count == 0 {
-		return nil, 0
+		return nil, 0, nil
 	}
 
-	return result, count
+	return result, count, nil
 }
 
 // GetRedisAllEndpoints returns type RedisEndpoints populated with either a single
@@ -114,8 +204,27 @@ func (a *Config) GetRedisAllEndpoints(cluster string) (*RedisEndpoints, error) {
 // endpoint host and port for use with redigo and go-redis
 // This ONLY returns the primary endpoint used for read/write operations
 func (a *Config) GetRedisPrimaryEndpoint(cluster string) (*RedisEndpoints, error) {
-	var err error
+	res, err := a.traced("redis", cluster, func(ctx context.Context) (interface{}, error) {
+		return a.cached("redis:"+cluster, func() (interface{}, error) {
+			return a.getRedisPrimaryEndpoint(ctx, cluster)
+		})
+	})
+	if res == nil {
+		return nil, err
+	}
+
+	return res.(*RedisEndpoints), err
+}
+
+// getRedisPrimaryEndpoint performs the uncached discovery for GetRedisPrimaryEndpoint
+func (a *Config) getRedisPrimaryEndpoint(ctx context.Context, cluster string) (*RedisEndpoints, error) {
+	return a.describeRedis(ctx, cluster, a.fuzzyNames)
+}
 
+// describeRedis discovers the endpoints of the replication group or cache cluster,
+// falling back to a fuzzy match of the name when fuzzy is set. The match is described
+// without fuzzy matching, so a name that vanishes in between can't recurse.
+func (a *Config) describeRedis(ctx context.Context, cluster string, fuzzy bool) (*RedisEndpoints, error) {
 	res := &RedisEndpoints{
 		ReplicationGroup: false,
 		ReadReplicas:     false,
@@ -125,58 +234,245 @@ func (a *Config) GetRedisPrimaryEndpoint(cluster string) (*RedisEndpoints, error
 	if cluster == "" {
 		return res, errors.New("no cluster name provided")
 	}
-	result, count := a.GetECReplicationGroup(cluster)
+	result, count, err := a.getECReplicationGroup(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
 	if count == 0 {
 		res.ReplicationGroup = false
 	} else if count > 1 {
 		res.ReplicationGroup = true
 		return res, errors.New("more than one cluster matches the name provided")
 	} else {
-		res.ReplicationGroup = true
-		if *result.ReplicationGroups[0].ClusterEnabled {
-			res.ClusterEnabled = true
-			res.ClusterConfig, err = a.GetRedisClusterEndpoint(cluster)
-			if err != nil {
-				return res, err
-			}
-		} else {
-			res.ClusterEnabled = false
-			res.Primary.Host = *result.ReplicationGroups[0].NodeGroups[0].PrimaryEndpoint.Address
-			res.Primary.Port = strconv.FormatInt(*result.ReplicationGroups[0].NodeGroups[0].PrimaryEndpoint.Port, 10)
-			if len(result.ReplicationGroups[0].NodeGroups[0].NodeGroupMembers) > 1 {
-				res.ReadReplicas = true
-				for _, v := range result.ReplicationGroups[0].NodeGroups[0].NodeGroupMembers {
-					entry := &RedisEndpoint{
-						Host: *v.ReadEndpoint.Address,
-						Port: strconv.FormatInt(*v.ReadEndpoint.Port, 10),
-					}
-					res.ReadEndpoints = append(res.ReadEndpoints, entry)
-				}
-			}
+		rg := result.ReplicationGroups[0]
+		res, err := redisEndpointsFromGroup(rg)
+		if err != nil {
+			return res, err
+		}
+		if err := a.setGroupEngine(ctx, res, rg); err != nil {
+			return nil, err
 		}
+		return res, nil
 	}
 
 	if !res.ReplicationGroup {
-		list, _ := a.GetECClusterDetails(cluster)
+		list, err := a.getECClusterDetails(ctx, cluster)
+		if aerr, ok := err.(awserr.Error); err != nil && (!ok || aerr.Code() != elasticache.ErrCodeCacheClusterNotFoundFault) {
+			return nil, err
+		}
 
-		if len(list.CacheClusters) == 0 {
+		if list == nil || len(list.CacheClusters) == 0 {
+			if fuzzy {
+				match, err := a.resolveFuzzyName(ctx, cluster)
+				if err != nil {
+					return nil, err
+				}
+				return a.describeRedis(ctx, match, false)
+			}
 			return nil, errors.New("no replication groups or cache clusters associated with this cluster name")
 		}
 		if len(list.CacheClusters) > 1 {
 			res.ReadReplicas = true
 			return nil, errors.New("more than one cache cluster associated with this name")
 		}
-		if list.CacheClusters[0].CacheNodes[0].Endpoint != nil {
-			res.Primary.Host = *list.CacheClusters[0].CacheNodes[0].Endpoint.Address
-			res.Primary.Port = strconv.FormatInt(*list.CacheClusters[0].CacheNodes[0].Endpoint.Port, 10)
-		} else {
-			return nil, errors.New("no cache cluster endpoint or replication group associated with this custer name")
+		return redisEndpointsFromCacheCluster(list.CacheClusters[0])
+	}
+
+	return res, nil
+}
+
+// redisEngineMu guards the engine metadata remembered on a Config
+var redisEngineMu sync.Mutex
+
+// redisEngine is the engine metadata of a replication group that DescribeReplicationGroups
+// does not return, as described on its first member cluster
+type redisEngine struct {
+	engine         string
+	version        string
+	parameterGroup string
+	status         string // status of the replication group when it was described
+}
+
+// setGroupEngine sets the engine version and parameter group of the replication group,
+// which are only described on its member clusters. They are remembered per replication
+// group and only described again when the group changed status, e.g. while it is being
+// upgraded, so discovery doesn't describe a member cluster on every call.
+func (a *Config) setGroupEngine(ctx context.Context, res *RedisEndpoints, rg *elasticache.ReplicationGroup) error {
+	if len(rg.MemberClusters) == 0 {
+		return nil
+	}
+	id := aws.StringValue(rg.ReplicationGroupId)
+	status := aws.StringValue(rg.Status)
+
+	redisEngineMu.Lock()
+	e, ok := a.redisEngines[id]
+	redisEngineMu.Unlock()
+
+	if !ok || e.status != status || status != "available" {
+		list, err := a.getECClusterDetails(ctx, aws.StringValue(rg.MemberClusters[0]))
+		if err != nil {
+			return err
+		}
+		if len(list.CacheClusters) == 0 {
+			return errors.New("no member cluster found for replication group " + id)
+		}
+		cc := list.CacheClusters[0]
+		e = redisEngine{
+			engine:  aws.StringValue(cc.Engine),
+			version: aws.StringValue(cc.EngineVersion),
+			status:  status,
+		}
+		if cc.CacheParameterGroup != nil {
+			e.parameterGroup = aws.StringValue(cc.CacheParameterGroup.CacheParameterGroupName)
+		}
+
+		redisEngineMu.Lock()
+		if a.redisEngines == nil {
+			a.redisEngines = map[string]redisEngine{}
+		}
+		a.redisEngines[id] = e
+		redisEngineMu.Unlock()
+	}
+
+	if res.Engine == "" {
+		res.Engine = e.engine
+	}
+	res.EngineVersion = e.version
+	res.ParameterGroup = e.parameterGroup
+	return nil
+}
+
+// redisEndpointsFromCacheCluster builds the RedisEndpoints of a described cache cluster
+// that is not part of a replication group
+func redisEndpointsFromCacheCluster(cc *elasticache.CacheCluster) (*RedisEndpoints, error) {
+	res := &RedisEndpoints{
+		ReplicationGroup: false,
+		ReadReplicas:     false,
+		ClusterEnabled:   false,
+	}
+	res.Primary = &RedisEndpoint{Role: RolePrimary}
+	res.NetworkType = aws.StringValue(cc.NetworkType)
+	res.IPDiscovery = aws.StringValue(cc.IpDiscovery)
+	res.setEngine(cc)
+	if len(cc.CacheNodes) == 0 || cc.CacheNodes[0].Endpoint == nil {
+		return nil, errors.New("no cache cluster endpoint or replication group associated with this custer name")
+	}
+	res.Primary.Host = aws.StringValue(cc.CacheNodes[0].Endpoint.Address)
+	res.Primary.Port = int(aws.Int64Value(cc.CacheNodes[0].Endpoint.Port))
+
+	return res, nil
+}
+
+// redisEndpointsFromGroup builds the RedisEndpoints of a described replication group
+func redisEndpointsFromGroup(rg *elasticache.ReplicationGroup) (*RedisEndpoints, error) {
+	res := &RedisEndpoints{
+		ReplicationGroup: true,
+		ReadReplicas:     false,
+		ClusterEnabled:   false,
+	}
+	res.Primary = &RedisEndpoint{}
+	res.NetworkType = aws.StringValue(rg.NetworkType)
+	res.IPDiscovery = aws.StringValue(rg.IpDiscovery)
+	res.Engine = aws.StringValue(rg.Engine)
+	res.NodeType = aws.StringValue(rg.CacheNodeType)
+	res.AuthTokenEnabled = aws.BoolValue(rg.AuthTokenEnabled)
+	res.AtRestEncryptionEnabled = aws.BoolValue(rg.AtRestEncryptionEnabled)
+	res.TransitEncryptionEnabled = aws.BoolValue(rg.TransitEncryptionEnabled)
+
+	if aws.BoolValue(rg.ClusterEnabled) {
+		res.ClusterEnabled = true
+		if rg.ConfigurationEndpoint == nil {
+			return res, errors.New("no cluster endpoint found, perhaps this is not a cluster configuration")
+		}
+		res.ClusterConfig = &RedisEndpoint{
+			Host: *rg.ConfigurationEndpoint.Address,
+			Port: int(*rg.ConfigurationEndpoint.Port),
+			TLS:  res.TransitEncryptionEnabled,
+			Role: RoleConfig,
+		}
+		res.Shards = redisShardsFromGroup(rg)
+		return res, nil
+	}
+
+	if len(rg.NodeGroups) == 0 || rg.NodeGroups[0].PrimaryEndpoint == nil {
+		return res, errors.New("no primary endpoint found for this replication group")
+	}
+
+	res.Primary.NodeType = res.NodeType
+	res.Primary.TLS = res.TransitEncryptionEnabled
+	res.Primary.Role = RolePrimary
+	res.Primary.Host = *rg.NodeGroups[0].PrimaryEndpoint.Address
+	res.Primary.Port = int(*rg.NodeGroups[0].PrimaryEndpoint.Port)
+	if len(rg.NodeGroups[0].NodeGroupMembers) > 1 {
+		res.ReadReplicas = true
+		for _, v := range rg.NodeGroups[0].NodeGroupMembers {
+			if aws.StringValue(v.CurrentRole) == "primary" {
+				res.Primary.AvailabilityZone = aws.StringValue(v.PreferredAvailabilityZone)
+			}
+			if v.ReadEndpoint == nil {
+				continue
+			}
+			entry := &RedisEndpoint{
+				Host:             *v.ReadEndpoint.Address,
+				Port:             int(*v.ReadEndpoint.Port),
+				TLS:              res.TransitEncryptionEnabled,
+				AvailabilityZone: aws.StringValue(v.PreferredAvailabilityZone),
+				CacheClusterID:   aws.StringValue(v.CacheClusterId),
+				Role:             EndpointRole(aws.StringValue(v.CurrentRole)),
+				NodeType:         res.NodeType,
+			}
+			res.ReadEndpoints = append(res.ReadEndpoints, entry)
 		}
 	}
 
 	return res, nil
 }
 
+// setEngine sets the engine metadata from a described cache cluster
+func (res *RedisEndpoints) setEngine(cc *elasticache.CacheCluster) {
+	res.Engine = aws.StringValue(cc.Engine)
+	res.EngineVersion = aws.StringValue(cc.EngineVersion)
+	if cc.CacheParameterGroup != nil {
+		res.ParameterGroup = aws.StringValue(cc.CacheParameterGroup.CacheParameterGroupName)
+	}
+	res.NodeType = aws.StringValue(cc.CacheNodeType)
+	res.AuthTokenEnabled = aws.BoolValue(cc.AuthTokenEnabled)
+	res.AtRestEncryptionEnabled = aws.BoolValue(cc.AtRestEncryptionEnabled)
+	res.TransitEncryptionEnabled = aws.BoolValue(cc.TransitEncryptionEnabled)
+	for _, re := range append([]*RedisEndpoint{res.Primary}, res.ReadEndpoints...) {
+		if re == nil {
+			continue
+		}
+		if re.NodeType == "" {
+			re.NodeType = res.NodeType
+		}
+		re.TLS = re.TLS || res.TransitEncryptionEnabled
+	}
+}
+
+// EngineMajorVersion returns the major version of the engine, e.g. 7 for 7.0.7, or 0
+// when the version is unknown
+func (res *RedisEndpoints) EngineMajorVersion() int {
+	major := strings.SplitN(res.EngineVersion, ".", 2)[0]
+	v, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// SupportsRESP3 reports whether the engine speaks the RESP3 protocol (Redis 6 and later,
+// and every Valkey version)
+func (res *RedisEndpoints) SupportsRESP3() bool {
+	return strings.EqualFold(res.Engine, "valkey") || res.EngineMajorVersion() >= 6
+}
+
+// SupportsACL reports whether the engine supports users and ACLs (Redis 6 and later,
+// and every Valkey version)
+func (res *RedisEndpoints) SupportsACL() bool {
+	return strings.EqualFold(res.Engine, "valkey") || res.EngineMajorVersion() >= 6
+}
+
 // GetRedisClusterEndpoint returns a string representation of the cluster
 // endpoint host ane port for use with Redigo and go-redis as host:port
 // This value is the configuration endpoint from elasticache
@@ -185,7 +481,10 @@ func (a *Config) GetRedisClusterEndpoint(cluster string) (*RedisEndpoint, error)
 	if cluster == "" {
 		return re, errors.New("no cluster name provided")
 	}
-	result, count := a.GetECReplicationGroup(cluster)
+	result, count, err := a.GetECReplicationGroupE(cluster)
+	if err != nil {
+		return re, err
+	}
 	if count == 0 {
 		return re, errors.New("no cluster existing matching provided name")
 	}
@@ -198,14 +497,20 @@ func (a *Config) GetRedisClusterEndpoint(cluster string) (*RedisEndpoint, error)
 	}
 
 	re.Host = *result.ReplicationGroups[0].ConfigurationEndpoint.Address
-	re.Port = strconv.FormatInt(*result.ReplicationGroups[0].ConfigurationEndpoint.Port, 10)
+	re.Port = int(*result.ReplicationGroups[0].ConfigurationEndpoint.Port)
+	re.TLS = aws.BoolValue(result.ReplicationGroups[0].TransitEncryptionEnabled)
+	re.Role = RoleConfig
 
 	return re, nil
 }
 
 // GetECClusterDetails provides the initial call to describe the identified cluster
 func (a *Config) GetECClusterDetails(cluster string) (*elasticache.DescribeCacheClustersOutput, error) {
+	return a.getECClusterDetails(a.context(), cluster)
+}
 
+// getECClusterDetails is GetECClusterDetails with the context of the call
+func (a *Config) getECClusterDetails(ctx context.Context, cluster string) (*elasticache.DescribeCacheClustersOutput, error) {
 	if cluster == "" {
 		if a.panicOnErr {
 			panic("panicOnErr enabled, must provide a cluster string to (a *Config) GetClusterDetails(cluster string)")
@@ -213,12 +518,16 @@ func (a *Config) GetECClusterDetails(cluster string) (*elasticache.DescribeCache
 		return nil, errors.New("did not provide a cluster name for the RDS describe call")
 	}
 
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
 	input := &elasticache.DescribeCacheClustersInput{
 		CacheClusterId:    aws.String(cluster),
 		ShowCacheNodeInfo: aws.Bool(true),
 	}
 
-	result, err := a.Service.Ec.DescribeCacheClusters(input)
+	result, err := a.Service.Ec.DescribeCacheClustersWithContext(ctx, input)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +536,7 @@ func (a *Config) GetECClusterDetails(cluster string) (*elasticache.DescribeCache
 }
 
 // GetECClient returns a client for use with AWS Elasticache
-func (a *Config) GetECClient() *elasticache.ElastiCache {
+func (a *Config) GetECClient() elasticacheiface.ElastiCacheAPI {
 	return a.Service.Ec
 }
 
@@ -236,7 +545,18 @@ func (a *Config) SetECClient() *Config {
 	if a.Service == nil {
 		panic("Must initialize Service struct with NewRDS()")
 	}
-	a.Service.Ec = elasticache.New(a.Session)
+	a.Service.Ec = elasticache.New(a.ClientConfig(elasticache.EndpointsID))
+
+	return a
+}
+
+// WithECClient sets the client used for AWS Elasticache calls, such as a mock from
+// the awsxmock package
+func (a *Config) WithECClient(client elasticacheiface.ElastiCacheAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Ec = client
 
 	return a
 }
@@ -0,0 +1,191 @@
+package awsx
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRefreshWindow is how long before expiry the refresher renews credentials
+	defaultRefreshWindow = 5 * time.Minute
+	// refreshRetryInterval is how long the refresher waits after a failed refresh or for
+	// credentials without an expiry
+	refreshRetryInterval = time.Minute
+)
+
+// CredentialEvent describes credentials that are about to expire or were refreshed
+type CredentialEvent struct {
+	Provider    string
+	AccessKeyID string
+	Expires     time.Time // zero if the credentials do not expire
+	Err         error     // set when the refresh failed
+}
+
+// CredentialHook is called by the CredentialRefresher
+type CredentialHook func(ev CredentialEvent)
+
+// credentialHooks holds the hooks registered on a Config
+type credentialHooks struct {
+	mu       sync.Mutex
+	expiring []CredentialHook
+	refresh  []CredentialHook
+}
+
+// OnCredentialExpiring registers a hook called by the CredentialRefresher shortly before
+// the session credentials expire, before they are refreshed
+func (a *Config) OnCredentialExpiring(fn CredentialHook) *Config {
+	h := a.hooks()
+	h.mu.Lock()
+	h.expiring = append(h.expiring, fn)
+	h.mu.Unlock()
+	return a
+}
+
+// OnCredentialRefresh registers a hook called by the CredentialRefresher after the
+// session credentials were refreshed, or failed to refresh, so derived secrets such as
+// IAM database auth tokens can be rotated
+func (a *Config) OnCredentialRefresh(fn CredentialHook) *Config {
+	h := a.hooks()
+	h.mu.Lock()
+	h.refresh = append(h.refresh, fn)
+	h.mu.Unlock()
+	return a
+}
+
+// credHooksMu guards the lazy creation of the credential hooks of a Config
+var credHooksMu sync.Mutex
+
+func (a *Config) hooks() *credentialHooks {
+	credHooksMu.Lock()
+	defer credHooksMu.Unlock()
+	if a.credHooks == nil {
+		a.credHooks = &credentialHooks{}
+	}
+	return a.credHooks
+}
+
+// CredentialRefresher renews the session credentials in the background ahead of their
+// expiry, so calls never block on an STS round trip, and runs the credential hooks
+type CredentialRefresher struct {
+	Window time.Duration
+
+	config *Config
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	running bool
+}
+
+// StartCredentialRefresher starts a refresher renewing the credentials window before
+// they expire. A zero window defaults to 5 minutes.
+func (a *Config) StartCredentialRefresher(window time.Duration) (*CredentialRefresher, error) {
+	if a.Session == nil {
+		a.SetSession()
+	}
+	if a.Session == nil || a.Session.Config.Credentials == nil {
+		return nil, errors.New("no session credentials to refresh")
+	}
+	if window <= 0 {
+		window = defaultRefreshWindow
+	}
+
+	r := &CredentialRefresher{Window: window, config: a, stop: make(chan struct{}), running: true}
+	go r.run(r.stop)
+
+	return r, nil
+}
+
+// Stop ends the background refresh
+func (r *CredentialRefresher) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running {
+		return
+	}
+	r.running = false
+	close(r.stop)
+}
+
+// expirable is implemented by providers holding credentials of their own, such as the
+// hops of a role chain, that must be expired for a refresh to retrieve new ones
+type expirable interface {
+	Expire()
+}
+
+// Refresh expires the session credentials and those held by the providers of the chain
+// and retrieves new ones, running the hooks
+func (r *CredentialRefresher) Refresh() CredentialEvent {
+	creds := r.config.Session.Config.Credentials
+
+	ev := r.event()
+	r.fire(func(h *credentialHooks) []CredentialHook { return h.expiring }, ev)
+
+	for _, p := range r.config.Providers {
+		if e, ok := p.(expirable); ok {
+			e.Expire()
+		}
+	}
+	creds.Expire()
+	ev = r.event()
+	r.fire(func(h *credentialHooks) []CredentialHook { return h.refresh }, ev)
+
+	return ev
+}
+
+// event retrieves the credentials and describes them
+func (r *CredentialRefresher) event() CredentialEvent {
+	creds := r.config.Session.Config.Credentials
+
+	v, err := creds.Get()
+	if err != nil {
+		return CredentialEvent{Err: err}
+	}
+
+	ev := CredentialEvent{Provider: v.ProviderName, AccessKeyID: v.AccessKeyID}
+	if expires, err := creds.ExpiresAt(); err == nil {
+		ev.Expires = expires
+	}
+
+	return ev
+}
+
+// fire calls the hooks picked from the registered ones, copied under the lock so hooks
+// can be registered while the refresher runs
+func (r *CredentialRefresher) fire(pick func(h *credentialHooks) []CredentialHook, ev CredentialEvent) {
+	h := r.config.hooks()
+	h.mu.Lock()
+	hooks := append([]CredentialHook(nil), pick(h)...)
+	h.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(ev)
+	}
+}
+
+func (r *CredentialRefresher) run(stop chan struct{}) {
+	clock := r.config.clock()
+	for {
+		ev := r.event()
+		if ev.Err != nil || ev.Expires.IsZero() {
+			// without a known expiry there is nothing to refresh ahead of, so the
+			// credentials are left to the SDK and checked again later
+			if !r.config.wait(refreshRetryInterval, stop) {
+				return
+			}
+			continue
+		}
+
+		if !r.config.wait(ev.Expires.Sub(clock.Now())-r.Window, stop) {
+			return
+		}
+		if ev := r.Refresh(); ev.Err != nil || ev.Expires.Sub(clock.Now()) <= r.Window {
+			// back off instead of refreshing in a loop when the new credentials fail or
+			// expire within the window themselves
+			if !r.config.wait(refreshRetryInterval, stop) {
+				return
+			}
+		}
+	}
+}
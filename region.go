@@ -0,0 +1,111 @@
+package awsx
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// maxRegionConcurrency bounds how many regions are queried in parallel
+const maxRegionConcurrency = 4
+
+// regionConfigs holds the per-region Configs derived by ForRegion
+type regionConfigs struct {
+	mu      sync.Mutex
+	configs map[string]*Config
+}
+
+// RegionalRedisEndpoints is the result of Redis discovery in a single region
+type RegionalRedisEndpoints struct {
+	Region    string
+	Endpoints *RedisEndpoints
+	Err       error
+}
+
+// RegionalAuroraEndpoints is the result of Aurora discovery in a single region
+type RegionalAuroraEndpoints struct {
+	Region    string
+	Endpoints *AuroraEndpoints
+	Err       error
+}
+
+// WithRegions sets the regions queried by the MultiRegion discovery functions
+func (a *Config) WithRegions(regions ...string) *Config {
+	a.Regions = append(a.Regions[:0:0], regions...)
+	return a
+}
+
+// ForRegion returns a Config sharing the credential chain and settings of this Config
+// but making calls against the given region. The derived Config is reused by later
+// calls for the same region so its clients and cache are shared.
+func (a *Config) ForRegion(region string) *Config {
+	if a.regions == nil {
+		a.regions = &regionConfigs{configs: map[string]*Config{}}
+	}
+
+	a.regions.mu.Lock()
+	defer a.regions.mu.Unlock()
+
+	if c, ok := a.regions.configs[region]; ok {
+		return c
+	}
+
+	if a.Session == nil {
+		a.SetSession()
+	}
+
+	c := *a
+	c.Region = region
+	c.Service = &Services{}
+	c.cache = newResultCache()
+	c.regions = nil
+	if a.Session != nil {
+		c.Session = a.Session.Copy(aws.NewConfig().WithRegion(region))
+	}
+
+	a.regions.configs[region] = &c
+	return &c
+}
+
+// GetRedisPrimaryEndpointMultiRegion runs GetRedisPrimaryEndpoint for the cluster in
+// every region set with WithRegions, in parallel, returning a result per region in
+// the order the regions were given
+func (a *Config) GetRedisPrimaryEndpointMultiRegion(cluster string) []*RegionalRedisEndpoints {
+	results := make([]*RegionalRedisEndpoints, len(a.Regions))
+	a.eachRegion(func(i int, rc *Config) {
+		res, err := rc.GetRedisPrimaryEndpoint(cluster)
+		results[i] = &RegionalRedisEndpoints{Region: rc.Region, Endpoints: res, Err: err}
+	})
+	return results
+}
+
+// GetAuroraEndpointsMultiRegion runs GetAuroraEndpoints for the cluster in every region
+// set with WithRegions, in parallel, returning a result per region in the order the
+// regions were given
+func (a *Config) GetAuroraEndpointsMultiRegion(cluster string) []*RegionalAuroraEndpoints {
+	results := make([]*RegionalAuroraEndpoints, len(a.Regions))
+	a.eachRegion(func(i int, rc *Config) {
+		aes, err := rc.GetAuroraEndpoints(cluster)
+		results[i] = &RegionalAuroraEndpoints{Region: rc.Region, Endpoints: aes, Err: err}
+	})
+	return results
+}
+
+// eachRegion calls fn with the Config of every region in a.Regions with bounded concurrency
+func (a *Config) eachRegion(fn func(i int, rc *Config)) {
+	sem := make(chan struct{}, maxRegionConcurrency)
+	var wg sync.WaitGroup
+
+	for i, region := range a.Regions {
+		rc := a.ForRegion(region)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, rc *Config) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i, rc)
+		}(i, rc)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,228 @@
+package awsx
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Datastore kinds of the entries of a Registry
+const (
+	RegistryRedis  = "redis"
+	RegistryAurora = "aurora"
+)
+
+// Registry maps logical names such as "sessions-cache" or "orders-db" to ElastiCache
+// replication groups and Aurora clusters and keeps their endpoints resolved and watched,
+// so services talking to many datastores discover all of them in one place
+type Registry struct {
+	Interval time.Duration // poll interval of the watchers, defaults to 30 seconds
+
+	config *Config
+
+	mu      sync.RWMutex
+	entries map[string]*registryEntry
+	running bool
+}
+
+// RegistryEntry is the state of a registered datastore. Redis or Aurora hold the last
+// endpoints discovered, which are kept when later discovery fails with Err.
+type RegistryEntry struct {
+	Name    string           `json:"name" yaml:"name"`
+	Kind    string           `json:"kind" yaml:"kind"` // redis or aurora
+	Cluster string           `json:"cluster" yaml:"cluster"`
+	Redis   *RedisEndpoints  `json:"redis,omitempty" yaml:"redis,omitempty"`
+	Aurora  *AuroraEndpoints `json:"aurora,omitempty" yaml:"aurora,omitempty"`
+	Err     error            `json:"-" yaml:"-"`
+	Updated time.Time        `json:"updated" yaml:"updated"` // when the endpoints or error were last discovered
+}
+
+type registryEntry struct {
+	RegistryEntry
+	watcher *Watcher
+}
+
+// NewRegistry returns an empty Registry discovering with the Config
+func (a *Config) NewRegistry(interval time.Duration) *Registry {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return &Registry{Interval: interval, config: a, entries: map[string]*registryEntry{}}
+}
+
+// RegisterRedis registers the replication group or cache cluster under name
+func (r *Registry) RegisterRedis(name, cluster string) *Registry {
+	return r.register(name, RegistryRedis, cluster)
+}
+
+// RegisterAurora registers the Aurora DB cluster under name
+func (r *Registry) RegisterAurora(name, cluster string) *Registry {
+	return r.register(name, RegistryAurora, cluster)
+}
+
+func (r *Registry) register(name, kind, cluster string) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.entries[name]; ok && old.watcher != nil {
+		old.watcher.Stop()
+	}
+	e := &registryEntry{RegistryEntry: RegistryEntry{Name: name, Kind: kind, Cluster: cluster}}
+	r.entries[name] = e
+	if r.running {
+		r.watch(e)
+	}
+
+	return r
+}
+
+// Start resolves every registered datastore with the batch discovery functions and then
+// watches each of them. Datastores registered later are watched as they are registered.
+func (r *Registry) Start() *Registry {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return r
+	}
+	r.running = true
+	redis, aurora := make([]string, 0), make([]string, 0)
+	for _, e := range r.entries {
+		if e.Kind == RegistryRedis {
+			redis = append(redis, e.Cluster)
+		} else {
+			aurora = append(aurora, e.Cluster)
+		}
+	}
+	r.mu.Unlock()
+
+	redisRes, redisErrs := r.config.GetRedisEndpointsBatch(redis)
+	auroraRes, auroraErrs := r.config.GetAuroraEndpointsBatch(aurora)
+	now := r.config.clock().Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		if e.Kind == RegistryRedis {
+			e.Redis, e.Err = redisRes[e.Cluster], redisErrs[e.Cluster]
+		} else {
+			e.Aurora, e.Err = auroraRes[e.Cluster], auroraErrs[e.Cluster]
+		}
+		e.Updated = now
+		r.watch(e)
+	}
+
+	return r
+}
+
+// Stop stops watching every registered datastore
+func (r *Registry) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.running = false
+	for _, e := range r.entries {
+		if e.watcher != nil {
+			e.watcher.Stop()
+			e.watcher = nil
+		}
+	}
+}
+
+// watch starts a watcher for the entry, updating it on every event. r.mu must be held.
+func (r *Registry) watch(e *registryEntry) {
+	if e.Kind == RegistryRedis {
+		e.watcher = r.config.WatchRedis(e.Cluster, r.Interval)
+	} else {
+		e.watcher = r.config.WatchAurora(e.Cluster, r.Interval)
+	}
+	sub := e.watcher.Subscribe(SubscribeOptions{Buffer: 1, Policy: Coalesce})
+
+	go func() {
+		for ev := range sub.C {
+			r.mu.Lock()
+			e.Err = ev.Err
+			if ev.Redis != nil {
+				e.Redis = ev.Redis
+			}
+			if ev.Aurora != nil {
+				e.Aurora = ev.Aurora
+			}
+			e.Updated = ev.Time
+			r.mu.Unlock()
+		}
+	}()
+
+	e.watcher.Start()
+}
+
+// Lookup returns the state of the datastore registered under name. A datastore that has
+// not been resolved yet, because the Registry was not started, is resolved first.
+func (r *Registry) Lookup(name string) (*RegistryEntry, error) {
+	r.mu.RLock()
+	e, ok := r.entries[name]
+	var entry RegistryEntry
+	if ok {
+		entry = e.RegistryEntry
+	}
+	r.mu.RUnlock()
+	if !ok {
+		return nil, errors.New("no datastore registered as " + name)
+	}
+
+	if entry.Redis == nil && entry.Aurora == nil && entry.Err == nil {
+		if entry.Kind == RegistryRedis {
+			entry.Redis, entry.Err = r.config.GetRedisPrimaryEndpoint(entry.Cluster)
+		} else {
+			entry.Aurora, entry.Err = r.config.GetAuroraEndpoints(entry.Cluster)
+		}
+		entry.Updated = r.config.clock().Now()
+	}
+
+	return &entry, nil
+}
+
+// LookupRedis returns the last endpoints discovered for the Redis datastore registered
+// under name, or the discovery error if there are none
+func (r *Registry) LookupRedis(name string) (*RedisEndpoints, error) {
+	e, err := r.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if e.Kind != RegistryRedis {
+		return nil, errors.New(name + " is registered as " + e.Kind + ", not redis")
+	}
+	if e.Redis == nil {
+		return nil, e.Err
+	}
+	return e.Redis, nil
+}
+
+// LookupAurora returns the last endpoints discovered for the Aurora datastore registered
+// under name, or the discovery error if there are none
+func (r *Registry) LookupAurora(name string) (*AuroraEndpoints, error) {
+	e, err := r.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if e.Kind != RegistryAurora {
+		return nil, errors.New(name + " is registered as " + e.Kind + ", not aurora")
+	}
+	if e.Aurora == nil {
+		return nil, e.Err
+	}
+	return e.Aurora, nil
+}
+
+// Names returns the registered names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
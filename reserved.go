@@ -0,0 +1,272 @@
+package awsx
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// reservationActive is the state of a reservation that is currently billed
+const reservationActive = "active"
+
+// Reservation is an ElastiCache reserved cache node or RDS reserved DB instance purchase
+type Reservation struct {
+	ID           string        `json:"id" yaml:"id"`
+	Service      string        `json:"service" yaml:"service"` // elasticache or rds
+	NodeType     string        `json:"node_type" yaml:"node_type"`
+	Engine       string        `json:"engine" yaml:"engine"`   // engine of the product description, e.g. redis or postgres
+	Product      string        `json:"product" yaml:"product"` // product description, e.g. postgresql or oracle-se2(byol)
+	Count        int64         `json:"count" yaml:"count"`
+	MultiAZ      bool          `json:"multi_az,omitempty" yaml:"multi_az,omitempty"`
+	OfferingType string        `json:"offering_type" yaml:"offering_type"`
+	State        string        `json:"state" yaml:"state"`
+	Start        time.Time     `json:"start" yaml:"start"`
+	Duration     time.Duration `json:"duration" yaml:"duration"`
+}
+
+// Active reports whether the reservation is currently billed
+func (r *Reservation) Active() bool {
+	return r.State == reservationActive
+}
+
+// End returns when the reservation expires
+func (r *Reservation) End() time.Time {
+	return r.Start.Add(r.Duration)
+}
+
+// ReservationCoverage compares the running nodes of a node type and engine to the active
+// reservations for them
+type ReservationCoverage struct {
+	NodeType string `json:"node_type" yaml:"node_type"`
+	Engine   string `json:"engine" yaml:"engine"`
+	Running  int64  `json:"running" yaml:"running"`
+	Reserved int64  `json:"reserved" yaml:"reserved"`
+}
+
+// Uncovered returns the number of running nodes billed on demand
+func (c ReservationCoverage) Uncovered() int64 {
+	if c.Running > c.Reserved {
+		return c.Running - c.Reserved
+	}
+	return 0
+}
+
+// Unused returns the number of reserved nodes without a running node to cover
+func (c ReservationCoverage) Unused() int64 {
+	if c.Reserved > c.Running {
+		return c.Reserved - c.Running
+	}
+	return 0
+}
+
+// CoverageReport is the reservation coverage of every node type and engine, sorted by
+// node type and engine
+type CoverageReport []ReservationCoverage
+
+// Coverage returns the share of running nodes covered by reservations, between 0 and 1.
+// It is 1 when nothing is running.
+func (r CoverageReport) Coverage() float64 {
+	var running, covered int64
+	for _, c := range r {
+		running += c.Running
+		covered += c.Running - c.Uncovered()
+	}
+	if running == 0 {
+		return 1
+	}
+	return float64(covered) / float64(running)
+}
+
+// ListECReservedNodes returns every ElastiCache reserved cache node purchase in the region,
+// iterating all pages of DescribeReservedCacheNodes
+func (a *Config) ListECReservedNodes() ([]*Reservation, error) {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeReservedCacheNodesInput{
+		MaxRecords: aws.Int64(listPageSize),
+	}
+
+	list := make([]*Reservation, 0)
+	err := a.Service.Ec.DescribeReservedCacheNodesPages(input, func(page *elasticache.DescribeReservedCacheNodesOutput, lastPage bool) bool {
+		for _, n := range page.ReservedCacheNodes {
+			list = append(list, &Reservation{
+				ID:           aws.StringValue(n.ReservedCacheNodeId),
+				Service:      elasticache.EndpointsID,
+				NodeType:     aws.StringValue(n.CacheNodeType),
+				Engine:       strings.ToLower(aws.StringValue(n.ProductDescription)),
+				Product:      aws.StringValue(n.ProductDescription),
+				Count:        aws.Int64Value(n.CacheNodeCount),
+				OfferingType: aws.StringValue(n.OfferingType),
+				State:        aws.StringValue(n.State),
+				Start:        aws.TimeValue(n.StartTime),
+				Duration:     time.Duration(aws.Int64Value(n.Duration)) * time.Second,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ListRDSReservedInstances returns every RDS reserved DB instance purchase in the region,
+// iterating all pages of DescribeReservedDBInstances
+func (a *Config) ListRDSReservedInstances() ([]*Reservation, error) {
+	if a.Service.Rds == nil {
+		a.SetRDSClient()
+	}
+
+	input := &rds.DescribeReservedDBInstancesInput{
+		MaxRecords: aws.Int64(listPageSize),
+	}
+
+	list := make([]*Reservation, 0)
+	err := a.Service.Rds.DescribeReservedDBInstancesPages(input, func(page *rds.DescribeReservedDBInstancesOutput, lastPage bool) bool {
+		for _, i := range page.ReservedDBInstances {
+			list = append(list, &Reservation{
+				ID:           aws.StringValue(i.ReservedDBInstanceId),
+				Service:      rds.EndpointsID,
+				NodeType:     aws.StringValue(i.DBInstanceClass),
+				Engine:       rdsProductEngine(aws.StringValue(i.ProductDescription)),
+				Product:      aws.StringValue(i.ProductDescription),
+				Count:        aws.Int64Value(i.DBInstanceCount),
+				MultiAZ:      aws.BoolValue(i.MultiAZ),
+				OfferingType: aws.StringValue(i.OfferingType),
+				State:        aws.StringValue(i.State),
+				Start:        aws.TimeValue(i.StartTime),
+				Duration:     time.Duration(aws.Int64Value(i.Duration)) * time.Second,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetECReservationCoverage matches the active reserved cache nodes of the region against
+// the running cache nodes by node type and engine. Size flexibility is not taken into
+// account.
+func (a *Config) GetECReservationCoverage() (CoverageReport, error) {
+	reservations, err := a.ListECReservedNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	m := coverageMatcher{}
+	err = a.EachECCacheCluster(func(c *elasticache.CacheCluster) bool {
+		m.running(aws.StringValue(c.CacheNodeType), aws.StringValue(c.Engine), aws.Int64Value(c.NumCacheNodes))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reservations {
+		if r.Active() {
+			m.reserved(r.NodeType, r.Engine, r.Count)
+		}
+	}
+
+	return m.report(), nil
+}
+
+// GetRDSReservationCoverage matches the active reserved DB instances of the region against
+// the running DB instances by instance class and engine. Counts are in single-AZ
+// instances, so a Multi-AZ instance or reservation counts twice. Size flexibility is not
+// taken into account.
+func (a *Config) GetRDSReservationCoverage() (CoverageReport, error) {
+	reservations, err := a.ListRDSReservedInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	m := coverageMatcher{}
+	err = a.EachRDSDBInstance(func(i *rds.DBInstance) bool {
+		m.running(aws.StringValue(i.DBInstanceClass), rdsProductEngine(aws.StringValue(i.Engine)), azCount(aws.BoolValue(i.MultiAZ)))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reservations {
+		if r.Active() {
+			m.reserved(r.NodeType, r.Engine, r.Count*azCount(r.MultiAZ))
+		}
+	}
+
+	return m.report(), nil
+}
+
+// rdsProductEngines maps the RDS reservation product descriptions that differ from the
+// engine names of DB instances to the engine
+var rdsProductEngines = map[string]string{
+	"postgresql": "postgres",
+	"aurora":     "aurora-mysql", // Aurora MySQL 5.6 compatible instances report the engine aurora
+}
+
+// rdsProductEngine returns the engine of an RDS reservation product description or DB
+// instance engine, so both match: the license model suffix, e.g. (byol) or (li) of
+// oracle-se2(byol), is dropped and postgresql becomes postgres.
+func rdsProductEngine(product string) string {
+	engine := strings.ToLower(strings.TrimSpace(product))
+	if i := strings.Index(engine, "("); i >= 0 {
+		engine = strings.TrimSpace(engine[:i])
+	}
+	if e, ok := rdsProductEngines[engine]; ok {
+		return e
+	}
+	return engine
+}
+
+func azCount(multiAZ bool) int64 {
+	if multiAZ {
+		return 2
+	}
+	return 1
+}
+
+// coverageMatcher accumulates running and reserved counts by node type and engine
+type coverageMatcher map[[2]string]*ReservationCoverage
+
+func (m coverageMatcher) get(nodeType, engine string) *ReservationCoverage {
+	engine = strings.ToLower(engine)
+	key := [2]string{nodeType, engine}
+	c, ok := m[key]
+	if !ok {
+		c = &ReservationCoverage{NodeType: nodeType, Engine: engine}
+		m[key] = c
+	}
+	return c
+}
+
+func (m coverageMatcher) running(nodeType, engine string, count int64) {
+	m.get(nodeType, engine).Running += count
+}
+
+func (m coverageMatcher) reserved(nodeType, engine string, count int64) {
+	m.get(nodeType, engine).Reserved += count
+}
+
+func (m coverageMatcher) report() CoverageReport {
+	report := make(CoverageReport, 0, len(m))
+	for _, c := range m {
+		report = append(report, *c)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].NodeType != report[j].NodeType {
+			return report[i].NodeType < report[j].NodeType
+		}
+		return report[i].Engine < report[j].Engine
+	})
+	return report
+}
@@ -0,0 +1,122 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// defaultRestoreTimeout bounds how long SpinUpFromSnapshot waits for the group to be available
+const defaultRestoreTimeout = 45 * time.Minute
+
+// RestoreOptions configures the temporary replication group created by SpinUpFromSnapshot.
+// Only the snapshot is required, all other settings default to those of the snapshot.
+type RestoreOptions struct {
+	ReplicationGroupID string        // optional: defaults to awsx-restore-<unix time>
+	NodeType           string        // optional: cache node type, e.g. cache.t4g.small
+	SubnetGroup        string        // optional: cache subnet group to launch into
+	SecurityGroupIDs   []string      // optional: VPC security groups for the group
+	Timeout            time.Duration // optional: how long to wait for creation and deletion
+}
+
+// EphemeralCache is a temporary replication group restored from a snapshot
+type EphemeralCache struct {
+	ReplicationGroupID string
+	Endpoints          *RedisEndpoints
+	config             *Config
+	timeout            time.Duration
+}
+
+// SpinUpFromSnapshot creates a temporary replication group from an ElastiCache snapshot,
+// waits for it to become available and returns its endpoints. Callers should defer
+// TearDown() on the result so the group is deleted once verification is complete.
+func (a *Config) SpinUpFromSnapshot(snapshot string, opts *RestoreOptions) (*EphemeralCache, error) {
+	if snapshot == "" {
+		return nil, errors.New("no snapshot name provided")
+	}
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	ec := &EphemeralCache{
+		ReplicationGroupID: opts.ReplicationGroupID,
+		config:             a,
+		timeout:            opts.Timeout,
+	}
+	if ec.ReplicationGroupID == "" {
+		ec.ReplicationGroupID = "awsx-restore-" + strconv.FormatInt(a.clock().Now().Unix(), 10)
+	}
+	if ec.timeout == 0 {
+		ec.timeout = defaultRestoreTimeout
+	}
+
+	input := &elasticache.CreateReplicationGroupInput{
+		ReplicationGroupId:          aws.String(ec.ReplicationGroupID),
+		ReplicationGroupDescription: aws.String("awsx restore of snapshot " + snapshot),
+		SnapshotName:                aws.String(snapshot),
+		Tags: []*elasticache.Tag{
+			{Key: aws.String("awsx:restored-from"), Value: aws.String(snapshot)},
+		},
+	}
+	if opts.NodeType != "" {
+		input.CacheNodeType = aws.String(opts.NodeType)
+	}
+	if opts.SubnetGroup != "" {
+		input.CacheSubnetGroupName = aws.String(opts.SubnetGroup)
+	}
+	if len(opts.SecurityGroupIDs) > 0 {
+		input.SecurityGroupIds = aws.StringSlice(opts.SecurityGroupIDs)
+	}
+
+	if _, err := a.Service.Ec.CreateReplicationGroup(input); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ec.timeout)
+	defer cancel()
+
+	err := a.Service.Ec.WaitUntilReplicationGroupAvailableWithContext(ctx, &elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(ec.ReplicationGroupID),
+	})
+	if err != nil {
+		return ec, err
+	}
+
+	ec.Endpoints, err = a.GetRedisPrimaryEndpoint(ec.ReplicationGroupID)
+	if err != nil {
+		return ec, err
+	}
+
+	return ec, nil
+}
+
+// TearDown deletes the temporary replication group without a final snapshot and
+// waits for the deletion to complete
+func (ec *EphemeralCache) TearDown() error {
+	if ec == nil || ec.config == nil {
+		return nil
+	}
+
+	_, err := ec.config.Service.Ec.DeleteReplicationGroup(&elasticache.DeleteReplicationGroupInput{
+		ReplicationGroupId:   aws.String(ec.ReplicationGroupID),
+		RetainPrimaryCluster: aws.Bool(false),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ec.timeout)
+	defer cancel()
+
+	return ec.config.Service.Ec.WaitUntilReplicationGroupDeletedWithContext(ctx, &elasticache.DescribeReplicationGroupsInput{
+		ReplicationGroupId: aws.String(ec.ReplicationGroupID),
+	})
+}
@@ -0,0 +1,90 @@
+package awsx
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// RetryPolicy configures how AWS calls are retried. Retries use exponential backoff
+// with jitter, with a separate and longer delay range for throttling errors so bursts
+// of Describe calls back off instead of failing.
+type RetryPolicy struct {
+	MaxRetries       int
+	MinDelay         time.Duration   // minimum delay before retrying a failed call
+	MaxDelay         time.Duration   // maximum delay before retrying a failed call
+	MinThrottleDelay time.Duration   // minimum delay before retrying a throttled call
+	MaxThrottleDelay time.Duration   // maximum delay before retrying a throttled call
+	Retryer          request.Retryer // optional: custom backoff strategy, overrides the above
+}
+
+// DefaultRetryPolicy returns the retry policy tuned for discovery, which tolerates
+// ElastiCache and RDS Describe throttling during startup bursts
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:       5,
+		MinDelay:         client.DefaultRetryerMinRetryDelay,
+		MaxDelay:         client.DefaultRetryerMaxRetryDelay,
+		MinThrottleDelay: client.DefaultRetryerMinThrottleDelay,
+		MaxThrottleDelay: 30 * time.Second,
+	}
+}
+
+// NewRetryer returns the request.Retryer described by the policy
+func (p *RetryPolicy) NewRetryer() request.Retryer {
+	if p.Retryer != nil {
+		return p.Retryer
+	}
+
+	return client.DefaultRetryer{
+		NumMaxRetries:    p.MaxRetries,
+		MinRetryDelay:    p.MinDelay,
+		MaxRetryDelay:    p.MaxDelay,
+		MinThrottleDelay: p.MinThrottleDelay,
+		MaxThrottleDelay: p.MaxThrottleDelay,
+	}
+}
+
+// Option returns a request.Option applying the policy to a single SDK call made with
+// one of the *WithContext operations
+func (p *RetryPolicy) Option() request.Option {
+	return func(r *request.Request) {
+		r.Retryer = p.NewRetryer()
+	}
+}
+
+// SetRetryPolicy sets the retry policy used by all service clients
+func (a *Config) SetRetryPolicy(policy *RetryPolicy) *Config {
+	a.RetryPolicy = policy
+	return a
+}
+
+// SetMaxRetries sets the maximum number of retries, keeping the other settings of the
+// current retry policy or the DefaultRetryPolicy
+func (a *Config) SetMaxRetries(retries int) *Config {
+	if a.RetryPolicy == nil {
+		a.RetryPolicy = DefaultRetryPolicy()
+	}
+	a.RetryPolicy.MaxRetries = retries
+	return a
+}
+
+// RetryOverride returns a copy of the Config whose service clients use the retry policy
+// so that individual calls can be made with a different policy:
+//
+//	res, err := a.RetryOverride(policy).GetRedisPrimaryEndpoint("cluster-name")
+func (a *Config) RetryOverride(policy *RetryPolicy) *Config {
+	if a.Session == nil {
+		a.SetSession()
+	}
+
+	c := *a
+	c.RetryPolicy = policy
+	c.Service = &Services{}
+	if a.Session != nil {
+		c.Session = a.Session.Copy(request.WithRetryer(a.Session.Config.Copy(), policy.NewRetryer()))
+	}
+
+	return &c
+}
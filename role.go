@@ -0,0 +1,218 @@
+package awsx
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// defaultRoleSessionName prefixes the session name of assumed roles without one
+const defaultRoleSessionName = "awsx"
+
+// RoleHop is a single AssumeRole call in a role chain
+type RoleHop struct {
+	ARN         string
+	ExternalID  string        // optional: external ID required by the role's trust policy
+	SessionName string        // optional: defaults to awsx-<hop number>
+	Duration    time.Duration // optional: defaults to 15 minutes, chained roles are limited to 1 hour
+
+	SourceIdentity    string            // optional: source identity recorded in CloudTrail and passed to later hops
+	Tags              map[string]string // optional: session tags for attribute-based access control
+	TransitiveTagKeys []string          // optional: session tag keys passed on to later hops
+}
+
+// WithRole replaces the credential chain with one that assumes Config.Role using the
+// ExternalID, SourceIdentity, SessionTags and TransitiveTagKeys set on the Config, with
+// the credentials of the providers added so far
+func (a *Config) WithRole() *Config {
+	if a.Role == "" {
+		a.warn("No role specified in Config.Role for call to WithRole()")
+		return a
+	}
+
+	return a.WithRoleHops(RoleHop{
+		ARN:               a.Role,
+		ExternalID:        a.ExternalID,
+		SourceIdentity:    a.SourceIdentity,
+		Tags:              a.SessionTags,
+		TransitiveTagKeys: a.TransitiveTagKeys,
+	})
+}
+
+// SetSessionTags sets the session tags, and which of them are transitive, used by WithRole
+func (a *Config) SetSessionTags(tags map[string]string, transitive ...string) *Config {
+	a.SessionTags = tags
+	a.TransitiveTagKeys = transitive
+	return a
+}
+
+// WithRoleChain replaces the credential chain with one that assumes each role in turn,
+// e.g. a role in a jump account and then a role in the target account. The first role
+// is assumed with the credentials of the providers added so far, so the With*() methods
+// for the base credentials must be called first.
+func (a *Config) WithRoleChain(arns ...string) *Config {
+	hops := make([]RoleHop, 0, len(arns))
+	for _, arn := range arns {
+		hops = append(hops, RoleHop{ARN: arn})
+	}
+	return a.WithRoleHops(hops...)
+}
+
+// WithRoleHops is WithRoleChain with per-hop external IDs, session names and durations
+func (a *Config) WithRoleHops(hops ...RoleHop) *Config {
+	if len(hops) == 0 {
+		a.warn("No roles specified in call to WithRoleHops(hops ...RoleHop)")
+		return a
+	}
+	if err := a.roleHops(hops...); err != nil {
+		a.warn(err.Error())
+		if a.panicOnErr {
+			panic(err.Error())
+		}
+	}
+
+	return a
+}
+
+// roleHops replaces the credential chain with the role chain of the hops, or returns an
+// error leaving the chain as it is
+func (a *Config) roleHops(hops ...RoleHop) error {
+	if len(hops) == 0 {
+		return errors.New("no roles to assume")
+	}
+	if len(a.Providers) == 0 {
+		return errors.New("no base credential providers to assume roles with")
+	}
+	for i, hop := range hops {
+		if hop.ARN == "" {
+			return errors.New("no role arn provided for role hop " + strconv.Itoa(i+1))
+		}
+	}
+
+	base := credentials.NewCredentials(&chainProvider{providers: a.Providers})
+	creds := base
+	chain := make([]*credentials.Credentials, 0, len(hops))
+	for i, hop := range hops {
+		p := a.assumeRoleProvider(creds, hop, i+1)
+		p = a.cacheCredentialsKey(p, "assume-role", roleCacheKey(base, a.Profile, hops[:i+1]))
+		creds = credentials.NewCredentials(p)
+		chain = append(chain, creds)
+	}
+
+	a.Role = hops[len(hops)-1].ARN
+	a.Providers = []credentials.Provider{&chainedProvider{creds: creds, hops: chain}}
+
+	return nil
+}
+
+// roleCacheKey returns the credential cache key parts of the last hop of a role chain:
+// the identity of the base credentials and every AssumeRole parameter of every hop up
+// to it, so chains that differ in any of them never share cached credentials
+func roleCacheKey(base *credentials.Credentials, profile string, hops []RoleHop) func() ([]string, error) {
+	return func() ([]string, error) {
+		v, err := base.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		parts := []string{"assume-role", profile, v.AccessKeyID}
+		for i, hop := range hops {
+			tags := make([]string, 0, len(hop.Tags))
+			for k, v := range hop.Tags {
+				tags = append(tags, k+"="+v)
+			}
+			sort.Strings(tags)
+			transitive := append([]string(nil), hop.TransitiveTagKeys...)
+			sort.Strings(transitive)
+
+			parts = append(parts,
+				strconv.Itoa(i+1), hop.ARN, hop.SessionName, hop.ExternalID, hop.Duration.String(),
+				hop.SourceIdentity, strings.Join(tags, ","), strings.Join(transitive, ","),
+			)
+		}
+		return parts, nil
+	}
+}
+
+// assumeRoleProvider returns a provider assuming the role of the hop with the credentials.
+// The STS session is only created when credentials are first needed.
+func (a *Config) assumeRoleProvider(creds *credentials.Credentials, hop RoleHop, n int) credentials.Provider {
+	return newLazyProvider(func() (credentials.Provider, error) {
+		sess, err := a.stsSession(creds)
+		if err != nil {
+			return nil, err
+		}
+		return newAssumeRoleProvider(sts.New(sess), hop, n), nil
+	})
+}
+
+// newAssumeRoleProvider returns the stscreds provider for the hop
+func newAssumeRoleProvider(client *sts.STS, hop RoleHop, n int) *stscreds.AssumeRoleProvider {
+	p := &stscreds.AssumeRoleProvider{
+		Client:          client,
+		RoleARN:         hop.ARN,
+		RoleSessionName: hop.SessionName,
+		Duration:        hop.Duration,
+		ExpiryWindow:    stscreds.DefaultDuration / 10,
+	}
+	if p.RoleSessionName == "" {
+		p.RoleSessionName = defaultRoleSessionName + "-" + strconv.Itoa(n)
+	}
+	if p.Duration == 0 {
+		p.Duration = stscreds.DefaultDuration
+	}
+	if hop.ExternalID != "" {
+		p.ExternalID = aws.String(hop.ExternalID)
+	}
+	if hop.SourceIdentity != "" {
+		p.SourceIdentity = aws.String(hop.SourceIdentity)
+	}
+	for k, v := range hop.Tags {
+		p.Tags = append(p.Tags, &sts.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	if len(hop.TransitiveTagKeys) > 0 {
+		p.TransitiveTagKeys = aws.StringSlice(hop.TransitiveTagKeys)
+	}
+
+	return p
+}
+
+// chainedProvider adapts the credentials of the last hop of a role chain to a Provider
+type chainedProvider struct {
+	creds *credentials.Credentials
+	hops  []*credentials.Credentials // credentials of every hop, the last one is creds
+}
+
+// Retrieve returns the credentials of the last role in the chain
+func (p *chainedProvider) Retrieve() (credentials.Value, error) {
+	return p.creds.Get()
+}
+
+// IsExpired reports whether the credentials of the last role in the chain have expired
+func (p *chainedProvider) IsExpired() bool {
+	return p.creds.IsExpired()
+}
+
+// Expire expires the credentials of every hop, so the next Retrieve assumes each role
+// again instead of returning credentials the hops still hold
+func (p *chainedProvider) Expire() {
+	for _, creds := range p.hops {
+		creds.Expire()
+	}
+}
+
+// ExpiresAt returns the expiry of the credentials of the last role in the chain
+func (p *chainedProvider) ExpiresAt() time.Time {
+	expires, err := p.creds.ExpiresAt()
+	if err != nil {
+		return time.Time{}
+	}
+	return expires
+}
@@ -0,0 +1,247 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+)
+
+// defaultRecordTTL is the TTL of records upserted without one, short so clients follow
+// topology changes quickly
+const defaultRecordTTL = 60
+
+// GetRoute53Client returns a client for use with Amazon Route 53
+func (a *Config) GetRoute53Client() route53iface.Route53API {
+	return a.Service.R53
+}
+
+// SetRoute53Client sets a client for use with Amazon Route 53
+func (a *Config) SetRoute53Client() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.R53 = route53.New(a.ClientConfig(route53.EndpointsID))
+
+	return a
+}
+
+// WithRoute53Client sets the client used for Amazon Route 53 calls, such as a mock from
+// the awsxmock package
+func (a *Config) WithRoute53Client(client route53iface.Route53API) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.R53 = client
+
+	return a
+}
+
+// DNSRecord is a resource record set of a hosted zone
+type DNSRecord struct {
+	Name          string   `json:"name" yaml:"name"`
+	Type          string   `json:"type" yaml:"type"`
+	TTL           int64    `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Values        []string `json:"values,omitempty" yaml:"values,omitempty"`
+	AliasTarget   string   `json:"alias_target,omitempty" yaml:"alias_target,omitempty"`
+	SetIdentifier string   `json:"set_identifier,omitempty" yaml:"set_identifier,omitempty"`
+	Weight        *int64   `json:"weight,omitempty" yaml:"weight,omitempty"` // nil unless the record is weighted
+	HealthCheckID string   `json:"health_check_id,omitempty" yaml:"health_check_id,omitempty"`
+}
+
+// GetHostedZoneID returns the ID of the public or private hosted zone with the domain name
+func (a *Config) GetHostedZoneID(domain string, private bool) (string, error) {
+	if domain == "" {
+		return "", errors.New("no domain name provided")
+	}
+
+	if a.Service.R53 == nil {
+		a.SetRoute53Client()
+	}
+
+	domain = fqdn(domain)
+	input := &route53.ListHostedZonesByNameInput{DNSName: aws.String(domain)}
+	for {
+		out, err := a.Service.R53.ListHostedZonesByName(input)
+		if err != nil {
+			return "", err
+		}
+		for _, z := range out.HostedZones {
+			if aws.StringValue(z.Name) != domain {
+				return "", errors.New("no hosted zone found for " + domain)
+			}
+			if z.Config != nil && aws.BoolValue(z.Config.PrivateZone) == private {
+				return strings.TrimPrefix(aws.StringValue(z.Id), "/hostedzone/"), nil
+			}
+		}
+		if !aws.BoolValue(out.IsTruncated) {
+			break
+		}
+		input.DNSName = out.NextDNSName
+		input.HostedZoneId = out.NextHostedZoneId
+	}
+
+	return "", errors.New("no hosted zone found for " + domain)
+}
+
+// GetDNSRecords returns every record set of the zone with the name, such as the CNAMEs
+// fronting ElastiCache endpoints or the weighted records of UpsertWeightedRecords
+func (a *Config) GetDNSRecords(zoneID, name string) ([]*DNSRecord, error) {
+	if zoneID == "" || name == "" {
+		return nil, errors.New("must provide a hosted zone ID and record name")
+	}
+
+	if a.Service.R53 == nil {
+		a.SetRoute53Client()
+	}
+
+	name = fqdn(name)
+	input := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(zoneID),
+		StartRecordName: aws.String(name),
+	}
+
+	records := make([]*DNSRecord, 0)
+	err := a.Service.R53.ListResourceRecordSetsPages(input, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+		for _, rs := range page.ResourceRecordSets {
+			if !strings.EqualFold(unescapeDNSName(aws.StringValue(rs.Name)), name) {
+				return false
+			}
+			records = append(records, dnsRecord(rs))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// WeightedRecord is a weighted record upserted by UpsertWeightedRecords
+type WeightedRecord struct {
+	Name          string
+	Type          string // optional: defaults to CNAME
+	Value         string
+	SetIdentifier string // optional: defaults to Value
+	Weight        int64
+	TTL           int64  // optional: defaults to 60 seconds
+	HealthCheckID string // optional: Route 53 health check of the value
+}
+
+// UpsertWeightedRecords creates or updates weighted records in one change batch, e.g. to
+// publish the discovered reader endpoints of a cluster behind a single DNS name. It
+// returns the change ID, see Route53ChangeSynced.
+func (a *Config) UpsertWeightedRecords(ctx context.Context, zoneID string, records []WeightedRecord) (string, error) {
+	if zoneID == "" || len(records) == 0 {
+		return "", errors.New("must provide a hosted zone ID and records")
+	}
+
+	if a.Service.R53 == nil {
+		a.SetRoute53Client()
+	}
+
+	changes := make([]*route53.Change, 0, len(records))
+	for _, r := range records {
+		rs := &route53.ResourceRecordSet{
+			Name:            aws.String(fqdn(r.Name)),
+			Type:            aws.String(stringOr(r.Type, route53.RRTypeCname)),
+			SetIdentifier:   aws.String(stringOr(r.SetIdentifier, r.Value)),
+			Weight:          aws.Int64(r.Weight),
+			TTL:             aws.Int64(r.TTL),
+			ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(r.Value)}},
+		}
+		if r.TTL <= 0 {
+			rs.TTL = aws.Int64(defaultRecordTTL)
+		}
+		if r.HealthCheckID != "" {
+			rs.HealthCheckId = aws.String(r.HealthCheckID)
+		}
+		changes = append(changes, &route53.Change{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: rs})
+	}
+
+	out, err := a.Service.R53.ChangeResourceRecordSetsWithContext(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+		ChangeBatch:  &route53.ChangeBatch{Changes: changes, Comment: aws.String("awsx discovery")},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(out.ChangeInfo.Id), nil
+}
+
+// Route53ChangeSynced reports whether a change has propagated to every Route 53 server
+func (a *Config) Route53ChangeSynced(changeID string) (bool, error) {
+	if a.Service.R53 == nil {
+		a.SetRoute53Client()
+	}
+
+	out, err := a.Service.R53.GetChange(&route53.GetChangeInput{Id: aws.String(changeID)})
+	if err != nil {
+		return false, err
+	}
+
+	return aws.StringValue(out.ChangeInfo.Status) == route53.ChangeStatusInsync, nil
+}
+
+// GetHealthCheckHealthy reports whether every Route 53 health checker reports the health
+// check as passing
+func (a *Config) GetHealthCheckHealthy(healthCheckID string) (bool, error) {
+	if healthCheckID == "" {
+		return false, errors.New("no health check ID provided")
+	}
+
+	if a.Service.R53 == nil {
+		a.SetRoute53Client()
+	}
+
+	out, err := a.Service.R53.GetHealthCheckStatus(&route53.GetHealthCheckStatusInput{HealthCheckId: aws.String(healthCheckID)})
+	if err != nil {
+		return false, err
+	}
+	if len(out.HealthCheckObservations) == 0 {
+		return false, errors.New("no observations for health check " + healthCheckID)
+	}
+	for _, o := range out.HealthCheckObservations {
+		if o.StatusReport == nil || !strings.HasPrefix(aws.StringValue(o.StatusReport.Status), "Success") {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func dnsRecord(rs *route53.ResourceRecordSet) *DNSRecord {
+	r := &DNSRecord{
+		Name:          unescapeDNSName(aws.StringValue(rs.Name)),
+		Type:          aws.StringValue(rs.Type),
+		TTL:           aws.Int64Value(rs.TTL),
+		SetIdentifier: aws.StringValue(rs.SetIdentifier),
+		Weight:        rs.Weight,
+		HealthCheckID: aws.StringValue(rs.HealthCheckId),
+	}
+	for _, v := range rs.ResourceRecords {
+		r.Values = append(r.Values, aws.StringValue(v.Value))
+	}
+	if rs.AliasTarget != nil {
+		r.AliasTarget = aws.StringValue(rs.AliasTarget.DNSName)
+	}
+	return r
+}
+
+// fqdn returns the name with a trailing dot, as Route 53 returns names
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// unescapeDNSName decodes the octal escape of * that Route 53 uses in wildcard names
+func unescapeDNSName(name string) string {
+	return strings.Replace(name, `\052`, "*", -1)
+}
@@ -0,0 +1,180 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// GetS3Client returns a client for use with AWS S3
+func (a *Config) GetS3Client() s3iface.S3API {
+	return a.Service.S3
+}
+
+// SetS3Client sets a client for use with AWS S3
+func (a *Config) SetS3Client() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.S3 = s3.New(a.ClientConfig(s3.EndpointsID))
+
+	return a
+}
+
+// WithS3Client sets the client used for AWS S3 calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithS3Client(client s3iface.S3API) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.S3 = client
+
+	return a
+}
+
+// defaultPresignExpiry is the validity of presigned URLs created with a zero expiry
+const defaultPresignExpiry = 15 * time.Minute
+
+// GetBucketRegion returns the region of the bucket. The result is cached like discovery
+// results when a CacheTTL is set.
+func (a *Config) GetBucketRegion(bucket string) (string, error) {
+	if bucket == "" {
+		return "", errors.New("no bucket name provided")
+	}
+
+	if a.Service.S3 == nil {
+		a.SetS3Client()
+	}
+
+	v, err := a.cached("s3region:"+bucket, func() (interface{}, error) {
+		loc, err := a.Service.S3.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: aws.String(bucket)})
+		if err != nil {
+			return nil, err
+		}
+		return s3.NormalizeBucketLocation(aws.StringValue(loc.LocationConstraint)), nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return v.(string), nil
+}
+
+// bucketConfig returns the Config for the region of the bucket, so that requests are
+// signed for the region the bucket is in
+func (a *Config) bucketConfig(bucket string) (*Config, error) {
+	region, err := a.GetBucketRegion(bucket)
+	if err != nil {
+		return nil, err
+	}
+	if region == a.GetRegion() {
+		if a.Service.S3 == nil {
+			a.SetS3Client()
+		}
+		return a, nil
+	}
+
+	rc := a.ForRegion(region)
+	if rc.Service.S3 == nil {
+		rc.SetS3Client()
+	}
+	return rc, nil
+}
+
+// PresignGetURL returns a URL to download the object without credentials, valid for
+// expires or 15 minutes when expires is zero
+func (a *Config) PresignGetURL(bucket, key string, expires time.Duration) (string, error) {
+	bc, err := a.bucketConfig(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := bc.Service.S3.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(presignExpiry(expires))
+}
+
+// PresignPutURL returns a URL to upload the object without credentials, valid for expires
+// or 15 minutes when expires is zero. When contentType is not empty, the upload must send
+// the same Content-Type header.
+func (a *Config) PresignPutURL(bucket, key, contentType string, expires time.Duration) (string, error) {
+	bc, err := a.bucketConfig(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	req, _ := bc.Service.S3.PutObjectRequest(input)
+	return req.Presign(presignExpiry(expires))
+}
+
+func presignExpiry(expires time.Duration) time.Duration {
+	if expires <= 0 {
+		return defaultPresignExpiry
+	}
+	return expires
+}
+
+// UploadOptions configures Upload
+type UploadOptions struct {
+	ContentType string // optional: Content-Type of the object
+	KMSKeyID    string // optional: encrypt the object with SSE-KMS using this key
+	PartSize    int64  // optional: size of the multipart upload parts, defaults to 5 MiB
+	Concurrency int    // optional: parts uploaded in parallel, defaults to 5
+}
+
+// Upload streams body to the object with a multipart upload, so that objects of unknown
+// size can be written without buffering them in memory, and returns the object URL
+func (a *Config) Upload(ctx context.Context, bucket, key string, body io.Reader, opts *UploadOptions) (string, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	bc, err := a.bucketConfig(bucket)
+	if err != nil {
+		return "", err
+	}
+
+	uploader := s3manager.NewUploaderWithClient(bc.Service.S3, func(u *s3manager.Uploader) {
+		if opts.PartSize > 0 {
+			u.PartSize = opts.PartSize
+		}
+		if opts.Concurrency > 0 {
+			u.Concurrency = opts.Concurrency
+		}
+	})
+
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if opts.KMSKeyID != "" {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		input.SSEKMSKeyId = aws.String(opts.KMSKeyID)
+	}
+
+	out, err := uploader.UploadWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return out.Location, nil
+}
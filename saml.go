@@ -0,0 +1,102 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// SAMLAssertionProvider retrieves a base64 encoded SAML response from an identity
+// provider such as ADFS or Okta, or from a custom identity broker. It is called every
+// time the assumed role credentials are refreshed, as assertions are short lived.
+type SAMLAssertionProvider interface {
+	Assertion(ctx context.Context) (string, error)
+}
+
+// SAMLAssertionFunc adapts a function to a SAMLAssertionProvider
+type SAMLAssertionFunc func(ctx context.Context) (string, error)
+
+// Assertion calls f
+func (f SAMLAssertionFunc) Assertion(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithSAML adds a provider to the credential chain that assumes Config.Role with
+// AssumeRoleWithSAML, using the assertions of the assertion provider and the identity
+// provider in Config.SAMLProvider. No other credentials are needed, so it can be the
+// only provider in the chain. The STS session is only created when credentials are
+// first needed.
+func (a *Config) WithSAML(assertion SAMLAssertionProvider) *Config {
+	if a.Role == "" || a.SAMLProvider == "" || assertion == nil {
+		a.warn("No role, SAML provider or assertion provider specified in call to WithSAML(assertion SAMLAssertionProvider)")
+		if a.panicOnErr {
+			panic("No role, SAML provider or assertion provider specified")
+		}
+		return a
+	}
+
+	roleARN, principalARN := a.Role, a.SAMLProvider
+	p := newLazyProvider(func() (credentials.Provider, error) {
+		sess, err := a.stsSession(nil)
+		if err != nil {
+			return nil, err
+		}
+		return &samlRoleProvider{
+			client:       sts.New(sess),
+			roleARN:      roleARN,
+			principalARN: principalARN,
+			assertion:    assertion,
+		}, nil
+	})
+	a.Providers = append(a.Providers, a.cacheCredentials(p, "saml", roleARN, principalARN))
+
+	return a
+}
+
+// samlRoleProvider assumes a role with a SAML assertion
+type samlRoleProvider struct {
+	credentials.Expiry
+
+	client       stsiface.STSAPI
+	roleARN      string
+	principalARN string
+	assertion    SAMLAssertionProvider
+}
+
+// Retrieve assumes the role with a new assertion
+func (p *samlRoleProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(context.Background())
+}
+
+// RetrieveWithContext assumes the role with a new assertion, passing ctx to the
+// assertion provider and STS
+func (p *samlRoleProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	assertion, err := p.assertion.Assertion(ctx)
+	if err != nil {
+		return credentials.Value{}, errors.New("retrieving SAML assertion: " + err.Error())
+	}
+
+	out, err := p.client.AssumeRoleWithSAMLWithContext(ctx, &sts.AssumeRoleWithSAMLInput{
+		RoleArn:         aws.String(p.roleARN),
+		PrincipalArn:    aws.String(p.principalARN),
+		SAMLAssertion:   aws.String(assertion),
+		DurationSeconds: aws.Int64(int64(stscreds.DefaultDuration.Seconds())),
+	})
+	if err != nil {
+		return credentials.Value{}, err
+	}
+
+	p.SetExpiration(aws.TimeValue(out.Credentials.Expiration), stscreds.DefaultDuration/10)
+
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(out.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(out.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(out.Credentials.SessionToken),
+		ProviderName:    "SAMLRoleProvider",
+	}, nil
+}
@@ -0,0 +1,137 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// defaultShutdownTimeout bounds how long shutdown hooks may run in total
+const defaultShutdownTimeout = 30 * time.Second
+
+// ShutdownFunc is a teardown hook run on shutdown. The context is cancelled when the
+// shutdown timeout expires.
+type ShutdownFunc func(ctx context.Context) error
+
+// Shutdown runs registered teardown hooks once, when the parent context is done, one of
+// the signals is received, or Trigger is called. Hooks run in reverse order of
+// registration so that resources are torn down in the reverse order they were created.
+type Shutdown struct {
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	hooks   []shutdownHook
+	once    sync.Once
+	done    chan struct{}
+	trigger chan struct{}
+	err     error
+}
+
+type shutdownHook struct {
+	name string
+	fn   ShutdownFunc
+}
+
+// RegisterShutdown returns a Shutdown that is triggered when ctx is done or one of the
+// signals is received. With no signals, SIGINT and SIGTERM are used.
+func RegisterShutdown(ctx context.Context, signals ...os.Signal) *Shutdown {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	s := &Shutdown{
+		Timeout: defaultShutdownTimeout,
+		done:    make(chan struct{}),
+		trigger: make(chan struct{}),
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-sig:
+		case <-s.trigger:
+		}
+		signal.Stop(sig)
+		s.run()
+	}()
+
+	return s
+}
+
+// Add registers a named teardown hook
+func (s *Shutdown) Add(name string, fn ShutdownFunc) *Shutdown {
+	s.mu.Lock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, fn: fn})
+	s.mu.Unlock()
+	return s
+}
+
+// AddWatcher stops the watcher, closing its subscriptions, on shutdown
+func (s *Shutdown) AddWatcher(w *Watcher) *Shutdown {
+	return s.Add("watcher "+w.Cluster, func(ctx context.Context) error {
+		w.Stop()
+		return nil
+	})
+}
+
+// AddCanary stops the canary on shutdown
+func (s *Shutdown) AddCanary(c *Canary) *Shutdown {
+	return s.Add("canary "+c.Name, func(ctx context.Context) error {
+		c.Stop()
+		return nil
+	})
+}
+
+// AddCredentialRefresher stops the credential refresher on shutdown
+func (s *Shutdown) AddCredentialRefresher(r *CredentialRefresher) *Shutdown {
+	return s.Add("credential refresher", func(ctx context.Context) error {
+		r.Stop()
+		return nil
+	})
+}
+
+// Trigger starts the shutdown without waiting for a signal
+func (s *Shutdown) Trigger() {
+	s.once.Do(func() { close(s.trigger) })
+}
+
+// Done is closed once every hook has returned
+func (s *Shutdown) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait blocks until shutdown has completed and returns the errors of failed hooks
+func (s *Shutdown) Wait() error {
+	<-s.done
+	return s.err
+}
+
+func (s *Shutdown) run() {
+	s.mu.Lock()
+	hooks := append([]shutdownHook(nil), s.hooks...)
+	timeout := s.Timeout
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	failed := make([]string, 0)
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(ctx); err != nil {
+			failed = append(failed, hooks[i].name+": "+err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		s.err = errors.New("shutdown hooks failed: " + strings.Join(failed, "; "))
+	}
+	close(s.done)
+}
@@ -0,0 +1,228 @@
+package awsx
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// redisSlots is the number of hash slots of a Redis Cluster
+const redisSlots = 16384
+
+// RedisShard is a node group of a cluster mode enabled replication group with the hash
+// slots it serves
+type RedisShard struct {
+	ID         string           `json:"id" yaml:"id"`
+	Slots      string           `json:"slots" yaml:"slots"` // as reported by ElastiCache, e.g. 0-5460
+	SlotRanges []SlotRange      `json:"slot_ranges" yaml:"slot_ranges"`
+	Nodes      []*RedisEndpoint `json:"nodes" yaml:"nodes"`
+}
+
+// SlotRange is an inclusive range of hash slots
+type SlotRange struct {
+	Start int `json:"start" yaml:"start"`
+	End   int `json:"end" yaml:"end"`
+}
+
+// Contains reports whether the slot is in the range
+func (sr SlotRange) Contains(slot int) bool {
+	return slot >= sr.Start && slot <= sr.End
+}
+
+// Contains reports whether the shard serves the slot
+func (s *RedisShard) Contains(slot int) bool {
+	for _, sr := range s.SlotRanges {
+		if sr.Contains(slot) {
+			return true
+		}
+	}
+	return false
+}
+
+// Primary returns the primary node of the shard, or nil if the role is not known.
+// ElastiCache only reports node roles for cluster mode disabled groups; for cluster
+// mode enabled use CLUSTER SHARDS against the configuration endpoint.
+func (s *RedisShard) Primary() *RedisEndpoint {
+	for _, n := range s.Nodes {
+		if n.Role == RolePrimary {
+			return n
+		}
+	}
+	return nil
+}
+
+// Replicas returns the nodes of the shard known to be replicas
+func (s *RedisShard) Replicas() []*RedisEndpoint {
+	replicas := make([]*RedisEndpoint, 0, len(s.Nodes))
+	for _, n := range s.Nodes {
+		if n.Role == RoleReplica {
+			replicas = append(replicas, n)
+		}
+	}
+	return replicas
+}
+
+// ShardForSlot returns the shard serving the slot, or nil if no shard does
+func (res *RedisEndpoints) ShardForSlot(slot int) *RedisShard {
+	for _, s := range res.Shards {
+		if s.Contains(slot) {
+			return s
+		}
+	}
+	return nil
+}
+
+// ShardForKey returns the shard serving the key, see KeySlot
+func (res *RedisEndpoints) ShardForKey(key string) *RedisShard {
+	return res.ShardForSlot(KeySlot(key))
+}
+
+// ParseSlotRanges parses the slots of a node group as reported by ElastiCache: comma
+// separated ranges such as 0-5460 or single slots
+func ParseSlotRanges(slots string) ([]SlotRange, error) {
+	ranges := make([]SlotRange, 0)
+	for _, part := range strings.Split(slots, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, errors.New("invalid slot range " + part)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, errors.New("invalid slot range " + part)
+			}
+		}
+		if start < 0 || end >= redisSlots || start > end {
+			return nil, errors.New("invalid slot range " + part)
+		}
+
+		ranges = append(ranges, SlotRange{Start: start, End: end})
+	}
+
+	return ranges, nil
+}
+
+// KeySlot returns the hash slot of a key as computed by Redis Cluster: CRC16 of the key,
+// or of its hash tag between the first { and the following }, modulo 16384
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % redisSlots)
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum used by Redis Cluster
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// redisShardsFromGroup builds the shards of a cluster mode enabled replication group
+// with the nodes known from the group description
+func redisShardsFromGroup(rg *elasticache.ReplicationGroup) []*RedisShard {
+	shards := make([]*RedisShard, 0, len(rg.NodeGroups))
+	for _, ng := range rg.NodeGroups {
+		slots := aws.StringValue(ng.Slots)
+		ranges, err := ParseSlotRanges(slots)
+		if err != nil {
+			ranges = make([]SlotRange, 0)
+		}
+
+		shard := &RedisShard{
+			ID:         aws.StringValue(ng.NodeGroupId),
+			Slots:      slots,
+			SlotRanges: ranges,
+			Nodes:      make([]*RedisEndpoint, 0, len(ng.NodeGroupMembers)),
+		}
+		for _, m := range ng.NodeGroupMembers {
+			node := &RedisEndpoint{
+				Slots:            slots,
+				AvailabilityZone: aws.StringValue(m.PreferredAvailabilityZone),
+				CacheClusterID:   aws.StringValue(m.CacheClusterId),
+				Role:             EndpointRole(aws.StringValue(m.CurrentRole)),
+				NodeType:         aws.StringValue(rg.CacheNodeType),
+				TLS:              aws.BoolValue(rg.TransitEncryptionEnabled),
+			}
+			if m.ReadEndpoint != nil {
+				node.Host = aws.StringValue(m.ReadEndpoint.Address)
+				node.Port = int(aws.Int64Value(m.ReadEndpoint.Port))
+			}
+			shard.Nodes = append(shard.Nodes, node)
+		}
+		shards = append(shards, shard)
+	}
+
+	return shards
+}
+
+// GetRedisSlotMap returns the shards of a cluster mode enabled replication group with
+// the slot ranges and the endpoint of every node, describing each member cache cluster
+// for the node endpoints that the replication group does not report
+func (a *Config) GetRedisSlotMap(cluster string) ([]*RedisShard, error) {
+	res, err := a.GetRedisPrimaryEndpoint(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if !res.ClusterEnabled {
+		return nil, errors.New("cluster mode is not enabled for " + cluster)
+	}
+
+	// copy the shards so the cached endpoints are not modified
+	shards := make([]*RedisShard, 0, len(res.Shards))
+	tasks := make([]SweepTask, 0)
+	for _, s := range res.Shards {
+		shard := *s
+		shard.Nodes = make([]*RedisEndpoint, 0, len(s.Nodes))
+		for _, n := range s.Nodes {
+			node := *n
+			shard.Nodes = append(shard.Nodes, &node)
+			if node.Host != "" {
+				continue
+			}
+			tasks = append(tasks, func() error {
+				list, err := a.GetECClusterDetails(node.CacheClusterID)
+				if err != nil {
+					return err
+				}
+				if len(list.CacheClusters) == 0 || len(list.CacheClusters[0].CacheNodes) == 0 ||
+					list.CacheClusters[0].CacheNodes[0].Endpoint == nil {
+					return errors.New("no endpoint found for node " + node.CacheClusterID)
+				}
+				ep := list.CacheClusters[0].CacheNodes[0].Endpoint
+				node.Host = aws.StringValue(ep.Address)
+				node.Port = int(aws.Int64Value(ep.Port))
+				return nil
+			})
+		}
+		shards = append(shards, &shard)
+	}
+
+	for _, err := range a.Sweep(elasticache.EndpointsID, tasks) {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return shards, nil
+}
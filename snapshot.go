@@ -0,0 +1,290 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	snapshotPollInterval  = 15 * time.Second
+	snapshotExportTimeout = 60 * time.Minute
+)
+
+// SnapshotProgressFunc is called on each poll while waiting on a snapshot operation
+type SnapshotProgressFunc func(status string, elapsed time.Duration)
+
+// ExportRedisSnapshotToS3 copies an ElastiCache snapshot to an S3 bucket in the same region
+// and waits for the export to complete, or for the context of a Config returned by
+// WithContext to be done. The bucket must grant the regional ElastiCache
+// snapshot service principal access, which is checked before the copy is started.
+// The returned string is the S3 object key prefix of the exported snapshot.
+func (a *Config) ExportRedisSnapshotToS3(snapshotName, bucket string, progress ...SnapshotProgressFunc) (string, error) {
+	if snapshotName == "" || bucket == "" {
+		return "", errors.New("must provide a snapshot name and bucket")
+	}
+
+	if err := a.CheckSnapshotExportBucket(bucket); err != nil {
+		return "", err
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.CopySnapshotInput{
+		SourceSnapshotName: aws.String(snapshotName),
+		TargetSnapshotName: aws.String(snapshotName),
+		TargetBucket:       aws.String(bucket),
+	}
+
+	if _, err := a.Service.Ec.CopySnapshot(input); err != nil {
+		return "", err
+	}
+
+	if _, err := a.waitForECSnapshot(a.context(), snapshotName, snapshotExportTimeout, "snapshot export to s3", progress); err != nil {
+		return "", err
+	}
+
+	return snapshotName, nil
+}
+
+// CheckSnapshotExportBucket verifies the prerequisites for exporting ElastiCache snapshots
+// to a bucket: the bucket must be in the configured region and its policy must grant the
+// regional ElastiCache snapshot service principal access
+func (a *Config) CheckSnapshotExportBucket(bucket string) error {
+	if bucket == "" {
+		return errors.New("no bucket name provided")
+	}
+
+	if a.Service.S3 == nil {
+		a.SetS3Client()
+	}
+
+	region := a.GetRegion()
+
+	bucketRegion, err := a.GetBucketRegion(bucket)
+	if err != nil {
+		return err
+	}
+	if bucketRegion != region {
+		return errors.New("bucket " + bucket + " is in " + bucketRegion + " but snapshots can only be exported within " + region)
+	}
+
+	policy, err := a.Service.S3.GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchBucketPolicy" {
+			return errors.New("bucket " + bucket + " has no policy granting elasticache snapshot access")
+		}
+		return err
+	}
+
+	principal := region + ".elasticache-snapshot." + DNSSuffixForRegion(region)
+	if !strings.Contains(aws.StringValue(policy.Policy), principal) {
+		return errors.New("bucket " + bucket + " policy does not grant access to " + principal)
+	}
+
+	return nil
+}
+
+// ECSnapshot is an ElastiCache snapshot
+type ECSnapshot struct {
+	Name               string    `json:"name" yaml:"name"`
+	ARN                string    `json:"arn" yaml:"arn"`
+	Status             string    `json:"status" yaml:"status"` // creating, available, restoring, copying or deleting
+	Source             string    `json:"source" yaml:"source"` // manual or automated
+	ReplicationGroupID string    `json:"replication_group_id,omitempty" yaml:"replication_group_id,omitempty"`
+	CacheClusterID     string    `json:"cache_cluster_id,omitempty" yaml:"cache_cluster_id,omitempty"`
+	Engine             string    `json:"engine" yaml:"engine"`
+	EngineVersion      string    `json:"engine_version" yaml:"engine_version"`
+	NodeType           string    `json:"node_type" yaml:"node_type"`
+	KMSKeyID           string    `json:"kms_key_id,omitempty" yaml:"kms_key_id,omitempty"`
+	Created            time.Time `json:"created" yaml:"created"` // when the first node snapshot was taken, zero while creating
+}
+
+// CreateECSnapshot starts a manual snapshot of a replication group, or of a cache
+// cluster that is not part of one, and returns it in the creating state. Use
+// WaitForECSnapshot to block until it is available.
+func (a *Config) CreateECSnapshot(source, snapshotName string) (*ECSnapshot, error) {
+	if source == "" || snapshotName == "" {
+		return nil, errors.New("must provide a source and snapshot name")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.CreateSnapshotInput{
+		SnapshotName: aws.String(snapshotName),
+		Tags: []*elasticache.Tag{
+			{Key: aws.String("awsx:snapshot-of"), Value: aws.String(source)},
+		},
+	}
+	_, count, err := a.GetECReplicationGroupE(source)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		input.ReplicationGroupId = aws.String(source)
+	} else {
+		input.CacheClusterId = aws.String(source)
+	}
+
+	result, err := a.Service.Ec.CreateSnapshot(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecSnapshot(result.Snapshot), nil
+}
+
+// CopyECSnapshot starts a copy of a snapshot within the region, optionally encrypted with
+// a KMS key, and returns the copy in the copying state. Use WaitForECSnapshot to block
+// until it is available.
+func (a *Config) CopyECSnapshot(sourceName, targetName, kmsKeyID string) (*ECSnapshot, error) {
+	if sourceName == "" || targetName == "" {
+		return nil, errors.New("must provide a source and target snapshot name")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.CopySnapshotInput{
+		SourceSnapshotName: aws.String(sourceName),
+		TargetSnapshotName: aws.String(targetName),
+	}
+	if kmsKeyID != "" {
+		input.KmsKeyId = aws.String(kmsKeyID)
+	}
+
+	result, err := a.Service.Ec.CopySnapshot(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecSnapshot(result.Snapshot), nil
+}
+
+// ListECSnapshots returns the snapshots of a replication group, or of a cache cluster
+// that is not part of one, or every snapshot in the region when source is empty
+func (a *Config) ListECSnapshots(source string) ([]*ECSnapshot, error) {
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	input := &elasticache.DescribeSnapshotsInput{MaxRecords: aws.Int64(50)}
+	if source != "" {
+		_, count, err := a.GetECReplicationGroupE(source)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			input.ReplicationGroupId = aws.String(source)
+		} else {
+			input.CacheClusterId = aws.String(source)
+		}
+	}
+
+	list := make([]*ECSnapshot, 0)
+	err := a.Service.Ec.DescribeSnapshotsPages(input, func(page *elasticache.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, snap := range page.Snapshots {
+			list = append(list, ecSnapshot(snap))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// WaitForECSnapshot polls a snapshot until it is available, calling progress on each
+// poll, and fails if the snapshot fails, is not available within timeout (defaults to
+// one hour) or ctx is done
+func (a *Config) WaitForECSnapshot(ctx context.Context, snapshotName string, timeout time.Duration, progress ...SnapshotProgressFunc) (*ECSnapshot, error) {
+	if snapshotName == "" {
+		return nil, errors.New("no snapshot name provided")
+	}
+	if timeout <= 0 {
+		timeout = snapshotExportTimeout
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	return a.waitForECSnapshot(ctx, snapshotName, timeout, "snapshot "+snapshotName, progress)
+}
+
+// waitForECSnapshot polls the snapshot status until it is available or ctx is done,
+// describing the operation waited on as what in errors
+func (a *Config) waitForECSnapshot(ctx context.Context, snapshotName string, timeout time.Duration, what string, progress []SnapshotProgressFunc) (*ECSnapshot, error) {
+	start := a.clock().Now()
+	for {
+		if !a.wait(snapshotPollInterval, ctx.Done()) {
+			return nil, ctx.Err()
+		}
+		elapsed := a.since(start)
+
+		snap, err := a.getECSnapshot(snapshotName)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range progress {
+			p(snap.Status, elapsed)
+		}
+
+		if snap.Status == "available" {
+			return snap, nil
+		}
+		if snap.Status == "failed" {
+			return nil, errors.New(what + " failed")
+		}
+		if elapsed > timeout {
+			return nil, errors.New("timed out waiting for " + what)
+		}
+	}
+}
+
+// ecSnapshot converts an SDK snapshot to an ECSnapshot
+func ecSnapshot(snap *elasticache.Snapshot) *ECSnapshot {
+	s := &ECSnapshot{
+		Name:               aws.StringValue(snap.SnapshotName),
+		ARN:                aws.StringValue(snap.ARN),
+		Status:             aws.StringValue(snap.SnapshotStatus),
+		Source:             aws.StringValue(snap.SnapshotSource),
+		ReplicationGroupID: aws.StringValue(snap.ReplicationGroupId),
+		CacheClusterID:     aws.StringValue(snap.CacheClusterId),
+		Engine:             aws.StringValue(snap.Engine),
+		EngineVersion:      aws.StringValue(snap.EngineVersion),
+		NodeType:           aws.StringValue(snap.CacheNodeType),
+		KMSKeyID:           aws.StringValue(snap.KmsKeyId),
+	}
+	if len(snap.NodeSnapshots) > 0 {
+		s.Created = aws.TimeValue(snap.NodeSnapshots[0].SnapshotCreateTime)
+	}
+	return s
+}
+
+// getECSnapshot describes a single ElastiCache snapshot
+func (a *Config) getECSnapshot(snapshotName string) (*ECSnapshot, error) {
+	result, err := a.Service.Ec.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{
+		SnapshotName: aws.String(snapshotName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Snapshots) == 0 {
+		return nil, errors.New("no snapshot found matching " + snapshotName)
+	}
+
+	return ecSnapshot(result.Snapshots[0]), nil
+}
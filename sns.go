@@ -0,0 +1,178 @@
+package awsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+)
+
+// GetSNSClient returns a client for use with Amazon SNS
+func (a *Config) GetSNSClient() snsiface.SNSAPI {
+	return a.Service.Sns
+}
+
+// SetSNSClient sets a client for use with Amazon SNS
+func (a *Config) SetSNSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Sns = sns.New(a.ClientConfig(sns.EndpointsID))
+
+	return a
+}
+
+// WithSNSClient sets the client used for Amazon SNS calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithSNSClient(client snsiface.SNSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Sns = client
+
+	return a
+}
+
+// Topic is an SNS topic returned by GetTopicByName
+type Topic struct {
+	Name string `json:"name" yaml:"name"`
+	ARN  string `json:"arn" yaml:"arn"`
+	FIFO bool   `json:"fifo" yaml:"fifo"`
+}
+
+// GetTopicByName returns the topic of the region with the name, iterating all pages of
+// ListTopics
+func (a *Config) GetTopicByName(name string) (*Topic, error) {
+	if name == "" {
+		return nil, errors.New("no topic name provided")
+	}
+
+	if a.Service.Sns == nil {
+		a.SetSNSClient()
+	}
+
+	var topic *Topic
+	var parseErr error
+	err := a.Service.Sns.ListTopicsPages(&sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+		for _, t := range page.Topics {
+			parsed, err := arn.Parse(aws.StringValue(t.TopicArn))
+			if err != nil {
+				parseErr = err
+				return false
+			}
+			if parsed.Resource == name {
+				topic = &Topic{Name: name, ARN: parsed.String(), FIFO: strings.HasSuffix(name, ".fifo")}
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if topic == nil {
+		return nil, errors.New("no topic found with name " + name)
+	}
+
+	return topic, nil
+}
+
+// SNSMessage is a message sent with Publish
+type SNSMessage struct {
+	// Message is sent as is when it is a string or []byte, and encoded as JSON otherwise
+	Message interface{}
+	Subject string // optional: subject of email subscriptions
+	// Attributes are sent as String, Number, Binary or String.Array message attributes
+	// for string, numeric, []byte and []string values respectively
+	Attributes      map[string]interface{}
+	GroupID         string // required for FIFO topics
+	DeduplicationID string // optional: for FIFO topics without content-based deduplication
+}
+
+// Publish sends the message to the topic and returns the message ID
+func (a *Config) Publish(ctx context.Context, topicARN string, msg *SNSMessage) (string, error) {
+	if topicARN == "" {
+		return "", errors.New("no topic ARN provided")
+	}
+	if msg == nil {
+		return "", errors.New("no message provided")
+	}
+
+	if a.Service.Sns == nil {
+		a.SetSNSClient()
+	}
+
+	var body string
+	switch m := msg.Message.(type) {
+	case string:
+		body = m
+	case []byte:
+		body = string(m)
+	default:
+		b, err := json.Marshal(m)
+		if err != nil {
+			return "", err
+		}
+		body = string(b)
+	}
+
+	input := &sns.PublishInput{
+		TopicArn: aws.String(topicARN),
+		Message:  aws.String(body),
+	}
+	if msg.Subject != "" {
+		input.Subject = aws.String(msg.Subject)
+	}
+	if msg.GroupID != "" {
+		input.MessageGroupId = aws.String(msg.GroupID)
+	}
+	if msg.DeduplicationID != "" {
+		input.MessageDeduplicationId = aws.String(msg.DeduplicationID)
+	}
+	if len(msg.Attributes) > 0 {
+		input.MessageAttributes = make(map[string]*sns.MessageAttributeValue, len(msg.Attributes))
+		for name, v := range msg.Attributes {
+			attr, err := snsAttribute(v)
+			if err != nil {
+				return "", errors.New("message attribute " + name + ": " + err.Error())
+			}
+			input.MessageAttributes[name] = attr
+		}
+	}
+
+	out, err := a.Service.Sns.PublishWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(out.MessageId), nil
+}
+
+// snsAttribute converts a Go value to an SNS message attribute
+func snsAttribute(v interface{}) (*sns.MessageAttributeValue, error) {
+	switch t := v.(type) {
+	case string:
+		return &sns.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(t)}, nil
+	case []byte:
+		return &sns.MessageAttributeValue{DataType: aws.String("Binary"), BinaryValue: t}, nil
+	case []string:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		return &sns.MessageAttributeValue{DataType: aws.String("String.Array"), StringValue: aws.String(string(b))}, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return &sns.MessageAttributeValue{DataType: aws.String("Number"), StringValue: aws.String(fmt.Sprint(t))}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported attribute type %T", v)
+}
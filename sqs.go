@@ -0,0 +1,152 @@
+package awsx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+// GetSQSClient returns a client for use with Amazon SQS
+func (a *Config) GetSQSClient() sqsiface.SQSAPI {
+	return a.Service.Sqs
+}
+
+// SetSQSClient sets a client for use with Amazon SQS
+func (a *Config) SetSQSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Sqs = sqs.New(a.ClientConfig(sqs.EndpointsID))
+
+	return a
+}
+
+// WithSQSClient sets the client used for Amazon SQS calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithSQSClient(client sqsiface.SQSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Sqs = client
+
+	return a
+}
+
+// Queue is an SQS queue returned by GetQueue
+type Queue struct {
+	Name              string            `json:"name" yaml:"name"`
+	URL               string            `json:"url" yaml:"url"`
+	ARN               string            `json:"arn" yaml:"arn"`
+	FIFO              bool              `json:"fifo" yaml:"fifo"`
+	VisibilityTimeout time.Duration     `json:"visibility_timeout" yaml:"visibility_timeout"`
+	RetentionPeriod   time.Duration     `json:"retention_period" yaml:"retention_period"`
+	ReceiveWaitTime   time.Duration     `json:"receive_wait_time" yaml:"receive_wait_time"`
+	Delay             time.Duration     `json:"delay" yaml:"delay"`
+	MaxMessageSize    int64             `json:"max_message_size" yaml:"max_message_size"`
+	KMSKeyID          string            `json:"kms_key_id,omitempty" yaml:"kms_key_id,omitempty"`
+	DeadLetterTarget  string            `json:"dead_letter_target,omitempty" yaml:"dead_letter_target,omitempty"` // ARN of the dead-letter queue
+	MaxReceiveCount   int64             `json:"max_receive_count,omitempty" yaml:"max_receive_count,omitempty"`
+	Attributes        map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"` // every attribute as returned by SQS
+}
+
+// GetQueue resolves the URL, ARN and key attributes of the queue, so that workers can
+// bootstrap from a queue name only. A queue ARN may be given instead of a name to look
+// up a queue in another account, see GetAccountQueue.
+func (a *Config) GetQueue(name string) (*Queue, error) {
+	if strings.HasPrefix(name, "arn:") {
+		parsed, err := arn.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.Region != "" && parsed.Region != a.GetRegion() {
+			return a.ForRegion(parsed.Region).GetAccountQueue(parsed.AccountID, parsed.Resource)
+		}
+		return a.GetAccountQueue(parsed.AccountID, parsed.Resource)
+	}
+	return a.GetAccountQueue("", name)
+}
+
+// GetAccountQueue resolves a queue owned by another account, which must grant the
+// caller access in its queue policy. An empty account ID looks up a queue of the caller.
+func (a *Config) GetAccountQueue(accountID, name string) (*Queue, error) {
+	if name == "" {
+		return nil, errors.New("no queue name provided")
+	}
+
+	if a.Service.Sqs == nil {
+		a.SetSQSClient()
+	}
+
+	input := &sqs.GetQueueUrlInput{QueueName: aws.String(name)}
+	if accountID != "" {
+		input.QueueOwnerAWSAccountId = aws.String(accountID)
+	}
+	urlOut, err := a.Service.Sqs.GetQueueUrl(input)
+	if err != nil {
+		return nil, err
+	}
+
+	attrOut, err := a.Service.Sqs.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       urlOut.QueueUrl,
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameAll}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Queue{
+		Name:       name,
+		URL:        aws.StringValue(urlOut.QueueUrl),
+		Attributes: aws.StringValueMap(attrOut.Attributes),
+	}
+	attrs := q.Attributes
+	q.ARN = attrs[sqs.QueueAttributeNameQueueArn]
+	q.FIFO = attrs[sqs.QueueAttributeNameFifoQueue] == "true"
+	q.VisibilityTimeout = secondsAttribute(attrs, sqs.QueueAttributeNameVisibilityTimeout)
+	q.RetentionPeriod = secondsAttribute(attrs, sqs.QueueAttributeNameMessageRetentionPeriod)
+	q.ReceiveWaitTime = secondsAttribute(attrs, sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds)
+	q.Delay = secondsAttribute(attrs, sqs.QueueAttributeNameDelaySeconds)
+	q.MaxMessageSize, _ = strconv.ParseInt(attrs[sqs.QueueAttributeNameMaximumMessageSize], 10, 64)
+	q.KMSKeyID = attrs[sqs.QueueAttributeNameKmsMasterKeyId]
+
+	if policy := attrs[sqs.QueueAttributeNameRedrivePolicy]; policy != "" {
+		if err := q.setRedrivePolicy(policy); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// setRedrivePolicy parses the RedrivePolicy attribute, in which maxReceiveCount may be a
+// number or a string
+func (q *Queue) setRedrivePolicy(policy string) error {
+	var p struct {
+		DeadLetterTargetArn string      `json:"deadLetterTargetArn"`
+		MaxReceiveCount     interface{} `json:"maxReceiveCount"`
+	}
+	if err := json.Unmarshal([]byte(policy), &p); err != nil {
+		return errors.New("invalid redrive policy of queue " + q.Name + ": " + err.Error())
+	}
+
+	q.DeadLetterTarget = p.DeadLetterTargetArn
+	q.MaxReceiveCount, _ = strconv.ParseInt(fmt.Sprint(p.MaxReceiveCount), 10, 64)
+	return nil
+}
+
+// secondsAttribute returns a queue attribute holding a number of seconds as a duration
+func secondsAttribute(attrs map[string]string, name string) time.Duration {
+	s, err := strconv.ParseInt(attrs[name], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(s) * time.Second
+}
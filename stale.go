@@ -0,0 +1,80 @@
+package awsx
+
+import (
+	"time"
+)
+
+// StalePolicy controls what discovery returns when the AWS Describe calls fail but an
+// earlier result is cached or in the endpoint store
+type StalePolicy int
+
+const (
+	// FailClosed returns the error, never an earlier result
+	FailClosed StalePolicy = iota + 1
+	// ServeStale returns the earlier result with its age in Staleness
+	ServeStale
+	// ServeStaleWithWarning is ServeStale, printing the error and the age of the result
+	ServeStaleWithWarning
+)
+
+// SetStalePolicy sets what discovery returns when AWS calls fail but an earlier result
+// exists. Without a policy, discovery fails closed unless an endpoint store is set, in
+// which case stored results are served with a warning.
+func (a *Config) SetStalePolicy(policy StalePolicy) *Config {
+	a.StalePolicy = policy
+	return a
+}
+
+// stalePolicy returns the stale policy with the default applied
+func (a *Config) stalePolicy() StalePolicy {
+	if a.StalePolicy != 0 {
+		return a.StalePolicy
+	}
+	if a.endpointStore != nil {
+		return ServeStaleWithWarning
+	}
+	return FailClosed
+}
+
+// staleResult is implemented by the discovery results that report their staleness
+type staleResult interface {
+	withStaleness(age time.Duration) interface{}
+}
+
+// serveStale returns a copy of the earlier result of key discovered at stored with its
+// staleness set, or nil if the policy or the type of the result doesn't allow serving it
+func (a *Config) serveStale(key string, value interface{}, stored time.Time, err error) interface{} {
+	policy := a.stalePolicy()
+	if policy == FailClosed {
+		return nil
+	}
+	res, ok := value.(staleResult)
+	if !ok {
+		return nil
+	}
+
+	age := a.since(stored)
+	if policy == ServeStaleWithWarning {
+		a.warn("Discovery of " + key + " failed, using the result from " + age.Round(time.Second).String() + " ago: " + err.Error())
+	}
+
+	return res.withStaleness(age)
+}
+
+func (r *RedisEndpoints) withStaleness(age time.Duration) interface{} {
+	c := *r
+	c.Staleness = age
+	return &c
+}
+
+func (aes *AuroraEndpoints) withStaleness(age time.Duration) interface{} {
+	c := *aes
+	c.Staleness = age
+	return &c
+}
+
+func (e *ECSServiceEndpoints) withStaleness(age time.Duration) interface{} {
+	c := *e
+	c.Staleness = age
+	return &c
+}
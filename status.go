@@ -0,0 +1,206 @@
+package awsx
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// statusMu guards the lazy creation of the status tracker of a Config
+var statusMu sync.Mutex
+
+// statusTracker records the running watchers and the outcome of discovery for Status
+type statusTracker struct {
+	mu        sync.Mutex
+	watchers  map[*Watcher]bool
+	discovery map[string]*DiscoveryStatus
+}
+
+// Status is a snapshot of the discovery state of a Config, as served by StatusHandler
+type Status struct {
+	Region      string             `json:"region" yaml:"region"`
+	Time        time.Time          `json:"time" yaml:"time"`
+	Watchers    []WatcherStatus    `json:"watchers" yaml:"watchers"`
+	Discovery   []DiscoveryStatus  `json:"discovery" yaml:"discovery"`
+	Cache       []CacheStatus      `json:"cache" yaml:"cache"`
+	Credentials *CredentialsStatus `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+}
+
+// WatcherStatus is the state of a running Watcher
+type WatcherStatus struct {
+	Cluster     string        `json:"cluster" yaml:"cluster"`
+	Interval    time.Duration `json:"interval" yaml:"interval"`
+	LastPoll    time.Time     `json:"last_poll" yaml:"last_poll"`
+	LastChange  time.Time     `json:"last_change" yaml:"last_change"` // when the last event was published
+	Topology    string        `json:"topology,omitempty" yaml:"topology,omitempty"`
+	Err         string        `json:"error,omitempty" yaml:"error,omitempty"`
+	Subscribers int           `json:"subscribers" yaml:"subscribers"`
+}
+
+// DiscoveryStatus is the outcome of the discovery calls for a cluster
+type DiscoveryStatus struct {
+	Kind        string    `json:"kind" yaml:"kind"`
+	Cluster     string    `json:"cluster" yaml:"cluster"`
+	LastSuccess time.Time `json:"last_success,omitempty" yaml:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty" yaml:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty" yaml:"last_error_at,omitempty"`
+}
+
+// CacheStatus is the age of a cached discovery result
+type CacheStatus struct {
+	Key   string        `json:"key" yaml:"key"`
+	Age   time.Duration `json:"age" yaml:"age"`
+	Fresh bool          `json:"fresh" yaml:"fresh"` // younger than CacheTTL
+}
+
+// CredentialsStatus describes the session credentials
+type CredentialsStatus struct {
+	Provider  string        `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Expires   time.Time     `json:"expires,omitempty" yaml:"expires,omitempty"` // zero if the credentials do not expire
+	ExpiresIn time.Duration `json:"expires_in,omitempty" yaml:"expires_in,omitempty"`
+	Err       string        `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Status returns the state of the running watchers, the last outcome of discovery per
+// cluster, the age of the cached results and the expiry of the session credentials
+func (a *Config) Status() *Status {
+	now := a.clock().Now()
+	st := &Status{
+		Region:    a.GetRegion(),
+		Time:      now,
+		Watchers:  make([]WatcherStatus, 0),
+		Discovery: make([]DiscoveryStatus, 0),
+		Cache:     make([]CacheStatus, 0),
+	}
+
+	t := a.status()
+	t.mu.Lock()
+	watchers := make([]*Watcher, 0, len(t.watchers))
+	for w := range t.watchers {
+		watchers = append(watchers, w)
+	}
+	for _, d := range t.discovery {
+		st.Discovery = append(st.Discovery, *d)
+	}
+	t.mu.Unlock()
+
+	for _, w := range watchers {
+		w.mu.Lock()
+		st.Watchers = append(st.Watchers, WatcherStatus{
+			Cluster:     w.Cluster,
+			Interval:    w.Interval,
+			LastPoll:    w.polled,
+			LastChange:  w.changed,
+			Topology:    w.last,
+			Err:         w.lastErr,
+			Subscribers: len(w.subs),
+		})
+		w.mu.Unlock()
+	}
+
+	cache := a.results()
+	cache.mu.Lock()
+	for key, e := range cache.entries {
+		age := now.Sub(e.stored)
+		st.Cache = append(st.Cache, CacheStatus{Key: key, Age: age, Fresh: age < a.CacheTTL})
+	}
+	cache.mu.Unlock()
+
+	if a.Session != nil && a.Session.Config.Credentials != nil {
+		creds := a.Session.Config.Credentials
+		cs := &CredentialsStatus{}
+		if v, err := creds.Get(); err != nil {
+			cs.Err = err.Error()
+		} else {
+			cs.Provider = v.ProviderName
+			if expires, err := creds.ExpiresAt(); err == nil {
+				cs.Expires = expires
+				cs.ExpiresIn = expires.Sub(now)
+			}
+		}
+		st.Credentials = cs
+	}
+
+	sort.Slice(st.Watchers, func(i, j int) bool { return st.Watchers[i].Cluster < st.Watchers[j].Cluster })
+	sort.Slice(st.Discovery, func(i, j int) bool {
+		if st.Discovery[i].Kind != st.Discovery[j].Kind {
+			return st.Discovery[i].Kind < st.Discovery[j].Kind
+		}
+		return st.Discovery[i].Cluster < st.Discovery[j].Cluster
+	})
+	sort.Slice(st.Cache, func(i, j int) bool { return st.Cache[i].Key < st.Cache[j].Key })
+
+	return st
+}
+
+// StatusHandler returns an http.Handler serving Status as JSON, so operators can debug
+// discovery on a running service:
+//
+//	http.Handle("/awsx/status", a.StatusHandler())
+func (a *Config) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(a.Status()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// PublishExpvar publishes Status as the expvar variable name, "awsx" if empty, so it is
+// served on /debug/vars with the other expvar variables. Publishing a name twice does
+// nothing.
+func (a *Config) PublishExpvar(name string) *Config {
+	if name == "" {
+		name = "awsx"
+	}
+	if expvar.Get(name) == nil {
+		expvar.Publish(name, expvar.Func(func() interface{} { return a.Status() }))
+	}
+	return a
+}
+
+// status returns the status tracker of the Config, creating it on first use
+func (a *Config) status() *statusTracker {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	if a.statusTracker == nil {
+		a.statusTracker = &statusTracker{watchers: map[*Watcher]bool{}, discovery: map[string]*DiscoveryStatus{}}
+	}
+	return a.statusTracker
+}
+
+func (t *statusTracker) addWatcher(w *Watcher) {
+	t.mu.Lock()
+	t.watchers[w] = true
+	t.mu.Unlock()
+}
+
+func (t *statusTracker) removeWatcher(w *Watcher) {
+	t.mu.Lock()
+	delete(t.watchers, w)
+	t.mu.Unlock()
+}
+
+// discovered records the outcome of a discovery operation of the kind for the cluster
+func (t *statusTracker) discovered(kind, cluster string, err error, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	d, ok := t.discovery[kind+":"+cluster]
+	if !ok {
+		d = &DiscoveryStatus{Kind: kind, Cluster: cluster}
+		t.discovery[kind+":"+cluster] = d
+	}
+	if err != nil {
+		d.LastError = err.Error()
+		d.LastErrorAt = now
+	} else {
+		d.LastSuccess = now
+	}
+}
@@ -0,0 +1,182 @@
+package awsx
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// CallerIdentity describes the principal the credential chain authenticates as
+type CallerIdentity struct {
+	Account   string
+	ARN       string
+	UserID    string
+	Partition string // partition of the principal, e.g. aws or aws-us-gov
+	Provider  string // name of the credential provider in the chain that supplied the credentials
+}
+
+// GetSTSClient returns a client for use with AWS STS
+func (a *Config) GetSTSClient() stsiface.STSAPI {
+	return a.Service.Sts
+}
+
+// SetSTSClient sets a client for use with AWS STS
+func (a *Config) SetSTSClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Sts = sts.New(a.ClientConfig(sts.EndpointsID))
+
+	return a
+}
+
+// WithSTSClient sets the client used for AWS STS calls, such as a mock from the
+// awsxmock package
+func (a *Config) WithSTSClient(client stsiface.STSAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.Sts = client
+
+	return a
+}
+
+// WhoAmI calls GetCallerIdentity with the session and returns the account, ARN and user
+// ID of the caller along with the credential provider that won in the chain
+func (a *Config) WhoAmI() (*CallerIdentity, error) {
+	if a.Service.Sts == nil {
+		a.SetSTSClient()
+	}
+
+	result, err := a.Service.Sts.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	id := &CallerIdentity{
+		Account: aws.StringValue(result.Account),
+		ARN:     aws.StringValue(result.Arn),
+		UserID:  aws.StringValue(result.UserId),
+	}
+	if parsed, err := arn.Parse(id.ARN); err == nil {
+		id.Partition = parsed.Partition
+	}
+	if a.Session != nil && a.Session.Config.Credentials != nil {
+		if v, err := a.Session.Config.Credentials.Get(); err == nil {
+			id.Provider = v.ProviderName
+		}
+	}
+
+	return id, nil
+}
+
+// identityMu guards Config.Identity, so concurrent first callers of identity share one
+// WhoAmI call
+var identityMu sync.Mutex
+
+// identity returns Config.Identity, calling WhoAmI to set it on first use
+func (a *Config) identity() (*CallerIdentity, error) {
+	identityMu.Lock()
+	defer identityMu.Unlock()
+
+	if a.Identity != nil {
+		return a.Identity, nil
+	}
+	id, err := a.WhoAmI()
+	if err != nil {
+		return nil, err
+	}
+	a.Identity = id
+	return id, nil
+}
+
+// AccountID returns the account ID of the principal of the credential chain. It is
+// resolved with GetCallerIdentity on first use and kept in Config.Identity.
+func (a *Config) AccountID() (string, error) {
+	id, err := a.identity()
+	if err != nil {
+		return "", err
+	}
+	return id.Account, nil
+}
+
+// Partition returns the partition of the principal of the credential chain, resolved
+// like AccountID, falling back to the partition of the region if the caller ARN has none
+func (a *Config) Partition() (string, error) {
+	id, err := a.identity()
+	if err != nil {
+		return "", err
+	}
+	if id.Partition == "" {
+		return PartitionForRegion(a.GetRegion()), nil
+	}
+	return id.Partition, nil
+}
+
+// globalARNServices are the services whose ARNs have no region
+var globalARNServices = map[string]bool{"iam": true, "cloudfront": true, "organizations": true}
+
+// accountlessARNServices are the services whose ARNs have neither region nor account,
+// e.g. arn:aws:route53:::hostedzone/Z123
+var accountlessARNServices = map[string]bool{"s3": true, "route53": true}
+
+// BuildARN returns the ARN of a resource of the service in the partition, region and
+// account of the Config, e.g. BuildARN("events", "rule/topology") for an EventBridge rule.
+// The region is left out for global services such as IAM, and the region and account for
+// S3 and Route 53, whose ARNs have neither.
+func (a *Config) BuildARN(service, resource string) (string, error) {
+	partition, err := a.Partition()
+	if err != nil {
+		return "", err
+	}
+	id, err := a.identity()
+	if err != nil {
+		return "", err
+	}
+
+	res := arn.ARN{Partition: partition, Service: service, Resource: resource}
+	switch {
+	case accountlessARNServices[service]:
+	case globalARNServices[service]:
+		res.AccountID = id.Account
+	default:
+		res.Region = a.GetRegion()
+		res.AccountID = id.Account
+	}
+
+	return res.String(), nil
+}
+
+// ValidateCredentials makes SetSession call WhoAmI so a broken credential chain fails at
+// startup instead of on the first service call. The identity is stored in Config.Identity.
+func (a *Config) ValidateCredentials() *Config {
+	a.validateCreds = true
+	return a
+}
+
+// validateSession runs WhoAmI for a new session when ValidateCredentials is enabled
+func (a *Config) validateSession() error {
+	if !a.validateCreds || a.Session == nil {
+		return nil
+	}
+
+	id, err := a.WhoAmI()
+	if err != nil {
+		a.warn("Error validating AWS credentials: ", err)
+		if a.panicOnErr {
+			a.warn("panicOnError is enabled so exiting...")
+			os.Exit(1)
+		}
+		return errors.New("credential validation failed: " + err.Error())
+	}
+	identityMu.Lock()
+	a.Identity = id
+	identityMu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package awsx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// serviceRateLimits are conservative requests per second budgets for the control plane
+// APIs used in sweeps. AWS does not publish exact limits, these are derived from the
+// observed token bucket refill rates for Describe/List calls.
+var serviceRateLimits = map[string]float64{
+	"elasticache": 10,
+	"rds":         20,
+	"sts":         50,
+	"monitoring":  25,
+	"s3":          100,
+	"ec2":         20,
+}
+
+const (
+	defaultSweepRate      = 5  // requests per second for services without a known limit
+	maxSweepConcurrency   = 16 // upper bound on parallel workers for any service
+	sweepThrottleRetries  = 5  // attempts per task when throttled before giving up
+	sweepIncreaseInterval = 10 // successful calls before concurrency is raised again
+	sweepThrottleBackoff  = 500 * time.Millisecond
+)
+
+// SweepTask is a unit of work issued by a sweep, typically one AWS API call
+type SweepTask func() error
+
+// SweepScheduler runs sweep tasks against a single service with a concurrency and
+// request rate derived from the known API limits for the service. Concurrency is
+// halved whenever a throttling error is observed and slowly raised again on success.
+// Fields left unset in a struct literal default to the default rate and one worker.
+type SweepScheduler struct {
+	Service        string
+	Rate           float64 // requests per second allowed across all workers
+	MaxConcurrency int
+	Clock          Clock // time source for rate limiting and backoff, defaults to RealClock
+
+	mu          sync.Mutex
+	cond        *sync.Cond
+	concurrency int
+	active      int
+	successes   int
+	throttles   int
+	next        time.Time
+}
+
+// NewSweepScheduler returns a scheduler for the service endpoint ID (e.g. "elasticache")
+func NewSweepScheduler(service string) *SweepScheduler {
+	rate, ok := serviceRateLimits[service]
+	if !ok {
+		rate = defaultSweepRate
+	}
+
+	max := int(rate / 2)
+	if max < 1 {
+		max = 1
+	}
+	if max > maxSweepConcurrency {
+		max = maxSweepConcurrency
+	}
+
+	s := &SweepScheduler{
+		Service:        service,
+		Rate:           rate,
+		MaxConcurrency: max,
+		Clock:          RealClock,
+		concurrency:    max,
+	}
+	s.cond = sync.NewCond(&s.mu)
+
+	return s
+}
+
+// Sweep runs the tasks against the service using a SweepScheduler and returns the
+// error of each task in the same order as the tasks
+func (a *Config) Sweep(service string, tasks []SweepTask) []error {
+	s := NewSweepScheduler(service)
+	s.Clock = a.clock()
+	return s.Run(tasks)
+}
+
+// Run executes all tasks and returns the error for each task in the same order
+func (s *SweepScheduler) Run(tasks []SweepTask) []error {
+	errs := make([]error, len(tasks))
+
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		s.acquire()
+		wg.Add(1)
+		go func(i int, task SweepTask) {
+			defer wg.Done()
+			defer s.release()
+			errs[i] = s.do(task)
+		}(i, task)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// Concurrency returns the current number of workers allowed to run in parallel
+func (s *SweepScheduler) Concurrency() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	return s.concurrency
+}
+
+// Throttles returns the number of throttling errors observed so far
+func (s *SweepScheduler) Throttles() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.throttles
+}
+
+// do runs a task, retrying with backoff while it is throttled
+func (s *SweepScheduler) do(task SweepTask) error {
+	var err error
+	for attempt := 0; attempt < sweepThrottleRetries; attempt++ {
+		s.wait()
+		err = task()
+		if err == nil || !request.IsErrorThrottle(err) {
+			s.observe(false)
+			return err
+		}
+		s.observe(true)
+		s.Clock.Sleep(sweepThrottleBackoff * time.Duration(1<<uint(attempt)))
+	}
+
+	return err
+}
+
+// wait blocks until the rate limit allows another request
+func (s *SweepScheduler) wait() {
+	s.mu.Lock()
+	s.init()
+	now := s.Clock.Now()
+	if s.next.Before(now) {
+		s.next = now
+	}
+	delay := s.next.Sub(now)
+	s.next = s.next.Add(time.Duration(float64(time.Second) / s.Rate))
+	s.mu.Unlock()
+
+	if delay > 0 {
+		s.Clock.Sleep(delay)
+	}
+}
+
+// observe adapts concurrency based on whether the last call was throttled
+func (s *SweepScheduler) observe(throttled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+
+	if throttled {
+		s.throttles++
+		s.successes = 0
+		s.concurrency = s.concurrency / 2
+		if s.concurrency < 1 {
+			s.concurrency = 1
+		}
+		return
+	}
+
+	s.successes++
+	if s.successes >= sweepIncreaseInterval && s.concurrency < s.MaxConcurrency {
+		s.concurrency++
+		s.successes = 0
+		s.cond.Broadcast()
+	}
+}
+
+func (s *SweepScheduler) acquire() {
+	s.mu.Lock()
+	s.init()
+	for s.active >= s.concurrency {
+		s.cond.Wait()
+	}
+	s.active++
+	s.mu.Unlock()
+}
+
+func (s *SweepScheduler) release() {
+	s.mu.Lock()
+	s.init()
+	s.active--
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// init sets the defaults of a scheduler not built with NewSweepScheduler. It must be
+// called with mu held.
+func (s *SweepScheduler) init() {
+	if s.cond != nil {
+		return
+	}
+	s.cond = sync.NewCond(&s.mu)
+	if s.Clock == nil {
+		s.Clock = RealClock
+	}
+	if s.Rate <= 0 {
+		s.Rate = defaultSweepRate
+	}
+	if s.MaxConcurrency < 1 {
+		s.MaxConcurrency = 1
+	}
+	if s.concurrency < 1 {
+		s.concurrency = s.MaxConcurrency
+	}
+}
@@ -0,0 +1,100 @@
+package awsx
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+)
+
+// TaggedRedisEndpoints are the endpoints of a replication group matched by tag
+type TaggedRedisEndpoints struct {
+	ReplicationGroupID string
+	ARN                string
+	Tags               map[string]string
+	Endpoints          *RedisEndpoints
+}
+
+// ListRedisClustersByTag returns the endpoints of every replication group carrying the tag
+// key. If value is not empty, the tag must also have that value. Tags are looked up for
+// each replication group with ListTagsForResource using a SweepScheduler to stay within
+// the ElastiCache API limits.
+func (a *Config) ListRedisClustersByTag(key, value string) ([]*TaggedRedisEndpoints, error) {
+	if key == "" {
+		return nil, errors.New("no tag key provided")
+	}
+
+	groups, err := a.ListECReplicationGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*TaggedRedisEndpoints, len(groups))
+
+	tasks := make([]SweepTask, 0, len(groups))
+	for i, rg := range groups {
+		i, rg := i, rg
+		tasks = append(tasks, func() error {
+			tags, err := a.GetECTags(aws.StringValue(rg.ARN))
+			if err != nil {
+				return err
+			}
+			if v, ok := tags[key]; !ok || (value != "" && v != value) {
+				return nil
+			}
+
+			res, err := redisEndpointsFromGroup(rg)
+			if err != nil {
+				return err
+			}
+
+			matches[i] = &TaggedRedisEndpoints{
+				ReplicationGroupID: aws.StringValue(rg.ReplicationGroupId),
+				ARN:                aws.StringValue(rg.ARN),
+				Tags:               tags,
+				Endpoints:          res,
+			}
+			return nil
+		})
+	}
+
+	for _, err := range a.Sweep(elasticache.EndpointsID, tasks) {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	list := make([]*TaggedRedisEndpoints, 0)
+	for _, m := range matches {
+		if m != nil {
+			list = append(list, m)
+		}
+	}
+
+	return list, nil
+}
+
+// GetECTags returns the tags of an ElastiCache resource by ARN
+func (a *Config) GetECTags(arn string) (map[string]string, error) {
+	if arn == "" {
+		return nil, errors.New("no resource arn provided")
+	}
+
+	if a.Service.Ec == nil {
+		a.SetECClient()
+	}
+
+	result, err := a.Service.Ec.ListTagsForResource(&elasticache.ListTagsForResourceInput{
+		ResourceName: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(result.TagList))
+	for _, t := range result.TagList {
+		tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,167 @@
+package awsx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/timestreamquery"
+	"github.com/aws/aws-sdk-go/service/timestreamquery/timestreamqueryiface"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite/timestreamwriteiface"
+)
+
+// maxTimestreamBatch is the maximum number of records of a WriteRecords call
+const maxTimestreamBatch = 100
+
+// GetTimestreamWriteClient returns a client for use with the Amazon Timestream write API
+func (a *Config) GetTimestreamWriteClient() timestreamwriteiface.TimestreamWriteAPI {
+	return a.Service.TsWrite
+}
+
+// SetTimestreamWriteClient sets a client for use with the Amazon Timestream write API.
+// Endpoint discovery is enabled unless an endpoint is set with SetServiceEndpoint.
+func (a *Config) SetTimestreamWriteClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	sess, cfg := a.ClientConfig(timestreamwrite.EndpointsID)
+	if cfg.Endpoint == nil {
+		cfg.WithEndpointDiscovery(true)
+	}
+	a.Service.TsWrite = timestreamwrite.New(sess, cfg)
+
+	return a
+}
+
+// WithTimestreamWriteClient sets the client used for Amazon Timestream write calls, such
+// as a mock from the awsxmock package
+func (a *Config) WithTimestreamWriteClient(client timestreamwriteiface.TimestreamWriteAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.TsWrite = client
+
+	return a
+}
+
+// GetTimestreamQueryClient returns a client for use with the Amazon Timestream query API
+func (a *Config) GetTimestreamQueryClient() timestreamqueryiface.TimestreamQueryAPI {
+	return a.Service.TsQuery
+}
+
+// SetTimestreamQueryClient sets a client for use with the Amazon Timestream query API.
+// Endpoint discovery is enabled unless an endpoint is set with SetServiceEndpoint.
+func (a *Config) SetTimestreamQueryClient() *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	sess, cfg := a.ClientConfig(timestreamquery.EndpointsID)
+	if cfg.Endpoint == nil {
+		cfg.WithEndpointDiscovery(true)
+	}
+	a.Service.TsQuery = timestreamquery.New(sess, cfg)
+
+	return a
+}
+
+// WithTimestreamQueryClient sets the client used for Amazon Timestream query calls, such
+// as a mock from the awsxmock package
+func (a *Config) WithTimestreamQueryClient(client timestreamqueryiface.TimestreamQueryAPI) *Config {
+	if a.Service == nil {
+		panic("Must initialize Service struct with NewAWS()")
+	}
+	a.Service.TsQuery = client
+
+	return a
+}
+
+// TimestreamEndpoints are the account specific Timestream endpoints returned by
+// DescribeEndpoints
+type TimestreamEndpoints struct {
+	Write            string        `json:"write" yaml:"write"`
+	WriteCachePeriod time.Duration `json:"write_cache_period" yaml:"write_cache_period"` // how long the write endpoint may be used
+	Query            string        `json:"query" yaml:"query"`
+	QueryCachePeriod time.Duration `json:"query_cache_period" yaml:"query_cache_period"` // how long the query endpoint may be used
+}
+
+// GetTimestreamEndpoints discovers the write and query endpoints of the account, e.g. for
+// clients outside the SDK. The SDK clients of awsx discover them on their own.
+func (a *Config) GetTimestreamEndpoints() (*TimestreamEndpoints, error) {
+	if a.Service.TsWrite == nil {
+		a.SetTimestreamWriteClient()
+	}
+	if a.Service.TsQuery == nil {
+		a.SetTimestreamQueryClient()
+	}
+
+	w, err := a.Service.TsWrite.DescribeEndpoints(&timestreamwrite.DescribeEndpointsInput{})
+	if err != nil {
+		return nil, err
+	}
+	if len(w.Endpoints) == 0 {
+		return nil, errors.New("no timestream write endpoint returned")
+	}
+	q, err := a.Service.TsQuery.DescribeEndpoints(&timestreamquery.DescribeEndpointsInput{})
+	if err != nil {
+		return nil, err
+	}
+	if len(q.Endpoints) == 0 {
+		return nil, errors.New("no timestream query endpoint returned")
+	}
+
+	return &TimestreamEndpoints{
+		Write:            aws.StringValue(w.Endpoints[0].Address),
+		WriteCachePeriod: time.Duration(aws.Int64Value(w.Endpoints[0].CachePeriodInMinutes)) * time.Minute,
+		Query:            aws.StringValue(q.Endpoints[0].Address),
+		QueryCachePeriod: time.Duration(aws.Int64Value(q.Endpoints[0].CachePeriodInMinutes)) * time.Minute,
+	}, nil
+}
+
+// WriteTimestreamRecords writes the records to the table in batches of 100. Attributes
+// shared by every record, such as dimensions and the measure name, can be set once in
+// common, which may be nil. Records rejected by Timestream are reported in the error
+// with their index in records.
+func (a *Config) WriteTimestreamRecords(ctx context.Context, database, table string, common *timestreamwrite.Record, records []*timestreamwrite.Record) error {
+	if database == "" || table == "" {
+		return errors.New("must provide a database and table name")
+	}
+
+	if a.Service.TsWrite == nil {
+		a.SetTimestreamWriteClient()
+	}
+
+	for start := 0; start < len(records); start += maxTimestreamBatch {
+		end := start + maxTimestreamBatch
+		if end > len(records) {
+			end = len(records)
+		}
+
+		_, err := a.Service.TsWrite.WriteRecordsWithContext(ctx, &timestreamwrite.WriteRecordsInput{
+			DatabaseName:     aws.String(database),
+			TableName:        aws.String(table),
+			CommonAttributes: common,
+			Records:          records[start:end],
+		})
+		if err != nil {
+			if rejected, ok := err.(*timestreamwrite.RejectedRecordsException); ok {
+				return rejectedRecordsError(rejected, start)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rejectedRecordsError lists the rejected records of a batch starting at offset
+func rejectedRecordsError(e *timestreamwrite.RejectedRecordsException, offset int) error {
+	reasons := make([]string, 0, len(e.RejectedRecords))
+	for _, r := range e.RejectedRecords {
+		reasons = append(reasons, "record "+strconv.FormatInt(aws.Int64Value(r.RecordIndex)+int64(offset), 10)+": "+aws.StringValue(r.Reason))
+	}
+	return errors.New("timestream rejected records: " + strings.Join(reasons, "; "))
+}
@@ -0,0 +1,111 @@
+package awsx
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name of the awsx tracer
+const tracerName = "github.com/routebyintuition/awsx"
+
+// WithTracerProvider enables OpenTelemetry tracing: a span around every AWS call made
+// through the session and around each discovery operation, carrying the cluster, region
+// and result. The AWS calls of a discovery are children of its span, and the discovery
+// span of a Config returned by WithContext a child of the span in the context passed to
+// it. Calls made with the SDK *WithContext operations are parented to the span in their
+// context. It must be called before SetSession.
+func (a *Config) WithTracerProvider(tp trace.TracerProvider) *Config {
+	if tp == nil {
+		a.tracer = nil
+		return a
+	}
+	a.tracer = tp.Tracer(tracerName)
+	return a
+}
+
+// installTracing adds the span handlers to a new session
+func (a *Config) installTracing(sess *session.Session) {
+	if a.tracer == nil {
+		return
+	}
+	tracer := a.tracer
+
+	sess.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "awsx.tracing.start",
+		Fn: func(r *request.Request) {
+			ctx, _ := tracer.Start(r.Context(), r.ClientInfo.ServiceName+"."+r.Operation.Name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("rpc.system", "aws-api"),
+					attribute.String("rpc.service", r.ClientInfo.ServiceName),
+					attribute.String("rpc.method", r.Operation.Name),
+					attribute.String("cloud.region", r.ClientInfo.SigningRegion),
+				),
+			)
+			r.SetContext(ctx)
+		},
+	})
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "awsx.tracing.end",
+		Fn: func(r *request.Request) {
+			span := trace.SpanFromContext(r.Context())
+			span.SetAttributes(
+				attribute.Int("aws.retries", r.RetryCount),
+				attribute.String("aws.request_id", r.RequestID),
+			)
+			if r.HTTPResponse != nil {
+				span.SetAttributes(attribute.Int("http.status_code", r.HTTPResponse.StatusCode))
+			}
+			if r.Error != nil {
+				span.RecordError(r.Error)
+				span.SetStatus(codes.Error, r.Error.Error())
+			}
+			span.End()
+		},
+	})
+}
+
+// traced runs a discovery operation of the kind ("redis" or "aurora") for the cluster
+// inside a span when tracing is enabled, and records its outcome for Status and the
+// TopologyHistory. fn makes its AWS calls with ctx so their spans are children of the
+// discovery span.
+func (a *Config) traced(kind, cluster string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	discover := fn
+	fn = func(ctx context.Context) (interface{}, error) {
+		v, err := discover(ctx)
+		now := a.clock().Now()
+		a.status().discovered(kind, cluster, err, now)
+		if a.history != nil {
+			if werr := a.history.record(kind, cluster, v, err, now); werr != nil {
+				a.warn("Error writing topology history " + a.history.path + ": " + werr.Error())
+			}
+		}
+		return v, err
+	}
+
+	if a.tracer == nil {
+		return fn(a.context())
+	}
+
+	ctx, span := a.tracer.Start(a.context(), "awsx.discover."+kind, trace.WithAttributes(
+		attribute.String("awsx.cluster", cluster),
+		attribute.String("cloud.region", a.GetRegion()),
+	))
+	defer span.End()
+
+	v, err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("awsx.result", "error"))
+	} else {
+		span.SetAttributes(attribute.String("awsx.result", "ok"))
+	}
+
+	return v, err
+}
@@ -0,0 +1,148 @@
+package awsx
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// vpcEndpointServices are the endpoint IDs of the services UseVPCEndpoints looks for
+var vpcEndpointServices = []string{elasticache.EndpointsID, rds.EndpointsID, sts.EndpointsID}
+
+// VPCEndpoint is an available PrivateLink interface endpoint for an AWS service
+type VPCEndpoint struct {
+	Service    string // endpoint ID of the service, e.g. elasticache
+	ID         string
+	DNSName    string // regional DNS name of the interface endpoint
+	PrivateDNS bool   // the default service hostname already resolves to the endpoint
+}
+
+// URL returns the https URL of the interface endpoint
+func (v *VPCEndpoint) URL() string {
+	return "https://" + v.DNSName
+}
+
+// CurrentVPC returns the ID of the VPC of the EC2 instance from instance metadata
+func (a *Config) CurrentVPC() (string, error) {
+	client, err := a.MetadataClient()
+	if err != nil {
+		return "", err
+	}
+
+	mac, err := client.GetMetadata("mac")
+	if err != nil {
+		return "", err
+	}
+
+	return client.GetMetadata("network/interfaces/macs/" + mac + "/vpc-id")
+}
+
+// DetectVPCEndpoints returns the available interface endpoints in the VPC for each of the
+// services, keyed by endpoint ID. Services without an interface endpoint are omitted.
+func (a *Config) DetectVPCEndpoints(vpcID string, services ...string) (map[string]*VPCEndpoint, error) {
+	if vpcID == "" {
+		return nil, errors.New("no vpc id provided")
+	}
+	if len(services) == 0 {
+		services = vpcEndpointServices
+	}
+
+	if a.Service.Ec2 == nil {
+		a.SetEC2Client()
+	}
+
+	region := a.GetRegion()
+	names := make(map[string]string, len(services))
+	for _, svc := range services {
+		names["com.amazonaws."+region+"."+svc] = svc
+		if PartitionForRegion(region) == endpoints.AwsCnPartitionID {
+			// some China region endpoint services carry a cn. prefix
+			names["cn.com.amazonaws."+region+"."+svc] = svc
+		}
+	}
+	serviceNames := make([]string, 0, len(names))
+	for name := range names {
+		serviceNames = append(serviceNames, name)
+	}
+
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{vpcID})},
+			{Name: aws.String("service-name"), Values: aws.StringSlice(serviceNames)},
+			{Name: aws.String("vpc-endpoint-type"), Values: aws.StringSlice([]string{ec2.VpcEndpointTypeInterface})},
+			{Name: aws.String("vpc-endpoint-state"), Values: aws.StringSlice([]string{"available"})},
+		},
+	}
+
+	found := map[string]*VPCEndpoint{}
+	err := a.Service.Ec2.DescribeVpcEndpointsPages(input, func(page *ec2.DescribeVpcEndpointsOutput, lastPage bool) bool {
+		for _, e := range page.VpcEndpoints {
+			svc, ok := names[aws.StringValue(e.ServiceName)]
+			if !ok || len(e.DnsEntries) == 0 {
+				continue
+			}
+			if _, dup := found[svc]; dup {
+				continue
+			}
+			found[svc] = &VPCEndpoint{
+				Service:    svc,
+				ID:         aws.StringValue(e.VpcEndpointId),
+				DNSName:    regionalDNSName(e.DnsEntries),
+				PrivateDNS: aws.BoolValue(e.PrivateDnsEnabled),
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// UseVPCEndpoints detects the interface endpoints for ElastiCache, RDS and STS (or the
+// given services) in the VPC of the instance and routes calls to them. Endpoints with
+// private DNS enabled need no change, and services with an endpoint already set with
+// SetServiceEndpoint keep it, so explicit configuration always overrides detection. It
+// must be called before the service clients are created.
+func (a *Config) UseVPCEndpoints(services ...string) (map[string]*VPCEndpoint, error) {
+	vpcID, err := a.CurrentVPC()
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := a.DetectVPCEndpoints(vpcID, services...)
+	if err != nil {
+		return nil, err
+	}
+
+	for svc, e := range found {
+		if e.PrivateDNS {
+			continue
+		}
+		if _, ok := a.ServiceEndpoints[svc]; ok {
+			continue
+		}
+		a.SetServiceEndpoint(svc, e.URL())
+	}
+
+	return found, nil
+}
+
+// regionalDNSName picks the regional entry from the DNS entries of an interface endpoint,
+// which unlike the zonal entries does not contain an availability zone suffix
+func regionalDNSName(entries []*ec2.DnsEntry) string {
+	for _, d := range entries {
+		name := aws.StringValue(d.DnsName)
+		if strings.HasPrefix(name, "vpce-") && strings.Count(strings.SplitN(name, ".", 2)[0], "-") == 2 {
+			return name
+		}
+	}
+	return aws.StringValue(entries[0].DnsName)
+}
@@ -0,0 +1,334 @@
+package awsx
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval is the poll interval used by watchers created with a zero interval
+const defaultWatchInterval = 30 * time.Second
+
+// defaultSubscriptionBuffer is the channel buffer used when SubscribeOptions.Buffer is zero
+const defaultSubscriptionBuffer = 8
+
+// OverflowPolicy controls what happens when an event is published to a subscriber
+// whose buffer is full
+type OverflowPolicy int
+
+const (
+	// Coalesce discards every pending event so the subscriber only receives the latest
+	// topology, which is the default since each event carries the full topology
+	Coalesce OverflowPolicy = iota
+	// DropOldest discards the oldest pending event to make room for the new one
+	DropOldest
+	// DropNewest discards the new event and keeps the pending events
+	DropNewest
+)
+
+// TopologyEvent is published by a Watcher when the discovered endpoints of a cluster
+// change or discovery starts failing. Only one of Redis or Aurora is set, and Err is
+// set instead when discovery failed. Switchover is set when a blue/green deployment
+// watched with WatchBlueGreen moved production to another environment.
+type TopologyEvent struct {
+	Seq        uint64
+	Cluster    string
+	Time       time.Time
+	Redis      *RedisEndpoints
+	Aurora     *AuroraEndpoints
+	Err        error
+	Switchover bool
+}
+
+// SubscribeOptions configures a Subscription
+type SubscribeOptions struct {
+	Buffer int            // channel buffer size, defaults to 8
+	Policy OverflowPolicy // what to do when the buffer is full, defaults to Coalesce
+}
+
+// SubscriptionStats reports how well a subscriber keeps up with its watcher
+type SubscriptionStats struct {
+	Delivered  uint64 // events placed on the channel
+	Dropped    uint64 // events discarded by DropOldest or DropNewest
+	Coalesced  uint64 // pending events replaced by a newer event
+	Pending    int    // events buffered but not yet received
+	MaxPending int    // highest number of buffered events seen, how far behind the subscriber has been
+}
+
+// Subscription receives TopologyEvents from a Watcher on C
+type Subscription struct {
+	C <-chan TopologyEvent
+
+	ch      chan TopologyEvent
+	policy  OverflowPolicy
+	watcher *Watcher
+
+	mu        sync.Mutex
+	delivered uint64
+	dropped   uint64
+	coalesced uint64
+	maxPend   int
+	closed    bool
+}
+
+// Watcher polls discovery for a cluster and fans out TopologyEvents to its subscribers.
+// Every subscriber has its own buffered channel so a slow consumer never delays the
+// others; the subscriber's OverflowPolicy decides which events it loses instead.
+type Watcher struct {
+	Cluster  string
+	Interval time.Duration
+
+	config  *Config
+	resolve func() (*RedisEndpoints, *AuroraEndpoints, error)
+
+	mu      sync.Mutex
+	subs    []*Subscription
+	seq     uint64
+	last    string
+	lastErr string
+	lastBG  string // production ARN of a watched blue/green deployment
+	polled  time.Time
+	changed time.Time
+	stop    chan struct{}
+	done    chan struct{}
+	running bool
+}
+
+// WatchRedis returns a Watcher polling GetRedisPrimaryEndpoint for the cluster
+func (a *Config) WatchRedis(cluster string, interval time.Duration) *Watcher {
+	return a.newWatcher(cluster, interval, func() (*RedisEndpoints, *AuroraEndpoints, error) {
+		res, err := a.GetRedisPrimaryEndpoint(cluster)
+		return res, nil, err
+	})
+}
+
+// WatchAurora returns a Watcher polling GetAuroraEndpoints for the cluster
+func (a *Config) WatchAurora(cluster string, interval time.Duration) *Watcher {
+	return a.newWatcher(cluster, interval, func() (*RedisEndpoints, *AuroraEndpoints, error) {
+		aes, err := a.GetAuroraEndpoints(cluster)
+		return nil, aes, err
+	})
+}
+
+func (a *Config) newWatcher(cluster string, interval time.Duration, resolve func() (*RedisEndpoints, *AuroraEndpoints, error)) *Watcher {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return &Watcher{
+		Cluster:  cluster,
+		Interval: interval,
+		config:   a,
+		resolve:  resolve,
+	}
+}
+
+// Subscribe attaches a new subscriber to the watcher
+func (w *Watcher) Subscribe(opts SubscribeOptions) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = defaultSubscriptionBuffer
+	}
+
+	ch := make(chan TopologyEvent, opts.Buffer)
+	sub := &Subscription{C: ch, ch: ch, policy: opts.Policy, watcher: w}
+
+	w.mu.Lock()
+	w.subs = append(w.subs, sub)
+	w.mu.Unlock()
+
+	return sub
+}
+
+// Start begins polling in a background goroutine. The first poll happens immediately.
+func (w *Watcher) Start() *Watcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running {
+		return w
+	}
+	w.running = true
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+	w.config.status().addWatcher(w)
+
+	return w
+}
+
+// Stop ends polling and closes every subscription channel. A poll that is in progress
+// is allowed to finish first; Stop returns once the watcher has stopped.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	close(w.stop)
+	done := w.done
+	w.mu.Unlock()
+	<-done
+
+	w.mu.Lock()
+	subs := w.subs
+	w.subs = nil
+	w.mu.Unlock()
+	w.config.status().removeWatcher(w)
+
+	for _, s := range subs {
+		s.close()
+	}
+}
+
+// Poll runs discovery once and publishes an event if the topology changed
+func (w *Watcher) Poll() {
+	res, aes, err := w.resolve()
+	now := w.config.clock().Now()
+
+	w.mu.Lock()
+	w.polled = now
+	if err != nil {
+		if err.Error() == w.lastErr {
+			w.mu.Unlock()
+			return
+		}
+		w.lastErr = err.Error()
+	} else {
+		fingerprint := ""
+		if res != nil {
+			fingerprint = res.String()
+		} else if aes != nil {
+			fingerprint = aes.String()
+		}
+		if fingerprint == w.last && w.lastErr == "" {
+			w.mu.Unlock()
+			return
+		}
+		w.last = fingerprint
+		w.lastErr = ""
+	}
+
+	switchover := false
+	if err == nil && aes != nil && aes.BlueGreen != nil {
+		switchover = w.lastBG != "" && w.lastBG != aes.BlueGreen.Production
+		w.lastBG = aes.BlueGreen.Production
+	}
+
+	w.seq++
+	w.changed = now
+	ev := TopologyEvent{
+		Seq:        w.seq,
+		Cluster:    w.Cluster,
+		Time:       now,
+		Redis:      res,
+		Aurora:     aes,
+		Err:        err,
+		Switchover: switchover,
+	}
+	subs := append([]*Subscription(nil), w.subs...)
+	w.mu.Unlock()
+
+	for _, s := range subs {
+		s.publish(ev)
+	}
+}
+
+func (w *Watcher) run(stop, done chan struct{}) {
+	defer close(done)
+	for {
+		w.Poll()
+		if !w.config.wait(w.Interval, stop) {
+			return
+		}
+	}
+}
+
+// Stats returns the delivery statistics of the subscription
+func (s *Subscription) Stats() SubscriptionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SubscriptionStats{
+		Delivered:  s.delivered,
+		Dropped:    s.dropped,
+		Coalesced:  s.coalesced,
+		Pending:    len(s.ch),
+		MaxPending: s.maxPend,
+	}
+}
+
+// Close detaches the subscription from its watcher and closes C
+func (s *Subscription) Close() {
+	w := s.watcher
+	w.mu.Lock()
+	for i, sub := range w.subs {
+		if sub == s {
+			w.subs = append(w.subs[:i], w.subs[i+1:]...)
+			break
+		}
+	}
+	w.mu.Unlock()
+
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// publish places the event on the channel without blocking, applying the overflow
+// policy when the buffer is full. Only the watcher sends on the channel, so draining
+// pending events before sending cannot race with another sender.
+func (s *Subscription) publish(ev TopologyEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	defer func() {
+		if n := len(s.ch); n > s.maxPend {
+			s.maxPend = n
+		}
+	}()
+
+	select {
+	case s.ch <- ev:
+		s.delivered++
+		return
+	default:
+	}
+
+	switch s.policy {
+	case DropNewest:
+		s.dropped++
+		return
+	case DropOldest:
+		select {
+		case <-s.ch:
+			s.dropped++
+		default:
+		}
+	default:
+		for drained := false; !drained; {
+			select {
+			case <-s.ch:
+				s.coalesced++
+			default:
+				drained = true
+			}
+		}
+	}
+
+	select {
+	case s.ch <- ev:
+		s.delivered++
+	default:
+		s.dropped++
+	}
+}
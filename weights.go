@@ -0,0 +1,139 @@
+package awsx
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minWeight is the weight of a node with no headroom left, so it still gets a trickle of
+// connections and its load can be observed to drop
+const minWeight = 0.05
+
+// NodeLoad is the latest CloudWatch utilization of an ElastiCache node
+type NodeLoad struct {
+	CPU     float64   `json:"cpu" yaml:"cpu"`         // EngineCPUUtilization, or CPUUtilization for nodes without it, in percent
+	Memory  float64   `json:"memory" yaml:"memory"`   // DatabaseMemoryUsagePercentage
+	Fetched time.Time `json:"fetched" yaml:"fetched"` // when the metrics were looked up
+}
+
+// GetRedisNodeLoad returns the latest CPU and memory utilization of each ElastiCache cache
+// cluster (node) ID. Nodes without a recent datapoint are left out.
+func (a *Config) GetRedisNodeLoad(cacheClusterIDs []string) (map[string]*NodeLoad, error) {
+	engineCPU, err := a.latestMetricValues("AWS/ElastiCache", "EngineCPUUtilization", "CacheClusterId", cacheClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+	hostCPU, err := a.latestMetricValues("AWS/ElastiCache", "CPUUtilization", "CacheClusterId", cacheClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+	memory, err := a.latestMetricValues("AWS/ElastiCache", "DatabaseMemoryUsagePercentage", "CacheClusterId", cacheClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.clock().Now()
+	load := make(map[string]*NodeLoad, len(cacheClusterIDs))
+	for _, id := range cacheClusterIDs {
+		cpu, ok := engineCPU[id]
+		if !ok {
+			cpu, ok = hostCPU[id]
+		}
+		mem, memOK := memory[id]
+		if !ok && !memOK {
+			continue
+		}
+		load[id] = &NodeLoad{CPU: cpu, Memory: mem, Fetched: now}
+	}
+	return load, nil
+}
+
+// AddRedisNodeLoad looks up the utilization of the read endpoints and sets their Load,
+// so client-side balancers can weight nodes by their headroom, see Weight
+func (a *Config) AddRedisNodeLoad(res *RedisEndpoints) error {
+	ids := make([]string, 0, len(res.ReadEndpoints))
+	for _, re := range res.ReadEndpoints {
+		if re.CacheClusterID != "" {
+			ids = append(ids, re.CacheClusterID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	load, err := a.GetRedisNodeLoad(ids)
+	if err != nil {
+		return err
+	}
+	for _, re := range res.ReadEndpoints {
+		if l, ok := load[re.CacheClusterID]; ok {
+			re.Load = l
+		}
+	}
+	return nil
+}
+
+// Weight returns a load hint for the node relative to other nodes: the approximate vCPU
+// count of its NodeType, scaled down by its CPU utilization when Load is set. Nodes of an
+// unknown type weigh 1.
+func (re *RedisEndpoint) Weight() float64 {
+	w := nodeTypeCapacity(re.NodeType)
+	if re.Load != nil {
+		headroom := 1 - re.Load.CPU/100
+		if headroom < minWeight {
+			headroom = minWeight
+		}
+		w *= headroom
+	}
+	return w
+}
+
+// nodeTypeCapacity returns the approximate vCPU count of an ElastiCache node type such as
+// cache.r6g.2xlarge, or 1 when the size is not recognized
+func nodeTypeCapacity(nodeType string) float64 {
+	parts := strings.Split(nodeType, ".")
+	size := parts[len(parts)-1]
+
+	switch size {
+	case "micro", "small", "medium":
+		return 1
+	case "large":
+		return 2
+	case "xlarge":
+		return 4
+	}
+	if strings.HasSuffix(size, "xlarge") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge")); err == nil && n > 0 {
+			return float64(4 * n)
+		}
+	}
+	return 1
+}
+
+// Weighted returns a strategy picking a reader at random in proportion to its Weight, so
+// larger and less loaded nodes receive more connections
+func Weighted() ReaderStrategy {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return ReaderStrategyFunc(func(readers []*RedisEndpoint) *RedisEndpoint {
+		total := 0.0
+		for _, re := range readers {
+			total += re.Weight()
+		}
+
+		mu.Lock()
+		pick := r.Float64() * total
+		mu.Unlock()
+
+		for _, re := range readers {
+			pick -= re.Weight()
+			if pick < 0 {
+				return re
+			}
+		}
+		return readers[len(readers)-1]
+	})
+}